@@ -0,0 +1,137 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// mcpCORSHeaders are the request headers browser clients may send to /mcp
+var mcpCORSHeaders = []string{
+	"Accept", "Authorization", "Content-Type", "Last-Event-ID", "Mcp-Protocol-Version", "Mcp-Session-Id",
+	"X-API-Key", "X-Atlassian-Email", "X-Atlassian-Token", "X-Jira-Session",
+}
+
+// httpSecurity protects the /mcp endpoint of the HTTP server
+type httpSecurity struct {
+	// BearerTokens are accepted in the Authorization header
+	BearerTokens []string
+	// APIKeys are accepted in the X-API-Key header
+	APIKeys []string
+	// AllowedOrigins are the browser origins that may call the server; "*" allows any.
+	// Without any, only pages served from localhost may.
+	AllowedOrigins []string
+}
+
+// httpSecurityConfig reads JIRA_MCP_HTTP_BEARER_TOKENS, JIRA_MCP_HTTP_API_KEYS and JIRA_MCP_HTTP_ALLOWED_ORIGINS,
+// each a comma-separated list
+func httpSecurityConfig() httpSecurity {
+	return httpSecurity{
+		BearerTokens:   splitEnvList("JIRA_MCP_HTTP_BEARER_TOKENS"),
+		APIKeys:        splitEnvList("JIRA_MCP_HTTP_API_KEYS"),
+		AllowedOrigins: splitEnvList("JIRA_MCP_HTTP_ALLOWED_ORIGINS"),
+	}
+}
+
+func splitEnvList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// requiresAuth reports whether clients have to present a token or key
+func (h httpSecurity) requiresAuth() bool {
+	return len(h.BearerTokens) > 0 || len(h.APIKeys) > 0
+}
+
+// protect checks the origin, answers CORS preflights and authenticates requests before they reach next
+func (h httpSecurity) protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Checking the origin keeps web pages, including DNS rebinding attacks, from driving a local server
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if !h.allowsOrigin(origin) {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
+			w.Header().Add("Vary", "Origin")
+
+			if r.Method == http.MethodOptions {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(mcpCORSHeaders, ", "))
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+
+		if !h.authenticated(r) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="jira-mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h httpSecurity) authenticated(r *http.Request) bool {
+	if !h.requiresAuth() {
+		return true
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && containsSecret(h.BearerTokens, strings.TrimSpace(token)) {
+		return true
+	}
+	if key := r.Header.Get("X-API-Key"); key != "" && containsSecret(h.APIKeys, key) {
+		return true
+	}
+	return false
+}
+
+// containsSecret compares in constant time, so response times do not reveal how much of a token matched
+func containsSecret(secrets []string, value string) bool {
+	found := false
+	for _, secret := range secrets {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(value)) == 1 {
+			found = true
+		}
+	}
+	return found
+}
+
+func (h httpSecurity) allowsOrigin(origin string) bool {
+	origin = strings.TrimSuffix(origin, "/")
+	if len(h.AllowedOrigins) == 0 {
+		parsed, err := url.Parse(origin)
+		return err == nil && isLoopbackHost(parsed.Hostname())
+	}
+	for _, allowed := range h.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLoopbackHost reports whether host only accepts connections from this machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handleHealth answers load balancer and container health checks without authentication
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
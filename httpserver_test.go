@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSecurityAuthenticates(t *testing.T) {
+	security := httpSecurity{BearerTokens: []string{"token-1"}, APIKeys: []string{"key-1"}}
+	handler := security.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, tt := range []struct {
+		header, value string
+		want          int
+	}{
+		{"", "", http.StatusUnauthorized},
+		{"Authorization", "Bearer token-1", http.StatusOK},
+		{"Authorization", "Bearer token-2", http.StatusUnauthorized},
+		{"Authorization", "Basic token-1", http.StatusUnauthorized},
+		{"X-API-Key", "key-1", http.StatusOK},
+		{"X-API-Key", "token-1", http.StatusUnauthorized},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if tt.header != "" {
+			r.Header.Set(tt.header, tt.value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("%s: %q got %d, want %d", tt.header, tt.value, w.Code, tt.want)
+		}
+	}
+
+	open := httpSecurity{}.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	w := httptest.NewRecorder()
+	open.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/mcp", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("without tokens: %d", w.Code)
+	}
+}
+
+func TestHTTPSecurityChecksOrigin(t *testing.T) {
+	handler := func(security httpSecurity) http.Handler {
+		return security.protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	}
+	call := func(h http.Handler, method, origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/mcp", nil)
+		r.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	local := handler(httpSecurity{})
+	if w := call(local, http.MethodPost, "http://localhost:5173"); w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "http://localhost:5173" {
+		t.Errorf("localhost origin: %d %v", w.Code, w.Header())
+	}
+	if w := call(local, http.MethodPost, "https://evil.example.com"); w.Code != http.StatusForbidden {
+		t.Errorf("foreign origin: %d", w.Code)
+	}
+
+	// Preflights carry no credentials, so they are answered before authentication
+	listed := handler(httpSecurity{BearerTokens: []string{"token"}, AllowedOrigins: []string{"https://app.example.com/"}})
+	w := call(listed, http.MethodOptions, "https://app.example.com")
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Headers") == "" {
+		t.Errorf("preflight: %d %v", w.Code, w.Header())
+	}
+	if w := call(listed, http.MethodPost, "https://app.example.com"); w.Code != http.StatusUnauthorized {
+		t.Errorf("allowed origin without a token: %d", w.Code)
+	}
+	if w := call(listed, http.MethodOptions, "http://localhost"); w.Code != http.StatusForbidden {
+		t.Errorf("origin outside of the list: %d", w.Code)
+	}
+
+	if w := call(handler(httpSecurity{AllowedOrigins: []string{"*"}}), http.MethodPost, "https://any.example.com"); w.Code != http.StatusOK {
+		t.Errorf("wildcard: %d", w.Code)
+	}
+}
+
+func TestIsLoopbackHost(t *testing.T) {
+	for host, want := range map[string]bool{"localhost": true, "127.0.0.1": true, "::1": true, "": false, "0.0.0.0": false, "example.com": false} {
+		if got := isLoopbackHost(host); got != want {
+			t.Errorf("isLoopbackHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
@@ -6,18 +6,33 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/prompts"
+	"github.com/nguyenvanduocit/jira-mcp/services"
 	"github.com/nguyenvanduocit/jira-mcp/tools"
 )
 
 func main() {
 	envFile := flag.String("env", "", "Path to environment file (optional when environment variables are set directly)")
 	httpPort := flag.String("http_port", "", "Port for HTTP server. If not provided, will use stdio")
+	httpHost := flag.String("http_host", "", "Address the HTTP server binds to, e.g. 127.0.0.1 (default: all interfaces, or set JIRA_MCP_HTTP_HOST)")
+	tlsCert := flag.String("tls_cert", "", "TLS certificate file to serve HTTPS (or set JIRA_MCP_TLS_CERT)")
+	tlsKey := flag.String("tls_key", "", "TLS key file to serve HTTPS (or set JIRA_MCP_TLS_KEY)")
+	confirmDestructive := flag.Bool("confirm-destructive", false, "Require a preview and confirmation token before delete, bulk and transition tools act (or set JIRA_MCP_CONFIRM_DESTRUCTIVE=true)")
+	policyFile := flag.String("policy-file", "", "Path to a YAML or JSON write policy limiting changes to some projects and issue types (or set JIRA_MCP_POLICY_FILE)")
+	auditLogPath := flag.String("audit-log", "", "Path of the JSONL audit log of mutating tool calls (or set JIRA_MCP_AUDIT_LOG)")
+	oauthLogin := flag.Bool("oauth-login", false, "Authorize the OAuth app in ATLASSIAN_OAUTH_CLIENT_ID in the browser, store its token and exit")
+	perUserCredentials := flag.Bool("per-user-credentials", false, "In HTTP mode, act with the Jira credentials each session sends instead of the server's (or set JIRA_MCP_PER_USER_CREDENTIALS=true)")
+	readOnly := flag.Bool("read-only", false, "Only register tools that do not change Jira (or set JIRA_MCP_READ_ONLY=true)")
 	flag.Parse()
 
 	// Load environment file if specified
@@ -29,8 +44,18 @@ func main() {
 		}
 	}
 
+	*perUserCredentials = *perUserCredentials || isTruthy(os.Getenv("JIRA_MCP_PER_USER_CREDENTIALS"))
+	if *perUserCredentials && *httpPort == "" {
+		fmt.Fprintln(os.Stderr, "❌ Configuration Error: per-user credentials need HTTP mode (--http_port)")
+		os.Exit(1)
+	}
+
 	// Check required environment variables
-	requiredEnvs := []string{"ATLASSIAN_HOST", "ATLASSIAN_EMAIL", "ATLASSIAN_TOKEN"}
+	requiredEnvs := services.RequiredEnv()
+	if *perUserCredentials {
+		// Users bring their own credentials, the server only needs to know the site
+		requiredEnvs = []string{"ATLASSIAN_HOST"}
+	}
 	missingEnvs := []string{}
 	for _, env := range requiredEnvs {
 		if os.Getenv(env) == "" {
@@ -73,14 +98,124 @@ func main() {
 	fmt.Println("✅ All required environment variables are set")
 	fmt.Printf("🔗 Connected to: %s\n", os.Getenv("ATLASSIAN_HOST"))
 
-	mcpServer := server.NewMCPServer(
-		"Jira MCP",
-		"1.0.1",
+	httpConfig, err := httpClientConfig()
+	if err == nil {
+		err = services.SetHTTPConfig(httpConfig)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+		os.Exit(1)
+	}
+	if httpConfig.ProxyURL != "" {
+		fmt.Fprintln(os.Stderr, "🌐 Connecting through the proxy in PROXY_URL")
+	}
+	if httpConfig.InsecureSkipVerify {
+		fmt.Fprintln(os.Stderr, "⚠️  Warning: TLS certificates are not verified (JIRA_MCP_INSECURE_SKIP_VERIFY)")
+	}
+
+	deployment, err := services.ParseDeployment(os.Getenv("JIRA_DEPLOYMENT"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+		os.Exit(1)
+	}
+	if deployment == services.DeploymentServer {
+		fmt.Fprintln(os.Stderr, "🏢 Using the Jira Server / Data Center REST API (JIRA_DEPLOYMENT)")
+	}
+
+	config, err := retryConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+		os.Exit(1)
+	}
+	services.SetRetryConfig(config)
+
+	if *oauthLogin {
+		config, err := services.OAuthConfigFromEnv()
+		if err == nil {
+			err = services.OAuthLogin(context.Background(), config)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ OAuth login failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var userCredentials *services.UserCredentials
+	if *perUserCredentials {
+		config, err := userCredentialsConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+			os.Exit(1)
+		}
+		userCredentials = services.NewUserCredentials(config)
+		fmt.Fprintln(os.Stderr, "👥 Each HTTP session acts with its own Jira credentials")
+	} else {
+		if _, err := services.DefaultCredentials(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "🔑 Authenticating with %s\n", authDescription(services.AuthType()))
+	}
+
+	if *confirmDestructive || isTruthy(os.Getenv("JIRA_MCP_CONFIRM_DESTRUCTIVE")) {
+		tools.SetConfirmDestructive(true)
+		fmt.Fprintln(os.Stderr, "🛡️  Destructive actions require confirmation")
+	}
+
+	toolPolicy := tools.ToolPolicy{
+		ReadOnly: *readOnly || isTruthy(os.Getenv("JIRA_MCP_READ_ONLY")),
+		Enabled:  tools.ParseToolPatterns(os.Getenv("JIRA_MCP_ENABLED_TOOLS")),
+		Disabled: tools.ParseToolPatterns(os.Getenv("JIRA_MCP_DISABLED_TOOLS")),
+	}
+	if err := tools.SetToolPolicy(toolPolicy); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+		os.Exit(1)
+	}
+	if toolPolicy.ReadOnly {
+		fmt.Fprintln(os.Stderr, "🔒 Read-only mode: tools that change Jira are not registered")
+	}
+
+	if *policyFile == "" {
+		*policyFile = os.Getenv("JIRA_MCP_POLICY_FILE")
+	}
+	if *policyFile != "" {
+		if err := tools.LoadWritePolicy(*policyFile); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "📜 Loaded write policy from %s\n", *policyFile)
+	}
+
+	if *auditLogPath == "" {
+		*auditLogPath = os.Getenv("JIRA_MCP_AUDIT_LOG")
+	}
+	if *auditLogPath != "" {
+		config, err := auditLogConfig(*auditLogPath)
+		if err == nil {
+			err = tools.OpenAuditLog(config)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Configuration Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Auditing changes to %s\n", *auditLogPath)
+	}
+
+	serverOptions := []server.ServerOption{
 		server.WithLogging(),
 		server.WithPromptCapabilities(true),
 		server.WithResourceCapabilities(true, true),
 		server.WithRecovery(),
+	}
+	if userCredentials != nil {
+		serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(tools.RequireUserCredentials))
+	}
+	serverOptions = append(serverOptions,
+		server.WithToolHandlerMiddleware(tools.ValidateOutputFormat),
+		server.WithToolHandlerMiddleware(tools.AuditMutations),
 	)
+	mcpServer := server.NewMCPServer("Jira MCP", "1.0.1", serverOptions...)
 
 	// Register all Jira tools
 	tools.RegisterJiraIssueTool(mcpServer)
@@ -95,14 +230,52 @@ func main() {
 	tools.RegisterJiraVersionTool(mcpServer)
 	tools.RegisterJiraDevelopmentTool(mcpServer)
 	tools.RegisterJiraAttachmentTool(mcpServer)
+	tools.RegisterJiraFieldTool(mcpServer)
+	tools.RegisterJiraUserTool(mcpServer)
+	tools.RegisterJiraProjectTool(mcpServer)
+	tools.RegisterJiraCreateMetadataTool(mcpServer)
+	tools.RegisterJiraBulkCreateTool(mcpServer)
+	tools.RegisterJiraBulkUpdateTool(mcpServer)
+	tools.RegisterJiraPolicyTool(mcpServer)
+	tools.RegisterJiraUndoTool(mcpServer)
+	fmt.Fprintf(os.Stderr, "🧰 Registered %d tools\n", len(mcpServer.ListTools()))
 
 	// Register all Jira prompts
 	prompts.RegisterJiraPrompts(mcpServer)
 
 	if *httpPort != "" {
+		if *httpHost == "" {
+			*httpHost = os.Getenv("JIRA_MCP_HTTP_HOST")
+		}
+		if *tlsCert == "" {
+			*tlsCert = os.Getenv("JIRA_MCP_TLS_CERT")
+		}
+		if *tlsKey == "" {
+			*tlsKey = os.Getenv("JIRA_MCP_TLS_KEY")
+		}
+		if (*tlsCert == "") != (*tlsKey == "") {
+			fmt.Println("❌ Configuration Error: HTTPS needs both a TLS certificate and a key")
+			os.Exit(1)
+		}
+		scheme, displayHost := "http", "localhost"
+		if *tlsCert != "" {
+			scheme = "https"
+		}
+		if *httpHost != "" {
+			displayHost = *httpHost
+		}
+		serverURL := fmt.Sprintf("%s://%s/mcp", scheme, net.JoinHostPort(displayHost, *httpPort))
+
+		security := httpSecurityConfig()
+
 		fmt.Println()
 		fmt.Println("🚀 Starting Jira MCP Server in HTTP mode...")
-		fmt.Printf("📡 Server will be available at: http://localhost:%s/mcp\n", *httpPort)
+		fmt.Printf("📡 Server will be available at: %s\n", serverURL)
+		if security.requiresAuth() {
+			fmt.Println("🔐 Clients must send a bearer token or API key")
+		} else if !isLoopbackHost(*httpHost) {
+			fmt.Println("⚠️  Warning: anyone who can reach this port can use the server; set JIRA_MCP_HTTP_BEARER_TOKENS or bind to 127.0.0.1 with --http_host")
+		}
 		fmt.Println()
 		fmt.Println("📋 Cursor Configuration:")
 		fmt.Println("Add the following to your Cursor MCP settings (.cursor/mcp.json):")
@@ -111,7 +284,12 @@ func main() {
 		fmt.Println("{")
 		fmt.Println("  \"mcpServers\": {")
 		fmt.Println("    \"jira\": {")
-		fmt.Printf("      \"url\": \"http://localhost:%s/mcp\"\n", *httpPort)
+		if security.requiresAuth() {
+			fmt.Printf("      \"url\": \"%s\",\n", serverURL)
+			fmt.Println("      \"headers\": { \"Authorization\": \"Bearer <your token>\" }")
+		} else {
+			fmt.Printf("      \"url\": \"%s\"\n", serverURL)
+		}
 		fmt.Println("    }")
 		fmt.Println("  }")
 		fmt.Println("}")
@@ -124,8 +302,31 @@ func main() {
 		fmt.Println()
 		fmt.Println("🔄 Server starting...")
 		
-		httpServer := server.NewStreamableHTTPServer(mcpServer, server.WithEndpointPath("/mcp"))
-		if err := httpServer.Start(fmt.Sprintf(":%s", *httpPort)); err != nil && !isContextCanceled(err) {
+		mux := http.NewServeMux()
+		httpOptions := []server.StreamableHTTPOption{
+			server.WithEndpointPath("/mcp"),
+			// No WriteTimeout: streamed responses stay open for as long as the session
+			server.WithStreamableHTTPServer(&http.Server{
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+				ReadTimeout:       30 * time.Second,
+				IdleTimeout:       120 * time.Second,
+			}),
+		}
+		if userCredentials != nil {
+			httpOptions = append(httpOptions, server.WithHTTPContextFunc(userCredentials.HTTPContext))
+			if err := userCredentials.RegisterOAuthHandlers(mux); err != nil {
+				log.Fatalf("❌ Configuration Error: %v", err)
+			}
+		}
+		if *tlsCert != "" {
+			httpOptions = append(httpOptions, server.WithTLSCert(*tlsCert, *tlsKey))
+		}
+		httpServer := server.NewStreamableHTTPServer(mcpServer, httpOptions...)
+		mux.Handle("/mcp", security.protect(httpServer))
+		mux.HandleFunc("/health", handleHealth)
+
+		if err := httpServer.Start(net.JoinHostPort(*httpHost, *httpPort)); err != nil && !isContextCanceled(err) {
 			log.Fatalf("❌ Server error: %v", err)
 		}
 	} else {
@@ -135,6 +336,153 @@ func main() {
 	}
 }
 
+// userCredentialsConfig reads the settings of per-user credentials: the site in ATLASSIAN_HOST and
+// ATLASSIAN_CLOUD_ID, the OAuth app when ATLASSIAN_OAUTH_CLIENT_ID is set, JIRA_MCP_OAUTH_SESSION_DIR
+// and JIRA_MCP_CLIENT_CACHE_SIZE (default 100)
+func userCredentialsConfig() (services.UserCredentialsConfig, error) {
+	config := services.UserCredentialsConfig{
+		Host:       os.Getenv("ATLASSIAN_HOST"),
+		CloudID:    os.Getenv("ATLASSIAN_CLOUD_ID"),
+		SessionDir: os.Getenv("JIRA_MCP_OAUTH_SESSION_DIR"),
+		CacheSize:  100,
+	}
+
+	deployment, err := services.ParseDeployment(os.Getenv("JIRA_DEPLOYMENT"))
+	if err != nil {
+		return config, err
+	}
+	config.Deployment = deployment
+
+	if os.Getenv("ATLASSIAN_OAUTH_CLIENT_ID") != "" {
+		if deployment == services.DeploymentServer {
+			return config, fmt.Errorf("OAuth 2.0 (3LO) sign in is only available on Jira Cloud, unset ATLASSIAN_OAUTH_CLIENT_ID with JIRA_DEPLOYMENT=server")
+		}
+		oauth, err := services.OAuthConfigFromEnv()
+		if err != nil {
+			return config, err
+		}
+		config.OAuth = &oauth
+		if config.SessionDir == "" {
+			config.SessionDir = filepath.Join(filepath.Dir(oauth.TokenFile), "sessions")
+		}
+	}
+
+	if value := os.Getenv("JIRA_MCP_CLIENT_CACHE_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			return config, fmt.Errorf("invalid JIRA_MCP_CLIENT_CACHE_SIZE %q: must be a number of users", value)
+		}
+		config.CacheSize = size
+	}
+
+	return config, nil
+}
+
+// httpClientConfig reads the connection settings of the Jira clients: PROXY_URL, JIRA_MCP_CA_FILE,
+// JIRA_MCP_CLIENT_CERT and JIRA_MCP_CLIENT_KEY, JIRA_MCP_INSECURE_SKIP_VERIFY and the timeouts
+// JIRA_MCP_CONNECT_TIMEOUT (default 10s) and JIRA_MCP_READ_TIMEOUT (default 1m)
+func httpClientConfig() (services.HTTPConfig, error) {
+	config := services.DefaultHTTPConfig()
+	config.ProxyURL = os.Getenv("PROXY_URL")
+	config.CAFile = os.Getenv("JIRA_MCP_CA_FILE")
+	config.ClientCertFile = os.Getenv("JIRA_MCP_CLIENT_CERT")
+	config.ClientKeyFile = os.Getenv("JIRA_MCP_CLIENT_KEY")
+	config.InsecureSkipVerify = isTruthy(os.Getenv("JIRA_MCP_INSECURE_SKIP_VERIFY"))
+
+	for name, timeout := range map[string]*time.Duration{
+		"JIRA_MCP_CONNECT_TIMEOUT": &config.ConnectTimeout,
+		"JIRA_MCP_READ_TIMEOUT":    &config.ReadTimeout,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration < 0 {
+			return config, fmt.Errorf("invalid %s %q: must be a duration such as 30s or 2m, 0 for no timeout", name, value)
+		}
+		*timeout = duration
+	}
+
+	return config, nil
+}
+
+// retryConfig reads the retry and rate limit settings of the Jira clients, JIRA_MCP_MAX_RETRIES (default 4),
+// JIRA_MCP_RATE_LIMIT in requests per second (default 10) and JIRA_MCP_RATE_BURST (default 10)
+func retryConfig() (services.RetryConfig, error) {
+	config := services.DefaultRetryConfig()
+
+	if value := os.Getenv("JIRA_MCP_MAX_RETRIES"); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return config, fmt.Errorf("invalid JIRA_MCP_MAX_RETRIES %q: must be a number of retries, 0 to never retry", value)
+		}
+		config.MaxRetries = retries
+	}
+
+	if value := os.Getenv("JIRA_MCP_RATE_LIMIT"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 {
+			return config, fmt.Errorf("invalid JIRA_MCP_RATE_LIMIT %q: must be a number of requests per second, 0 for no limit", value)
+		}
+		config.RequestsPerSecond = rate
+	}
+
+	if value := os.Getenv("JIRA_MCP_RATE_BURST"); value != "" {
+		burst, err := strconv.Atoi(value)
+		if err != nil || burst < 1 {
+			return config, fmt.Errorf("invalid JIRA_MCP_RATE_BURST %q: must be a number of requests", value)
+		}
+		config.Burst = burst
+	}
+
+	return config, nil
+}
+
+// auditLogConfig reads the rotation settings of the audit log, JIRA_MCP_AUDIT_MAX_SIZE_MB
+// (default 10) and JIRA_MCP_AUDIT_MAX_BACKUPS (default 5)
+func auditLogConfig(path string) (tools.AuditLogConfig, error) {
+	config := tools.AuditLogConfig{Path: path, MaxSizeBytes: 10 << 20, MaxBackups: 5}
+
+	if value := os.Getenv("JIRA_MCP_AUDIT_MAX_SIZE_MB"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return config, fmt.Errorf("invalid JIRA_MCP_AUDIT_MAX_SIZE_MB %q: must be a number of megabytes, 0 to never rotate", value)
+		}
+		config.MaxSizeBytes = int64(size) << 20
+	}
+
+	if value := os.Getenv("JIRA_MCP_AUDIT_MAX_BACKUPS"); value != "" {
+		backups, err := strconv.Atoi(value)
+		if err != nil || backups < 0 {
+			return config, fmt.Errorf("invalid JIRA_MCP_AUDIT_MAX_BACKUPS %q: must be a number of files", value)
+		}
+		config.MaxBackups = backups
+	}
+
+	return config, nil
+}
+
+// authDescription names an auth type for the startup output
+func authDescription(authType string) string {
+	switch authType {
+	case services.AuthTypePAT:
+		return "a personal access token"
+	case services.AuthTypeOAuth:
+		return "OAuth 2.0"
+	}
+	return "an API token"
+}
+
+// isTruthy reports whether an environment variable value turns an option on
+func isTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
 // IsContextCanceled checks if the error is related to context cancellation
 func isContextCanceled(err error) bool {
 	if err == nil {
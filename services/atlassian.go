@@ -1,35 +1,42 @@
 package services
 
 import (
+	"context"
 	"log"
-	"os"
 	"sync"
 
 	"github.com/ctreminiom/go-atlassian/jira/agile"
 	"github.com/pkg/errors"
 )
 
-func loadAtlassianCredentials() (host, mail, token string) {
-	host = os.Getenv("ATLASSIAN_HOST")
-	mail = os.Getenv("ATLASSIAN_EMAIL")
-	token = os.Getenv("ATLASSIAN_TOKEN")
-
-	if host == "" || mail == "" || token == "" {
-		log.Fatal("ATLASSIAN_HOST, ATLASSIAN_EMAIL, ATLASSIAN_TOKEN are required, please set it in MCP Config")
-	}
+// DefaultCredentials are the credentials of the server itself, read from the environment
+var DefaultCredentials = sync.OnceValues(func() (*Credentials, error) {
+	return LoadCredentials(context.Background())
+})
 
-	return host, mail, token
+// NewAgileClient creates an agile client acting with the given credentials
+func NewAgileClient(credentials *Credentials) (*agile.Client, error) {
+	return agile.New(authenticatedHttpClient(credentials.Auth), credentials.Site)
 }
 
 var AgileClient = sync.OnceValue[*agile.Client](func() *agile.Client {
-	host, mail, token := loadAtlassianCredentials()
+	credentials, err := DefaultCredentials()
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	instance, err := agile.New(nil, host)
+	instance, err := NewAgileClient(credentials)
 	if err != nil {
 		log.Fatal(errors.WithMessage(err, "failed to create agile client"))
 	}
 
-	instance.Auth.SetBasicAuth(mail, token)
-
 	return instance
 })
+
+// AgileClientFor returns the agile client of the session in ctx, or AgileClient
+func AgileClientFor(ctx context.Context) *agile.Client {
+	if clients := clientsFromContext(ctx); clients != nil {
+		return clients.Agile
+	}
+	return AgileClient()
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// AuthProvider adds credentials to every request sent to Jira
+type AuthProvider interface {
+	Authenticate(req *http.Request) error
+}
+
+// BasicAuth authenticates with an Atlassian account email and API token
+type BasicAuth struct {
+	Email string
+	Token string
+}
+
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Email, a.Token)
+	return nil
+}
+
+// BearerAuth authenticates with a Jira Data Center personal access token, or any other bearer token
+type BearerAuth struct {
+	Token string
+}
+
+func (a BearerAuth) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// Credentials are the site and authentication the Jira clients are built with.
+// For OAuth the site is the api.atlassian.com gateway of the cloud instance, not the instance URL.
+type Credentials struct {
+	Site string
+	Auth AuthProvider
+	// Deployment is DeploymentCloud or DeploymentServer, empty means cloud
+	Deployment string
+}
+
+// IsServer reports whether the site runs Jira Server or Data Center
+func (c *Credentials) IsServer() bool {
+	return c.Deployment == DeploymentServer
+}
+
+const (
+	AuthTypeBasic = "basic"
+	AuthTypePAT   = "pat"
+	AuthTypeOAuth = "oauth"
+)
+
+// AuthType returns how the server authenticates to Jira: ATLASSIAN_AUTH_TYPE, or oauth when an OAuth client
+// is configured, or basic
+func AuthType() string {
+	if authType := strings.ToLower(strings.TrimSpace(os.Getenv("ATLASSIAN_AUTH_TYPE"))); authType != "" {
+		return authType
+	}
+	if os.Getenv("ATLASSIAN_OAUTH_CLIENT_ID") != "" {
+		return AuthTypeOAuth
+	}
+	return AuthTypeBasic
+}
+
+// RequiredEnv lists the environment variables the configured AuthType needs
+func RequiredEnv() []string {
+	switch AuthType() {
+	case AuthTypePAT:
+		return []string{"ATLASSIAN_HOST", "ATLASSIAN_TOKEN"}
+	case AuthTypeOAuth:
+		return []string{"ATLASSIAN_HOST", "ATLASSIAN_OAUTH_CLIENT_ID", "ATLASSIAN_OAUTH_CLIENT_SECRET"}
+	}
+	return []string{"ATLASSIAN_HOST", "ATLASSIAN_EMAIL", "ATLASSIAN_TOKEN"}
+}
+
+// LoadCredentials reads the credentials of the server from the environment
+func LoadCredentials(ctx context.Context) (*Credentials, error) {
+	host := os.Getenv("ATLASSIAN_HOST")
+	for _, name := range RequiredEnv() {
+		if os.Getenv(name) == "" {
+			return nil, fmt.Errorf("%s are required, please set it in MCP Config", strings.Join(RequiredEnv(), ", "))
+		}
+	}
+
+	deployment, err := ParseDeployment(os.Getenv("JIRA_DEPLOYMENT"))
+	if err != nil {
+		return nil, err
+	}
+
+	switch authType := AuthType(); authType {
+	case AuthTypeBasic:
+		return &Credentials{Site: host, Auth: BasicAuth{Email: os.Getenv("ATLASSIAN_EMAIL"), Token: os.Getenv("ATLASSIAN_TOKEN")}, Deployment: deployment}, nil
+	case AuthTypePAT:
+		return &Credentials{Site: host, Auth: BearerAuth{Token: os.Getenv("ATLASSIAN_TOKEN")}, Deployment: deployment}, nil
+	case AuthTypeOAuth:
+		if deployment == DeploymentServer {
+			return nil, fmt.Errorf("OAuth 2.0 (3LO) is only available on Jira Cloud, use ATLASSIAN_AUTH_TYPE=pat or basic with JIRA_DEPLOYMENT=server")
+		}
+		config, err := OAuthConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		auth, err := NewOAuthAuth(config, FileTokenStore{Path: config.TokenFile})
+		if err != nil {
+			return nil, err
+		}
+		site, err := auth.Site(ctx, host, os.Getenv("ATLASSIAN_CLOUD_ID"))
+		if err != nil {
+			return nil, err
+		}
+		return &Credentials{Site: site, Auth: auth, Deployment: deployment}, nil
+	default:
+		return nil, fmt.Errorf("invalid ATLASSIAN_AUTH_TYPE %q: must be basic, pat or oauth", authType)
+	}
+}
+
+// authTransport authenticates requests before they reach the shared transport
+type authTransport struct {
+	next http.RoundTripper
+	auth AuthProvider
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	if err := t.auth.Authenticate(req); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
+
+// authenticatedHttpClient shares the transport, and with it the rate limit, of DefaultHttpClient
+func authenticatedHttpClient(auth AuthProvider) *http.Client {
+	return &http.Client{Transport: &authTransport{next: DefaultHttpClient().Transport, auth: auth}}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthProviders(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		auth AuthProvider
+		want string
+	}{
+		{BasicAuth{Email: "bot@example.com", Token: "secret"}, "Basic Ym90QGV4YW1wbGUuY29tOnNlY3JldA=="},
+		{BearerAuth{Token: "pat"}, "Bearer pat"},
+	} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := authenticatedHttpClient(tt.auth).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if authorization != tt.want {
+			t.Errorf("Authorization = %q, want %q", authorization, tt.want)
+		}
+		if req.Header.Get("Authorization") != "" {
+			t.Error("the caller's request was modified")
+		}
+	}
+}
+
+func TestLoadCredentials(t *testing.T) {
+	t.Setenv("ATLASSIAN_HOST", "https://jira.example.com")
+	t.Setenv("ATLASSIAN_EMAIL", "")
+	t.Setenv("ATLASSIAN_TOKEN", "pat")
+	t.Setenv("ATLASSIAN_OAUTH_CLIENT_ID", "")
+	t.Setenv("ATLASSIAN_AUTH_TYPE", "")
+
+	if _, err := LoadCredentials(context.Background()); err == nil || !strings.Contains(err.Error(), "ATLASSIAN_EMAIL") {
+		t.Errorf("basic auth without an email: %v", err)
+	}
+
+	t.Setenv("ATLASSIAN_AUTH_TYPE", "PAT")
+	credentials, err := LoadCredentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.Site != "https://jira.example.com" || credentials.Auth != (BearerAuth{Token: "pat"}) {
+		t.Errorf("credentials = %+v", credentials)
+	}
+
+	t.Setenv("ATLASSIAN_AUTH_TYPE", "kerberos")
+	if _, err := LoadCredentials(context.Background()); err == nil {
+		t.Error("expected an error for an unknown auth type")
+	}
+}
+
+func TestOAuthRefreshesAndStoresToken(t *testing.T) {
+	var grants []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			grant := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&grant)
+			grants = append(grants, grant)
+			json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-2", "refresh_token": "refresh-2", "expires_in": 3600})
+		case "/resources":
+			if r.Header.Get("Authorization") != "Bearer access-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`[{"id": "other", "url": "https://other.atlassian.net"}, {"id": "cloud-1", "url": "https://acme.atlassian.net"}]`))
+		}
+	}))
+	defer server.Close()
+
+	store := FileTokenStore{Path: filepath.Join(t.TempDir(), "jira-mcp", "token.json")}
+	auth := newOAuthAuth(OAuthConfig{ClientID: "id", ClientSecret: "secret"}, store, &OAuthToken{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: time.Now()})
+	auth.tokenURL = server.URL + "/oauth/token"
+	auth.resourcesURL = server.URL + "/resources"
+
+	site, err := auth.Site(context.Background(), "https://acme.atlassian.net/", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if site != "https://api.atlassian.com/ex/jira/cloud-1" {
+		t.Errorf("site = %q", site)
+	}
+
+	if len(grants) != 1 || grants[0]["grant_type"] != "refresh_token" || grants[0]["refresh_token"] != "refresh-1" || grants[0]["client_secret"] != "secret" {
+		t.Errorf("grants = %v", grants)
+	}
+
+	stored, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.AccessToken != "access-2" || stored.RefreshToken != "refresh-2" {
+		t.Errorf("stored token = %+v", stored)
+	}
+
+	// A valid token is not refreshed again
+	if token, err := auth.AccessToken(context.Background()); err != nil || token != "access-2" || len(grants) != 1 {
+		t.Errorf("token %q, %v after %d grants", token, err, len(grants))
+	}
+
+	if _, err := auth.Site(context.Background(), "https://unknown.atlassian.net", ""); err == nil || !strings.Contains(err.Error(), "https://acme.atlassian.net") {
+		t.Errorf("unknown site: %v", err)
+	}
+}
+
+func TestNewOAuthAuthNeedsToken(t *testing.T) {
+	t.Setenv("ATLASSIAN_OAUTH_REFRESH_TOKEN", "")
+	config := OAuthConfig{TokenFile: filepath.Join(t.TempDir(), "token.json")}
+	if _, err := NewOAuthAuth(config, FileTokenStore{Path: config.TokenFile}); err == nil || !strings.Contains(err.Error(), "--oauth-login") {
+		t.Errorf("expected a hint to log in: %v", err)
+	}
+
+	t.Setenv("ATLASSIAN_OAUTH_REFRESH_TOKEN", "seed")
+	auth, err := NewOAuthAuth(config, FileTokenStore{Path: config.TokenFile})
+	if err != nil || auth.token.RefreshToken != "seed" {
+		t.Errorf("seeded token: %+v, %v", auth, err)
+	}
+}
+
+func TestJiraClientFor(t *testing.T) {
+	clients, err := NewClients(&Credentials{Site: "https://jira.example.com", Auth: BearerAuth{Token: "pat"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := ContextWithClients(context.Background(), clients)
+	if JiraClientFor(ctx) != clients.Jira || AgileClientFor(ctx) != clients.Agile {
+		t.Error("the session's clients are not used")
+	}
+}
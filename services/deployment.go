@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	v2 "github.com/ctreminiom/go-atlassian/jira/v2"
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+)
+
+// Deployments a Jira site can run on
+const (
+	DeploymentCloud  = "cloud"
+	DeploymentServer = "server"
+)
+
+// ParseDeployment reads the value of JIRA_DEPLOYMENT: cloud (default), or server for Jira Server and
+// Data Center ("datacenter" and "dc" are accepted too)
+func ParseDeployment(value string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", DeploymentCloud:
+		return DeploymentCloud, nil
+	case DeploymentServer, "datacenter", "data-center", "dc":
+		return DeploymentServer, nil
+	}
+	return "", fmt.Errorf("invalid JIRA_DEPLOYMENT %q: must be cloud or server", value)
+}
+
+// NewServerClient creates a client of the v2 REST API, which Jira Server and Data Center offer instead of v3
+func NewServerClient(credentials *Credentials) (*v2.Client, error) {
+	return v2.New(authenticatedHttpClient(credentials.Auth), credentials.Site)
+}
+
+// useServerServices points the services of client whose requests and responses are the same in both
+// REST API versions to the v2 API of server. go-atlassian builds them from the same types for both
+// versions. Issues, comments, worklogs, links and search carry rich text and accountIds, so they stay
+// on v3 and callers must use the v2 client for them explicitly.
+func useServerServices(client *jira.Client, server *v2.Client) {
+	client.Audit = server.Audit
+	client.Role = server.Role
+	client.Banner = server.Banner
+	client.Dashboard = server.Dashboard
+	client.Filter = server.Filter
+	client.Group = server.Group
+	client.MySelf = server.MySelf
+	client.Permission = server.Permission
+	client.Project = server.Project
+	client.Screen = server.Screen
+	client.Task = server.Task
+	client.Server = server.Server
+	client.User = server.User
+	client.Workflow = server.Workflow
+	client.JQL = server.JQL
+	client.NotificationScheme = server.NotificationScheme
+	client.Team = server.Team
+
+	client.Issue.Attachment = server.Issue.Attachment
+	client.Issue.Field = server.Issue.Field
+	client.Issue.Label = server.Issue.Label
+	client.Issue.Metadata = server.Issue.Metadata
+	client.Issue.Priority = server.Issue.Priority
+	client.Issue.Resolution = server.Issue.Resolution
+	client.Issue.Type = server.Issue.Type
+	client.Issue.Vote = server.Issue.Vote
+	client.Issue.Watcher = server.Issue.Watcher
+	client.Issue.Property = server.Issue.Property
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseDeployment(t *testing.T) {
+	for value, want := range map[string]string{"": DeploymentCloud, "cloud": DeploymentCloud, "server": DeploymentServer, "Data-Center": DeploymentServer, " dc ": DeploymentServer} {
+		if got, err := ParseDeployment(value); err != nil || got != want {
+			t.Errorf("ParseDeployment(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseDeployment("onprem"); err == nil {
+		t.Error("expected an error for an unknown deployment")
+	}
+}
+
+func TestServerClientsUseTheV2API(t *testing.T) {
+	var paths []string
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "alice", "displayName": "Alice"}`))
+	}))
+	defer jiraServer.Close()
+
+	ctx := context.Background()
+	clients, err := NewClients(&Credentials{Site: jiraServer.URL, Auth: BearerAuth{Token: "pat"}, Deployment: DeploymentServer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clients.Server == nil {
+		t.Fatal("no v2 client for a server deployment")
+	}
+
+	// Services that are the same in both versions go to v2 through the v3 client as well
+	if _, _, err := clients.Jira.MySelf.Details(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	// Rich text services stay on v3, callers must use the v2 client for them
+	clients.Server.Issue.Get(ctx, "PROJ-1", nil, nil)
+	if len(paths) != 2 || paths[0] != "/rest/api/2/myself" || paths[1] != "/rest/api/2/issue/PROJ-1" {
+		t.Errorf("requested %v", paths)
+	}
+
+	cloud, err := NewClients(&Credentials{Site: jiraServer.URL, Auth: BearerAuth{Token: "pat"}, Deployment: DeploymentCloud})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cloud.Server != nil {
+		t.Error("a cloud deployment got a v2 client")
+	}
+	if ServerClientFor(ContextWithClients(ctx, cloud)) != nil || ServerClientFor(ContextWithClients(ctx, clients)) != clients.Server {
+		t.Error("ServerClientFor does not follow the clients of the context")
+	}
+}
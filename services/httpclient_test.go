@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTTPTransportTrustsCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// Without the CA the self-signed certificate is rejected, there is no silent InsecureSkipVerify
+	transport, err := newHTTPTransport(DefaultHTTPConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&http.Client{Transport: transport}).Get(server.URL); err == nil {
+		t.Fatal("expected a certificate error")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certificate, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultHTTPConfig()
+	config.CAFile = caFile
+	if transport, err = newHTTPTransport(config); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+	if err != nil {
+		t.Fatalf("with the CA file: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestHTTPTransportUsesProxy(t *testing.T) {
+	var proxyAuth, target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		target = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	config := DefaultHTTPConfig()
+	config.ProxyURL = "http://bot:secret@" + proxy.Listener.Addr().String()
+	transport, err := newHTTPTransport(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := (&http.Client{Transport: transport}).Get("http://jira.example.com/rest/api/3/myself")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if target != "http://jira.example.com/rest/api/3/myself" {
+		t.Errorf("proxy got %q", target)
+	}
+	if want := "Basic " + base64.StdEncoding.EncodeToString([]byte("bot:secret")); proxyAuth != want {
+		t.Errorf("Proxy-Authorization = %q, want %q", proxyAuth, want)
+	}
+}
+
+func TestHTTPTransportRejectsBadConfig(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, config := range map[string]HTTPConfig{
+		"proxy without host":  {ProxyURL: "proxy:3128"},
+		"missing CA file":     {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"CA file without PEM": {CAFile: empty},
+		"certificate, no key": {ClientCertFile: empty},
+		"invalid key pair":    {ClientCertFile: empty, ClientKeyFile: empty},
+	} {
+		if _, err := newHTTPTransport(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -1,26 +1,148 @@
 package services
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"sync"
 
+	"github.com/ctreminiom/go-atlassian/jira/agile"
+	v2 "github.com/ctreminiom/go-atlassian/jira/v2"
 	jira "github.com/ctreminiom/go-atlassian/jira/v3"
 	"github.com/pkg/errors"
 )
 
-var JiraClient = sync.OnceValue[*jira.Client](func() *jira.Client {
-	host, mail, token := loadAtlassianCredentials()
+// NewJiraClient creates a Jira client acting with the given credentials. On Server and Data Center, its
+// services that do not differ between the REST API versions use the v2 API; see useServerServices.
+func NewJiraClient(credentials *Credentials) (*jira.Client, error) {
+	client, err := jira.New(authenticatedHttpClient(credentials.Auth), credentials.Site)
+	if err != nil || !credentials.IsServer() {
+		return client, err
+	}
 
-	if host == "" || mail == "" || token == "" {
-		log.Fatal("ATLASSIAN_HOST, ATLASSIAN_EMAIL, ATLASSIAN_TOKEN are required")
+	server, err := NewServerClient(credentials)
+	if err != nil {
+		return nil, err
 	}
+	useServerServices(client, server)
+	return client, nil
+}
 
-	instance, err := jira.New(nil, host)
+var JiraClient = sync.OnceValue[*jira.Client](func() *jira.Client {
+	credentials, err := DefaultCredentials()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	instance, err := NewJiraClient(credentials)
 	if err != nil {
 		log.Fatal(errors.WithMessage(err, "failed to create jira client"))
 	}
 
-	instance.Auth.SetBasicAuth(mail, token)
+	return instance
+})
+
+// JiraClientFor returns the Jira client of the session in ctx, or JiraClient
+func JiraClientFor(ctx context.Context) *jira.Client {
+	if clients := clientsFromContext(ctx); clients != nil {
+		return clients.Jira
+	}
+	return JiraClient()
+}
+
+// ServerClient is the v2 client of the server's own credentials, nil unless they are for Server or Data Center
+var ServerClient = sync.OnceValue[*v2.Client](func() *v2.Client {
+	credentials, err := DefaultCredentials()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !credentials.IsServer() {
+		return nil
+	}
+
+	instance, err := NewServerClient(credentials)
+	if err != nil {
+		log.Fatal(errors.WithMessage(err, "failed to create jira server client"))
+	}
 
 	return instance
-})
\ No newline at end of file
+})
+
+// ServerClientFor returns the v2 client of the session in ctx, or ServerClient. It is nil on Jira Cloud,
+// so tools check it to pick the Server and Data Center variant of a request.
+func ServerClientFor(ctx context.Context) *v2.Client {
+	if clients := clientsFromContext(ctx); clients != nil {
+		return clients.Server
+	}
+	return ServerClient()
+}
+
+// Clients are the Jira and agile clients of a session that carries its own credentials
+type Clients struct {
+	Jira *jira.Client
+	// Server is the v2 client, only set for Server and Data Center
+	Server *v2.Client
+	Agile  *agile.Client
+
+	mu       sync.Mutex
+	identity string
+}
+
+// Identity returns the account Jira authenticates the clients' credentials as: the account ID on
+// Cloud, the user key on Server and Data Center. It asks /myself once; failures are not kept, so
+// the next call asks again.
+func (c *Clients) Identity(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.identity != "" {
+		return c.identity, nil
+	}
+
+	myself, response, err := c.Jira.MySelf.Details(ctx, nil)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to get current user: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get current user: %v", err)
+	}
+	switch {
+	case myself.AccountID != "":
+		c.identity = "account:" + myself.AccountID
+	case myself.Key != "":
+		c.identity = "user:" + myself.Key
+	default:
+		return "", fmt.Errorf("failed to get current user: Jira returned no account ID")
+	}
+	return c.identity, nil
+}
+
+// NewClients creates the clients for a session's credentials
+func NewClients(credentials *Credentials) (*Clients, error) {
+	jiraClient, err := NewJiraClient(credentials)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create jira client")
+	}
+	agileClient, err := NewAgileClient(credentials)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create agile client")
+	}
+	clients := &Clients{Jira: jiraClient, Agile: agileClient}
+	if credentials.IsServer() {
+		if clients.Server, err = NewServerClient(credentials); err != nil {
+			return nil, errors.WithMessage(err, "failed to create jira server client")
+		}
+	}
+	return clients, nil
+}
+
+type clientsContextKey struct{}
+
+// ContextWithClients makes JiraClientFor and AgileClientFor return the given clients instead of the server's
+func ContextWithClients(ctx context.Context, clients *Clients) context.Context {
+	return context.WithValue(ctx, clientsContextKey{}, clients)
+}
+
+func clientsFromContext(ctx context.Context) *Clients {
+	clients, _ := ctx.Value(clientsContextKey{}).(*Clients)
+	return clients
+}
@@ -0,0 +1,392 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	oauthAuthorizeURL    = "https://auth.atlassian.com/authorize"
+	oauthTokenURL        = "https://auth.atlassian.com/oauth/token"
+	oauthResourcesURL    = "https://api.atlassian.com/oauth/token/accessible-resources"
+	oauthAPIGateway      = "https://api.atlassian.com/ex/jira/"
+	defaultOAuthRedirect = "http://localhost:8085/callback"
+	// oauthRefreshMargin refreshes access tokens this long before they expire
+	oauthRefreshMargin = time.Minute
+)
+
+// defaultOAuthScopes cover the tools; offline_access is what makes Atlassian return a refresh token
+var defaultOAuthScopes = []string{"read:jira-work", "write:jira-work", "read:jira-user", "offline_access"}
+
+// OAuthConfig is an Atlassian OAuth 2.0 (3LO) app from developer.atlassian.com
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// TokenFile stores the current token; Atlassian rotates refresh tokens, so it is rewritten on every refresh
+	TokenFile string
+}
+
+// OAuthConfigFromEnv reads ATLASSIAN_OAUTH_CLIENT_ID, ATLASSIAN_OAUTH_CLIENT_SECRET, ATLASSIAN_OAUTH_REDIRECT_URL,
+// ATLASSIAN_OAUTH_SCOPES and ATLASSIAN_OAUTH_TOKEN_FILE
+func OAuthConfigFromEnv() (OAuthConfig, error) {
+	config := OAuthConfig{
+		ClientID:     os.Getenv("ATLASSIAN_OAUTH_CLIENT_ID"),
+		ClientSecret: os.Getenv("ATLASSIAN_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("ATLASSIAN_OAUTH_REDIRECT_URL"),
+		Scopes:       strings.Fields(strings.ReplaceAll(os.Getenv("ATLASSIAN_OAUTH_SCOPES"), ",", " ")),
+		TokenFile:    os.Getenv("ATLASSIAN_OAUTH_TOKEN_FILE"),
+	}
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return config, fmt.Errorf("ATLASSIAN_OAUTH_CLIENT_ID and ATLASSIAN_OAUTH_CLIENT_SECRET are required for OAuth")
+	}
+	if config.RedirectURL == "" {
+		config.RedirectURL = defaultOAuthRedirect
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = defaultOAuthScopes
+	}
+	if config.TokenFile == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return config, fmt.Errorf("ATLASSIAN_OAUTH_TOKEN_FILE is required: %w", err)
+		}
+		config.TokenFile = filepath.Join(dir, "jira-mcp", "oauth-token.json")
+	}
+	return config, nil
+}
+
+// OAuthToken is an access token with the refresh token to renew it
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+func (t *OAuthToken) valid() bool {
+	return t.AccessToken != "" && time.Until(t.Expiry) > oauthRefreshMargin
+}
+
+// TokenStore keeps OAuth tokens between runs
+type TokenStore interface {
+	Load() (*OAuthToken, error)
+	Save(token *OAuthToken) error
+}
+
+// FileTokenStore keeps the token in a JSON file only the current user can read
+type FileTokenStore struct {
+	Path string
+}
+
+// Load returns the stored token, or nil when none was saved yet
+func (s FileTokenStore) Load() (*OAuthToken, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth token: %w", err)
+	}
+	token := &OAuthToken{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("invalid OAuth token file %s: %w", s.Path, err)
+	}
+	return token, nil
+}
+
+// Save replaces the stored token atomically, so a crash never loses the rotated refresh token
+func (s FileTokenStore) Save(token *OAuthToken) error {
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o700); err != nil {
+		return fmt.Errorf("failed to save OAuth token: %w", err)
+	}
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save OAuth token: %w", err)
+	}
+	if err := os.Rename(tmp, s.Path); err != nil {
+		return fmt.Errorf("failed to save OAuth token: %w", err)
+	}
+	return nil
+}
+
+// OAuthAuth authenticates with OAuth 2.0 (3LO) access tokens and refreshes them when they expire
+type OAuthAuth struct {
+	config OAuthConfig
+	store  TokenStore
+
+	mu    sync.Mutex
+	token *OAuthToken
+
+	tokenURL     string
+	resourcesURL string
+}
+
+// errNoOAuthToken is returned when a token store holds no token
+var errNoOAuthToken = errors.New("no stored OAuth token")
+
+// NewOAuthAuth loads the token from store. Without a stored token, ATLASSIAN_OAUTH_REFRESH_TOKEN seeds it;
+// otherwise run the server once with --oauth-login.
+func NewOAuthAuth(config OAuthConfig, store TokenStore) (*OAuthAuth, error) {
+	auth, err := StoredOAuthAuth(config, store)
+	if !errors.Is(err, errNoOAuthToken) {
+		return auth, err
+	}
+	refreshToken := os.Getenv("ATLASSIAN_OAUTH_REFRESH_TOKEN")
+	if refreshToken == "" {
+		return nil, fmt.Errorf("no OAuth token in %s: run jira-mcp --oauth-login once, or set ATLASSIAN_OAUTH_REFRESH_TOKEN", config.TokenFile)
+	}
+	return newOAuthAuth(config, store, &OAuthToken{RefreshToken: refreshToken}), nil
+}
+
+// StoredOAuthAuth only uses a token already in store, for stores of individual users that must never fall
+// back to the server's own account
+func StoredOAuthAuth(config OAuthConfig, store TokenStore) (*OAuthAuth, error) {
+	token, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errNoOAuthToken
+	}
+	return newOAuthAuth(config, store, token), nil
+}
+
+func newOAuthAuth(config OAuthConfig, store TokenStore, token *OAuthToken) *OAuthAuth {
+	return &OAuthAuth{config: config, store: store, token: token, tokenURL: oauthTokenURL, resourcesURL: oauthResourcesURL}
+}
+
+func (a *OAuthAuth) Authenticate(req *http.Request) error {
+	accessToken, err := a.AccessToken(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	return nil
+}
+
+// AccessToken returns a valid access token, refreshing it first when it is about to expire
+func (a *OAuthAuth) AccessToken(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token.valid() {
+		return a.token.AccessToken, nil
+	}
+	if a.token.RefreshToken == "" {
+		return "", fmt.Errorf("the OAuth access token expired and there is no refresh token: run jira-mcp --oauth-login again")
+	}
+
+	token, err := a.requestToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": a.token.RefreshToken,
+	})
+	if err != nil {
+		return "", err
+	}
+	// Refresh tokens rotate, but Atlassian may leave the old one valid and omit it
+	if token.RefreshToken == "" {
+		token.RefreshToken = a.token.RefreshToken
+	}
+	a.token = token
+	if err := a.store.Save(token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// Exchange trades the code of the authorization redirect for a token and stores it
+func (a *OAuthAuth) Exchange(ctx context.Context, code string) error {
+	token, err := a.requestToken(ctx, map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": a.config.RedirectURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = token
+	return a.store.Save(token)
+}
+
+func (a *OAuthAuth) requestToken(ctx context.Context, grant map[string]string) (*OAuthToken, error) {
+	grant["client_id"] = a.config.ClientID
+	grant["client_secret"] = a.config.ClientSecret
+	body, err := json.Marshal(grant)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := DefaultHttpClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OAuth token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get OAuth token: %s (status %d)", strings.TrimSpace(string(data)), resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode OAuth token: %w", err)
+	}
+	return &OAuthToken{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// Site returns the API gateway URL of the Jira site at host. OAuth requests cannot go to the site itself;
+// without cloudID the site is looked up among those the token may access.
+func (a *OAuthAuth) Site(ctx context.Context, host, cloudID string) (string, error) {
+	if cloudID != "" {
+		return oauthAPIGateway + cloudID, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.resourcesURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := a.Authenticate(req); err != nil {
+		return "", err
+	}
+
+	resp, err := DefaultHttpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get accessible Atlassian sites: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get accessible Atlassian sites: %s (status %d)", strings.TrimSpace(string(data)), resp.StatusCode)
+	}
+
+	var resources []struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return "", fmt.Errorf("failed to decode accessible Atlassian sites: %w", err)
+	}
+
+	var urls []string
+	for _, resource := range resources {
+		if strings.EqualFold(strings.TrimSuffix(resource.URL, "/"), strings.TrimSuffix(host, "/")) {
+			return oauthAPIGateway + resource.ID, nil
+		}
+		urls = append(urls, resource.URL)
+	}
+	return "", fmt.Errorf("the OAuth token cannot access %s (accessible sites: %s); set ATLASSIAN_CLOUD_ID or authorize the app for that site", host, strings.Join(urls, ", "))
+}
+
+// AuthorizationURL is where the user grants the app access; state protects the redirect against forgery
+func (a *OAuthAuth) AuthorizationURL(state string) string {
+	params := url.Values{}
+	params.Set("audience", "api.atlassian.com")
+	params.Set("client_id", a.config.ClientID)
+	params.Set("scope", strings.Join(a.config.Scopes, " "))
+	params.Set("redirect_uri", a.config.RedirectURL)
+	params.Set("state", state)
+	params.Set("response_type", "code")
+	params.Set("prompt", "consent")
+	return oauthAuthorizeURL + "?" + params.Encode()
+}
+
+// OAuthLogin runs the authorization code flow: it prints the authorization URL, waits for the redirect
+// on the local address of the redirect URL and stores the token
+func OAuthLogin(ctx context.Context, config OAuthConfig) error {
+	redirect, err := url.Parse(config.RedirectURL)
+	if err != nil || redirect.Host == "" {
+		return fmt.Errorf("invalid ATLASSIAN_OAUTH_REDIRECT_URL %q", config.RedirectURL)
+	}
+
+	auth := newOAuthAuth(config, FileTokenStore{Path: config.TokenFile}, &OAuthToken{})
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return err
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the OAuth redirect: %w", err)
+	}
+
+	result := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var err error
+		switch {
+		case query.Get("state") != state:
+			err = fmt.Errorf("the OAuth redirect has an unexpected state")
+		case query.Get("error") != "":
+			err = fmt.Errorf("authorization failed: %s", query.Get("error_description"))
+		default:
+			err = auth.Exchange(r.Context(), query.Get("code"))
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Jira MCP is authorized, you can close this window.")
+		}
+		select {
+		case result <- err:
+		default:
+		}
+	})
+	httpServer := &http.Server{Handler: mux}
+	go httpServer.Serve(listener)
+	defer httpServer.Close()
+
+	fmt.Println("🔑 Open this URL to authorize Jira MCP:")
+	fmt.Println(auth.AuthorizationURL(state))
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	fmt.Printf("✅ Saved the OAuth token to %s\n", config.TokenFile)
+	return nil
+}
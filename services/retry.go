@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryConfig controls how the shared transport paces requests to Jira and retries the ones that fail
+type RetryConfig struct {
+	// MaxRetries is how often a request is retried after a rate limit, an unavailable server or a network error
+	MaxRetries int
+	// RequestsPerSecond is the sustained client-side request rate, 0 to not limit it
+	RequestsPerSecond float64
+	// Burst is how many requests may be sent at once before RequestsPerSecond applies
+	Burst int
+	// MaxWait is the longest the transport waits before a retry; a longer Retry-After fails the request
+	MaxWait time.Duration
+}
+
+// DefaultRetryConfig stays below the Atlassian Cloud rate limits for a single user
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{MaxRetries: 4, RequestsPerSecond: 10, Burst: 10, MaxWait: time.Minute}
+}
+
+var retryConfig = DefaultRetryConfig()
+
+// SetRetryConfig changes the retry and rate limit settings. It must be called before the first client is created.
+func SetRetryConfig(config RetryConfig) {
+	retryConfig = config
+}
+
+const (
+	retryBaseDelay  = 500 * time.Millisecond
+	retryMaxBackoff = 30 * time.Second
+	// maxErrorBodySize limits how much of a failed response ends up in a RetryError
+	maxErrorBodySize = 512
+)
+
+// RetryError is returned when a request still failed after the transport retried it, or could not be retried.
+// It replaces the response so that the model sees how often the request was tried instead of a raw error body.
+type RetryError struct {
+	Method     string
+	Retries    int
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+	Body       string
+	Reason     string
+	Err        error
+}
+
+func (e *RetryError) Error() string {
+	var message string
+	if e.Err != nil {
+		message = fmt.Sprintf("request failed: %v", e.Err)
+	} else if e.StatusCode == http.StatusTooManyRequests {
+		message = "rate limited by Jira (" + e.Status + ")"
+	} else {
+		message = "Jira returned " + e.Status
+	}
+
+	message += fmt.Sprintf(" after %d %s", e.Retries, pluralize(e.Retries, "retry", "retries"))
+	if e.Reason != "" {
+		message += " (" + e.Reason + ")"
+	}
+	if e.RetryAfter > 0 {
+		message += fmt.Sprintf("; Jira asks to retry after %s", e.RetryAfter)
+	}
+	if e.Body != "" {
+		message += ": " + e.Body
+	}
+	return message
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// NewRetryTransport wraps next with a client-side rate limit and retries.
+// Rate limited requests (429, or 503 with Retry-After) are retried whatever their method, since Jira did not process them.
+// Other unavailable responses (502, 503, 504) and network errors are only retried for idempotent requests.
+func NewRetryTransport(next http.RoundTripper, config RetryConfig) http.RoundTripper {
+	transport := &retryTransport{next: next, config: config, baseDelay: retryBaseDelay, sleep: sleepContext}
+	if config.RequestsPerSecond > 0 {
+		transport.limiter = newTokenBucket(config.RequestsPerSecond, config.Burst)
+	}
+	return transport
+}
+
+type retryTransport struct {
+	next      http.RoundTripper
+	config    RetryConfig
+	limiter   *tokenBucket
+	baseDelay time.Duration
+	sleep     func(ctx context.Context, delay time.Duration) error
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	attemptReq := req
+	for retries := 0; ; retries++ {
+		if retries > 0 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		if err := t.limiter.wait(ctx, t.sleep); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+
+		var retryAfter time.Duration
+		retryable, rateLimited := false, false
+		if err != nil {
+			retryable = ctx.Err() == nil
+		} else {
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			switch resp.StatusCode {
+			case http.StatusTooManyRequests:
+				retryable, rateLimited = true, true
+			case http.StatusServiceUnavailable:
+				retryable, rateLimited = true, resp.Header.Get("Retry-After") != ""
+			case http.StatusBadGateway, http.StatusGatewayTimeout:
+				retryable = true
+			}
+		}
+		if !retryable {
+			return resp, err
+		}
+
+		delay := retryAfter
+		if delay == 0 {
+			delay = backoff(t.baseDelay, retries)
+		}
+
+		reason, giveUp := "", true
+		switch {
+		case !rateLimited && !isIdempotent(req.Method):
+			reason = req.Method + " requests are not retried"
+		case !rewindable:
+			reason = "the request body cannot be sent again"
+		case t.config.MaxWait > 0 && delay > t.config.MaxWait:
+			reason = fmt.Sprintf("waiting longer than %s is not allowed", t.config.MaxWait)
+		case retries >= t.config.MaxRetries:
+			// out of retries, the error says how many were made
+		default:
+			giveUp = false
+		}
+		if giveUp {
+			if err != nil && retries == 0 {
+				return nil, err
+			}
+			return nil, newRetryError(req.Method, retries, resp, err, retryAfter, reason)
+		}
+
+		// Jira limits the whole account, so other requests wait as well
+		if rateLimited && retryAfter > 0 {
+			t.limiter.pause(retryAfter)
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+			resp.Body.Close()
+		}
+		if err := t.sleep(ctx, delay); err != nil {
+			return nil, newRetryError(req.Method, retries, nil, err, 0, "")
+		}
+	}
+}
+
+func newRetryError(method string, retries int, resp *http.Response, err error, retryAfter time.Duration, reason string) *RetryError {
+	retryErr := &RetryError{Method: method, Retries: retries, RetryAfter: retryAfter, Reason: reason, Err: err}
+	if resp != nil {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		retryErr.StatusCode = resp.StatusCode
+		retryErr.Status = resp.Status
+		retryErr.Body = strings.TrimSpace(string(body))
+	}
+	return retryErr
+}
+
+// isIdempotent reports whether sending a request twice has the same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns an exponential delay for the given retry with equal jitter, so that concurrent clients spread out
+func backoff(base time.Duration, retries int) time.Duration {
+	delay := base << retries
+	if delay <= 0 || delay > retryMaxBackoff {
+		delay = retryMaxBackoff
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// tokenBucket is a client-side rate limiter shared by every request of the transport.
+// Requests take a token up front and wait for the debt to be paid back, which keeps them in arrival order.
+type tokenBucket struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), now: time.Now}
+}
+
+// reserve takes a token and returns how long the caller has to wait before using it
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if paused := b.pausedUntil.Sub(now); paused > delay {
+		delay = paused
+	}
+	return delay
+}
+
+// pause holds back every request until Jira's Retry-After has passed
+func (b *tokenBucket) pause(delay time.Duration) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until := b.now().Add(delay); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context, sleep func(context.Context, time.Duration) error) error {
+	if b == nil {
+		return nil
+	}
+	return sleep(ctx, b.reserve())
+}
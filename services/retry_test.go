@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetryClient returns a client whose retries do not sleep, recording the delays instead
+func newTestRetryClient(config RetryConfig) (*http.Client, *[]time.Duration) {
+	var delays []time.Duration
+	transport := NewRetryTransport(http.DefaultTransport, config).(*retryTransport)
+	transport.baseDelay = time.Millisecond
+	transport.sleep = func(ctx context.Context, delay time.Duration) error {
+		if delay > 0 {
+			delays = append(delays, delay)
+		}
+		return ctx.Err()
+	}
+	return &http.Client{Transport: transport}, &delays
+}
+
+func TestRetryTransportHonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"summary":"x"}` {
+			t.Errorf("retried request lost its body: %q", body)
+		}
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client, delays := newTestRetryClient(RetryConfig{MaxRetries: 4})
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"summary":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated || calls.Load() != 3 {
+		t.Errorf("status %d after %d calls", resp.StatusCode, calls.Load())
+	}
+	if len(*delays) != 2 || (*delays)[0] != 2*time.Second {
+		t.Errorf("delays = %v, want two of 2s", *delays)
+	}
+}
+
+func TestRetryTransportGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"errorMessages":["down for maintenance"]}`)
+	}))
+	defer server.Close()
+
+	client, _ := newTestRetryClient(RetryConfig{MaxRetries: 2})
+
+	_, err := client.Get(server.URL)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Retries != 2 || calls.Load() != 3 {
+		t.Fatalf("GET: %v after %d calls", err, calls.Load())
+	}
+	if !strings.Contains(err.Error(), "Jira returned 503 Service Unavailable after 2 retries: {\"errorMessages\":[\"down for maintenance\"]}") {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	// Creating an issue twice is worse than failing once
+	calls.Store(0)
+	_, err = client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if !errors.As(err, &retryErr) || calls.Load() != 1 || !strings.Contains(err.Error(), "after 0 retries (POST requests are not retried)") {
+		t.Errorf("POST: %v after %d calls", err, calls.Load())
+	}
+}
+
+func TestRetryTransportRejectsLongRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client, delays := newTestRetryClient(RetryConfig{MaxRetries: 4, MaxWait: time.Minute})
+	_, err := client.Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "rate limited by Jira (429 Too Many Requests) after 0 retries (waiting longer than 1m0s is not allowed); Jira asks to retry after 10m0s") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(*delays) != 0 {
+		t.Errorf("waited %v", *delays)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for retries := 0; retries < 10; retries++ {
+		want := retryBaseDelay << retries
+		if want > retryMaxBackoff {
+			want = retryMaxBackoff
+		}
+		if delay := backoff(retryBaseDelay, retries); delay < want/2 || delay > want {
+			t.Errorf("backoff(%d) = %s, want between %s and %s", retries, delay, want/2, want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("30"); got != 30*time.Second {
+		t.Errorf("seconds: %s", got)
+	}
+	if got := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)); got < 58*time.Second || got > time.Minute {
+		t.Errorf("date: %s", got)
+	}
+	for _, invalid := range []string{"", "-1", "soon"} {
+		if got := parseRetryAfter(invalid); got != 0 {
+			t.Errorf("parseRetryAfter(%q) = %s", invalid, got)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(0, 0)
+	bucket := newTokenBucket(2, 2)
+	bucket.now = func() time.Time { return now }
+
+	for i, want := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if got := bucket.reserve(); got != want {
+			t.Errorf("request %d waits %s, want %s", i, got, want)
+		}
+	}
+
+	now = now.Add(3 * time.Second)
+	if got := bucket.reserve(); got != 0 {
+		t.Errorf("after refilling: waits %s", got)
+	}
+
+	bucket.pause(5 * time.Second)
+	if got := bucket.reserve(); got != 5*time.Second {
+		t.Errorf("while paused: waits %s, want 5s", got)
+	}
+}
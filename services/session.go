@@ -0,0 +1,286 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Headers a client of the HTTP server sends its own Jira credentials in
+const (
+	HeaderAtlassianEmail = "X-Atlassian-Email"
+	HeaderAtlassianToken = "X-Atlassian-Token"
+	HeaderJiraSession    = "X-Jira-Session"
+)
+
+const oauthLoginTimeout = 10 * time.Minute
+
+// ErrNoUserCredentials is reported to sessions that did not send credentials to a server that needs them
+var ErrNoUserCredentials = errors.New("this server acts with your own Jira account: send your email and API token in the " +
+	HeaderAtlassianEmail + " and " + HeaderAtlassianToken + " headers, a personal access token in " + HeaderAtlassianToken +
+	", or sign in at /oauth/login and send the session key in " + HeaderJiraSession)
+
+// UserCredentialsConfig configures per-user credentials of the HTTP server
+type UserCredentialsConfig struct {
+	// Host is the Jira site every user works on
+	Host string
+	// CloudID is the cloud ID of Host for OAuth; looked up when empty
+	CloudID string
+	// Deployment is DeploymentCloud or DeploymentServer, empty means cloud
+	Deployment string
+	// OAuth enables signing in through /oauth/login when set
+	OAuth *OAuthConfig
+	// SessionDir stores the OAuth tokens of signed in users
+	SessionDir string
+	// CacheSize is how many users' clients are kept
+	CacheSize int
+}
+
+// UserCredentials builds and caches clients for the credentials each HTTP request carries
+type UserCredentials struct {
+	config UserCredentialsConfig
+	cache  *clientCache
+
+	mu      sync.Mutex
+	pending map[string]time.Time
+}
+
+func NewUserCredentials(config UserCredentialsConfig) *UserCredentials {
+	return &UserCredentials{config: config, cache: newClientCache(config.CacheSize), pending: map[string]time.Time{}}
+}
+
+type userClientsContextKey struct{}
+
+type userClients struct {
+	clients *Clients
+	err     error
+}
+
+// HTTPContext adds the clients of the request's credentials to ctx. It is meant for server.WithHTTPContextFunc.
+func (u *UserCredentials) HTTPContext(ctx context.Context, r *http.Request) context.Context {
+	user := &userClients{}
+	user.clients, user.err = u.clients(ctx, r.Header)
+	if user.clients != nil {
+		ctx = ContextWithClients(ctx, user.clients)
+	}
+	return context.WithValue(ctx, userClientsContextKey{}, user)
+}
+
+// Actor identifies whose credentials the request in ctx acts with, by the account Jira authenticates
+// them as rather than by what the headers claim. It is empty when the server's own credentials are
+// used, and an error when Jira does not accept the credentials.
+func Actor(ctx context.Context) (string, error) {
+	user, _ := ctx.Value(userClientsContextKey{}).(*userClients)
+	if user == nil || user.clients == nil {
+		return "", nil
+	}
+	identity, err := user.clients.Identity(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify your Jira credentials: %w", err)
+	}
+	return identity, nil
+}
+
+// CheckUserCredentials returns why the request in ctx has no clients of its own, or nil when it has
+func CheckUserCredentials(ctx context.Context) error {
+	user, _ := ctx.Value(userClientsContextKey{}).(*userClients)
+	switch {
+	case user == nil:
+		return ErrNoUserCredentials
+	case user.err != nil:
+		return user.err
+	case user.clients == nil:
+		return ErrNoUserCredentials
+	}
+	return nil
+}
+
+func (u *UserCredentials) clients(ctx context.Context, header http.Header) (*Clients, error) {
+	email, token, session := header.Get(HeaderAtlassianEmail), header.Get(HeaderAtlassianToken), header.Get(HeaderJiraSession)
+
+	switch {
+	case session != "":
+		if u.config.OAuth == nil {
+			return nil, fmt.Errorf("OAuth sign in is not configured on this server")
+		}
+		return u.cache.get(cacheKey("oauth", session), func() (*Clients, error) {
+			auth, err := StoredOAuthAuth(*u.config.OAuth, u.sessionStore(session))
+			if err != nil {
+				return nil, fmt.Errorf("unknown or expired Jira session, sign in again at /oauth/login")
+			}
+			site, err := auth.Site(ctx, u.config.Host, u.config.CloudID)
+			if err != nil {
+				return nil, err
+			}
+			return NewClients(&Credentials{Site: site, Auth: auth, Deployment: u.config.Deployment})
+		})
+	case token != "" && email != "":
+		return u.cache.get(cacheKey("basic", email, token), func() (*Clients, error) {
+			return NewClients(&Credentials{Site: u.config.Host, Auth: BasicAuth{Email: email, Token: token}, Deployment: u.config.Deployment})
+		})
+	case token != "":
+		return u.cache.get(cacheKey("bearer", token), func() (*Clients, error) {
+			return NewClients(&Credentials{Site: u.config.Host, Auth: BearerAuth{Token: token}, Deployment: u.config.Deployment})
+		})
+	case email != "":
+		return nil, fmt.Errorf("%s needs an API token in %s", HeaderAtlassianEmail, HeaderAtlassianToken)
+	}
+	return nil, nil
+}
+
+// sessionStore keeps a signed in user's token in a file named after a hash of the session key,
+// so the key itself is never written to disk
+func (u *UserCredentials) sessionStore(session string) FileTokenStore {
+	return FileTokenStore{Path: filepath.Join(u.config.SessionDir, cacheKey("session", session)+".json")}
+}
+
+// RegisterOAuthHandlers adds /oauth/login and the callback of the redirect URL to mux
+func (u *UserCredentials) RegisterOAuthHandlers(mux *http.ServeMux) error {
+	if u.config.OAuth == nil {
+		return nil
+	}
+	redirect, err := url.Parse(u.config.OAuth.RedirectURL)
+	if err != nil || redirect.Path == "" || redirect.Path == "/" {
+		return fmt.Errorf("invalid ATLASSIAN_OAUTH_REDIRECT_URL %q: it must point to the /oauth/callback path of this server", u.config.OAuth.RedirectURL)
+	}
+	if err := os.MkdirAll(u.config.SessionDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create the OAuth session directory: %w", err)
+	}
+
+	mux.HandleFunc("/oauth/login", u.handleLogin)
+	mux.HandleFunc(redirect.Path, u.handleCallback)
+	return nil
+}
+
+func (u *UserCredentials) handleLogin(w http.ResponseWriter, r *http.Request) {
+	state, err := randomKey()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	u.mu.Lock()
+	now := time.Now()
+	for pending, expiry := range u.pending {
+		if now.After(expiry) {
+			delete(u.pending, pending)
+		}
+	}
+	u.pending[state] = now.Add(oauthLoginTimeout)
+	u.mu.Unlock()
+
+	auth := newOAuthAuth(*u.config.OAuth, nil, &OAuthToken{})
+	http.Redirect(w, r, auth.AuthorizationURL(state), http.StatusFound)
+}
+
+func (u *UserCredentials) handleCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	u.mu.Lock()
+	expiry, ok := u.pending[query.Get("state")]
+	delete(u.pending, query.Get("state"))
+	u.mu.Unlock()
+
+	if !ok || time.Now().After(expiry) {
+		http.Error(w, "The sign in expired or was not started here, open /oauth/login again.", http.StatusBadRequest)
+		return
+	}
+	if query.Get("error") != "" {
+		http.Error(w, "Authorization failed: "+query.Get("error_description"), http.StatusBadRequest)
+		return
+	}
+
+	session, err := randomKey()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	auth := newOAuthAuth(*u.config.OAuth, u.sessionStore(session), &OAuthToken{})
+	if err := auth.Exchange(r.Context(), query.Get("code")); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "Signed in to Jira. Add this header to the MCP configuration of your client:\n\n%s: %s\n\nKeep it secret, it acts as your Jira account.\n", HeaderJiraSession, session)
+}
+
+func randomKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// cacheKey hashes credentials, so that the cache does not hold them as keys
+func cacheKey(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// clientCache keeps the clients of the most recently active users. OAuth clients hold their refreshed
+// tokens, so keeping them avoids a refresh on every request.
+type clientCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type clientCacheEntry struct {
+	key     string
+	clients *Clients
+}
+
+func newClientCache(size int) *clientCache {
+	if size < 1 {
+		size = 1
+	}
+	return &clientCache{size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// get returns the cached clients for key or creates them, evicting the least recently used ones when full.
+// Failures are not cached.
+func (c *clientCache) get(key string, create func() (*Clients, error)) (*Clients, error) {
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+		return element.Value.(*clientCacheEntry).clients, nil
+	}
+	c.mu.Unlock()
+
+	clients, err := create()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*clientCacheEntry).clients, nil
+	}
+	c.entries[key] = c.order.PushFront(&clientCacheEntry{key: key, clients: clients})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*clientCacheEntry).key)
+	}
+	return clients, nil
+}
@@ -0,0 +1,431 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// AuditLogConfig configures the JSONL audit log of mutating tool calls
+type AuditLogConfig struct {
+	Path string
+	// MaxSizeBytes rotates the log before it grows beyond this size, 0 never rotates
+	MaxSizeBytes int64
+	// MaxBackups is the number of rotated files kept as Path.1 (newest) to Path.N
+	MaxBackups int
+}
+
+// AuditRecord is one line of the audit log
+type AuditRecord struct {
+	ID         string                 `json:"id"`
+	Time       string                 `json:"time"`
+	Tool       string                 `json:"tool"`
+	Actor      string                 `json:"actor,omitempty" jsonschema_description:"The Jira account of the credentials the call used in HTTP mode, empty for the server's own"`
+	Arguments  map[string]interface{} `json:"arguments"`
+	IssueKeys  []string               `json:"issue_keys,omitempty"`
+	StatusCode int                    `json:"status_code,omitempty"`
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+	Changes    []AuditChange          `json:"changes,omitempty"`
+	Created    []AuditResource        `json:"created,omitempty"`
+	Undone     []string               `json:"undone,omitempty" jsonschema_description:"IDs of the records jira_undo reverted"`
+	DurationMS int64                  `json:"duration_ms"`
+}
+
+// AuditChange is the value of a field before and after a call changed it, as text and as
+// the raw JSON Jira returned
+type AuditChange struct {
+	Issue       string          `json:"issue"`
+	Field       string          `json:"field"`
+	Before      string          `json:"before"`
+	After       string          `json:"after"`
+	BeforeValue json.RawMessage `json:"before_value,omitempty"`
+	AfterValue  json.RawMessage `json:"after_value,omitempty"`
+}
+
+// AuditResource is an issue, comment, worklog or link a call created
+type AuditResource struct {
+	Type  string `json:"type"`
+	Issue string `json:"issue"`
+	ID    string `json:"id,omitempty"`
+	// LinkType and Target identify a link, which Jira creates without returning its ID
+	LinkType string `json:"link_type,omitempty"`
+	Target   string `json:"target,omitempty"`
+}
+
+// Types of AuditResource
+const (
+	auditResourceIssue   = "issue"
+	auditResourceComment = "comment"
+	auditResourceWorklog = "worklog"
+	auditResourceLink    = "link"
+)
+
+// auditLog appends records to a file, rotating it by size
+type auditLog struct {
+	mu     sync.Mutex
+	config AuditLogConfig
+	file   *os.File
+	size   int64
+}
+
+var currentAuditLog atomic.Pointer[auditLog]
+
+// mutatingTools holds the names of registered tools not annotated as read-only
+var mutatingTools sync.Map
+
+// OpenAuditLog starts recording mutating tool calls to the configured file.
+// The server must also use the AuditMutations middleware.
+func OpenAuditLog(config AuditLogConfig) error {
+	l := &auditLog{config: config}
+	if err := l.open(); err != nil {
+		return err
+	}
+	if previous := currentAuditLog.Swap(l); previous != nil {
+		previous.close()
+	}
+	return nil
+}
+
+func (l *auditLog) open() error {
+	file, err := os.OpenFile(l.config.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+func (l *auditLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		l.file.Close()
+		l.file = nil
+	}
+}
+
+func (l *auditLog) write(record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return fmt.Errorf("audit log is closed")
+	}
+	if l.config.MaxSizeBytes > 0 && l.size > 0 && l.size+int64(len(line)) > l.config.MaxSizeBytes {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts Path.N-1 to Path.N down to Path to Path.1 and starts a new file.
+// Without backups the full log is simply truncated.
+func (l *auditLog) rotate() error {
+	l.file.Close()
+	l.file = nil
+
+	if l.config.MaxBackups > 0 {
+		for i := l.config.MaxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", l.config.Path, i), fmt.Sprintf("%s.%d", l.config.Path, i+1))
+		}
+		if err := os.Rename(l.config.Path, l.config.Path+".1"); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	} else if err := os.Remove(l.config.Path); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	return l.open()
+}
+
+// AuditMutations records every call of a mutating tool in the audit log. Handlers add the
+// affected issues, Jira response codes and field changes to the record through the context.
+func AuditMutations(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		l := currentAuditLog.Load()
+		if l == nil {
+			return next(ctx, request)
+		}
+		if _, ok := mutatingTools.Load(request.Params.Name); !ok {
+			return next(ctx, request)
+		}
+
+		start := time.Now()
+		entry := &auditEntry{record: AuditRecord{
+			ID:        newAuditRecordID(start),
+			Time:      start.UTC().Format(time.RFC3339Nano),
+			Tool:      request.Params.Name,
+			Arguments: redactArguments(request.GetArguments()),
+		}}
+
+		result, err := next(context.WithValue(ctx, auditContextKey{}, entry), request)
+
+		record := entry.snapshot()
+		record.DurationMS = time.Since(start).Milliseconds()
+		// A record whose credentials Jira rejects belongs to nobody, so no caller can undo it
+		actor, actorErr := services.Actor(ctx)
+		if actorErr != nil {
+			actor = unverifiedActor
+		}
+		record.Actor = actor
+		switch {
+		case err != nil:
+			record.Error = err.Error()
+		case result != nil && result.IsError:
+			record.Error = toolResultText(result)
+		default:
+			record.Success = true
+		}
+
+		if writeErr := l.write(record); writeErr != nil {
+			log.Printf("audit: %v", writeErr)
+		}
+		return result, err
+	}
+}
+
+// unverifiedActor is the actor of records made with credentials Jira did not accept
+const unverifiedActor = "unverified"
+
+type auditContextKey struct{}
+
+// auditEntry collects the record of a call while its handler runs, bulk tools fill it concurrently
+type auditEntry struct {
+	mu     sync.Mutex
+	record AuditRecord
+}
+
+func auditEntryFrom(ctx context.Context) *auditEntry {
+	entry, _ := ctx.Value(auditContextKey{}).(*auditEntry)
+	return entry
+}
+
+func (e *auditEntry) snapshot() AuditRecord {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.record
+}
+
+// auditRequest records a Jira request that changed or tried to change issues: the issue
+// keys and the response code. A failure code is kept over later successes, so a bulk call
+// that partly failed shows the failing code.
+func auditRequest(ctx context.Context, response *models.ResponseScheme, issueKeys ...string) {
+	entry := auditEntryFrom(ctx)
+	if entry == nil {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	for _, key := range issueKeys {
+		if key != "" && !containsString(entry.record.IssueKeys, key) {
+			entry.record.IssueKeys = append(entry.record.IssueKeys, key)
+		}
+	}
+
+	if response == nil || response.Code == 0 {
+		return
+	}
+	if entry.record.StatusCode >= http.StatusBadRequest && response.Code < http.StatusBadRequest {
+		return
+	}
+	entry.record.StatusCode = response.Code
+}
+
+// auditCreated records something a call created, so jira_undo can remove it
+func auditCreated(ctx context.Context, resource AuditResource) {
+	entry := auditEntryFrom(ctx)
+	if entry == nil {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	entry.record.Created = append(entry.record.Created, resource)
+}
+
+// auditUndone records the records a jira_undo call reverted
+func auditUndone(ctx context.Context, recordIDs ...string) {
+	entry := auditEntryFrom(ctx)
+	if entry == nil {
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	entry.record.Undone = append(entry.record.Undone, recordIDs...)
+}
+
+// auditSnapshot reads fields of an issue before a change, for auditChanges to compare them
+// afterwards. It returns nil without calling Jira when the call is not audited.
+func auditSnapshot(ctx context.Context, client *jira.Client, issueKey string, fieldIDs []string) map[string]json.RawMessage {
+	if auditEntryFrom(ctx) == nil || len(fieldIDs) == 0 {
+		return nil
+	}
+
+	values, err := getIssueFieldValues(ctx, client, issueKey, fieldIDs)
+	if err != nil {
+		log.Printf("audit: %v", err)
+		return nil
+	}
+	return values
+}
+
+// auditChanges reads the snapshot fields again and records the ones that changed
+func auditChanges(ctx context.Context, client *jira.Client, issueKey string, before map[string]json.RawMessage) {
+	entry := auditEntryFrom(ctx)
+	if entry == nil || before == nil {
+		return
+	}
+
+	fieldIDs := make([]string, 0, len(before))
+	for id := range before {
+		fieldIDs = append(fieldIDs, id)
+	}
+	sort.Strings(fieldIDs)
+
+	after, err := getIssueFieldValues(ctx, client, issueKey, fieldIDs)
+	if err != nil {
+		log.Printf("audit: %v", err)
+		return
+	}
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	for _, id := range fieldIDs {
+		if string(before[id]) == string(after[id]) {
+			continue
+		}
+		entry.record.Changes = append(entry.record.Changes, AuditChange{
+			Issue:       issueKey,
+			Field:       id,
+			Before:      util.RenderFieldValue(before[id]),
+			After:       util.RenderFieldValue(after[id]),
+			BeforeValue: before[id],
+			AfterValue:  after[id],
+		})
+	}
+}
+
+// getIssueFieldValues reads the raw JSON of fields of an issue, missing fields are null
+func getIssueFieldValues(ctx context.Context, client *jira.Client, issueKey string, fieldIDs []string) (map[string]json.RawMessage, error) {
+	endpoint := restAPI(ctx, fmt.Sprintf("issue/%s?fields=%s", issueKey, strings.Join(fieldIDs, ",")))
+	req, err := client.NewRequest(ctx, http.MethodGet, endpoint, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var issue struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	response, err := client.Call(req, &issue)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get fields of %s: %s (endpoint: %s)", issueKey, response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get fields of %s: %v", issueKey, err)
+	}
+
+	values := make(map[string]json.RawMessage, len(fieldIDs))
+	for _, id := range fieldIDs {
+		value := issue.Fields[id]
+		if len(value) == 0 {
+			value = json.RawMessage("null")
+		}
+		values[id] = value
+	}
+	return values, nil
+}
+
+// redactArguments copies the arguments of a call, hiding values of secret-looking keys
+func redactArguments(arguments map[string]interface{}) map[string]interface{} {
+	redacted, _ := redactValue(arguments).(map[string]interface{})
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if isSecretKey(key) {
+				copied[key] = "[REDACTED]"
+				continue
+			}
+			copied[key] = redactValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = redactValue(item)
+		}
+		return copied
+	}
+	return value
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range []string{"token", "password", "secret", "authorization", "api_key", "apikey", "credential"} {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// newAuditRecordID returns an ID that sorts by time and is unique across processes
+func newAuditRecordID(now time.Time) string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+	return fmt.Sprintf("%d-%s", now.UnixMilli(), hex.EncodeToString(buf))
+}
+
+func toolResultText(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func openTestAuditLog(t *testing.T, config AuditLogConfig) string {
+	t.Helper()
+	if config.Path == "" {
+		config.Path = filepath.Join(t.TempDir(), "audit.jsonl")
+	}
+	if err := OpenAuditLog(config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if l := currentAuditLog.Swap(nil); l != nil {
+			l.close()
+		}
+	})
+	return config.Path
+}
+
+func readAuditRecords(t *testing.T, path string) []AuditRecord {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid audit line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestRedactArguments(t *testing.T) {
+	arguments := map[string]interface{}{
+		"issue_key":          "KP-1",
+		"confirmation_token": "abc",
+		"custom_fields": map[string]interface{}{
+			"API_Key": "secret",
+			"Team":    "Platform",
+		},
+		"items": []interface{}{map[string]interface{}{"password": "hunter2"}},
+	}
+
+	want := map[string]interface{}{
+		"issue_key":          "KP-1",
+		"confirmation_token": "[REDACTED]",
+		"custom_fields": map[string]interface{}{
+			"API_Key": "[REDACTED]",
+			"Team":    "Platform",
+		},
+		"items": []interface{}{map[string]interface{}{"password": "[REDACTED]"}},
+	}
+	if got := redactArguments(arguments); !reflect.DeepEqual(got, want) {
+		t.Errorf("redactArguments() = %v, want %v", got, want)
+	}
+	if arguments["confirmation_token"] != "abc" {
+		t.Error("redactArguments changed its input")
+	}
+}
+
+func TestAuditMutations(t *testing.T) {
+	path := openTestAuditLog(t, AuditLogConfig{})
+	mutatingTools.Store("jira_test_update", true)
+	defer mutatingTools.Delete("jira_test_update")
+
+	handler := AuditMutations(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.GetArguments()["fail"] == true {
+			auditRequest(ctx, &models.ResponseScheme{Code: 400}, "KP-2")
+			return nil, errors.New("failed to update issue")
+		}
+		auditRequest(ctx, &models.ResponseScheme{Code: 204}, "KP-1")
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(name string, arguments map[string]interface{}) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = arguments
+		handler(context.Background(), request)
+	}
+	call("jira_test_update", map[string]interface{}{"issue_key": "KP-1", "token": "abc"})
+	call("jira_get_issue", map[string]interface{}{"issue_key": "KP-1"})
+	call("jira_test_update", map[string]interface{}{"fail": true})
+
+	records := readAuditRecords(t, path)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (read-only tools are not audited): %+v", len(records), records)
+	}
+
+	if records[0].ID == "" || records[0].ID == records[1].ID {
+		t.Errorf("records need distinct IDs: %q, %q", records[0].ID, records[1].ID)
+	}
+	if record := records[0]; !record.Success || record.StatusCode != 204 || !reflect.DeepEqual(record.IssueKeys, []string{"KP-1"}) || record.Arguments["token"] != "[REDACTED]" {
+		t.Errorf("successful call: %+v", record)
+	}
+	if record := records[1]; record.Success || record.StatusCode != 400 || record.Error != "failed to update issue" {
+		t.Errorf("failed call: %+v", record)
+	}
+}
+
+func TestAuditRequestKeepsFailureCode(t *testing.T) {
+	entry := &auditEntry{}
+	ctx := context.WithValue(context.Background(), auditContextKey{}, entry)
+
+	auditRequest(ctx, &models.ResponseScheme{Code: 201}, "KP-1")
+	auditRequest(ctx, &models.ResponseScheme{Code: 400}, "KP-2")
+	auditRequest(ctx, &models.ResponseScheme{Code: 201}, "KP-3", "KP-1")
+
+	record := entry.snapshot()
+	if record.StatusCode != 400 || !reflect.DeepEqual(record.IssueKeys, []string{"KP-1", "KP-2", "KP-3"}) {
+		t.Errorf("record = %+v", record)
+	}
+
+	// Without an audited call nothing is recorded
+	auditRequest(context.Background(), &models.ResponseScheme{Code: 500}, "KP-4")
+}
+
+func TestAuditLogRotation(t *testing.T) {
+	path := openTestAuditLog(t, AuditLogConfig{MaxSizeBytes: 200, MaxBackups: 2})
+	l := currentAuditLog.Load()
+
+	for i := 0; i < 10; i++ {
+		if err := l.write(AuditRecord{Tool: "jira_update_issue", IssueKeys: []string{"KP-1"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatalf("missing %s: %v", name, err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("%s has %d bytes, more than the maximum", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, stat .3: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// confirmationTTL is how long a confirmation token from a preview stays valid
+const confirmationTTL = 10 * time.Minute
+
+var confirmDestructive atomic.Bool
+
+// SetConfirmDestructive turns on confirmation mode: delete, bulk and transition tools then
+// return a preview and a confirmation token, and only act when called again with the token
+func SetConfirmDestructive(enabled bool) {
+	confirmDestructive.Store(enabled)
+}
+
+// ConfirmationInput is embedded in the input of tools that honour confirmation mode
+type ConfirmationInput struct {
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// ConfirmationOutput is embedded in the output of tools that honour confirmation mode.
+// It is only set on a preview, when nothing was changed.
+type ConfirmationOutput struct {
+	ConfirmationToken   string `json:"confirmation_token,omitempty" jsonschema_description:"Set on a preview: call the tool again with the same arguments and this token to run the action"`
+	ConfirmationExpires string `json:"confirmation_expires,omitempty"`
+}
+
+// withConfirmation declares the confirmation_token argument
+func withConfirmation() mcp.ToolOption {
+	return mcp.WithString("confirmation_token", mcp.Description("Token from a preview, required to run the action when the server asks for confirmation of destructive actions"))
+}
+
+// pendingConfirmation is a preview waiting to be confirmed
+type pendingConfirmation struct {
+	fingerprint string
+	expires     time.Time
+}
+
+var confirmations = struct {
+	sync.Mutex
+	pending map[string]pendingConfirmation
+}{pending: map[string]pendingConfirmation{}}
+
+// requireConfirmation decides whether a destructive call may run. It returns nil when
+// confirmation mode is off or the call carries a valid token for the same arguments and
+// user, which is then used up. Otherwise it returns a new token for the caller to show with
+// its preview.
+func requireConfirmation(ctx context.Context, request mcp.CallToolRequest, token string) (*ConfirmationOutput, error) {
+	if !confirmDestructive.Load() {
+		return nil, nil
+	}
+
+	fingerprint, err := confirmationFingerprint(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	confirmations.Lock()
+	defer confirmations.Unlock()
+
+	// Expired previews are evicted on every call, so abandoned ones do not pile up
+	now := time.Now()
+	for id, pending := range confirmations.pending {
+		if now.After(pending.expires) {
+			delete(confirmations.pending, id)
+		}
+	}
+
+	if token != "" {
+		pending, ok := confirmations.pending[token]
+		if !ok {
+			return nil, fmt.Errorf("confirmation token is invalid or expired, call the tool without it to get a new preview")
+		}
+		if pending.fingerprint != fingerprint {
+			return nil, fmt.Errorf("confirmation token was issued for a different call, the arguments must match the preview")
+		}
+		delete(confirmations.pending, token)
+		return nil, nil
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to create confirmation token: %w", err)
+	}
+	token = hex.EncodeToString(buf)
+	expires := now.Add(confirmationTTL)
+	confirmations.pending[token] = pendingConfirmation{fingerprint: fingerprint, expires: expires}
+
+	return &ConfirmationOutput{ConfirmationToken: token, ConfirmationExpires: expires.UTC().Format(time.RFC3339)}, nil
+}
+
+// confirmationFingerprint identifies a call by the user making it, its tool and its arguments,
+// leaving out the token itself and the output format, which do not change what the call does.
+// The user is part of it so that a token leaked to another session cannot confirm its preview.
+func confirmationFingerprint(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+	arguments := map[string]interface{}{}
+	for key, value := range request.GetArguments() {
+		switch key {
+		case "confirmation_token", "output_format":
+			continue
+		}
+		arguments[key] = value
+	}
+
+	// encoding/json sorts map keys, so equal arguments encode the same way
+	encoded, err := json.Marshal(arguments)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	actor, err := services.Actor(ctx)
+	if err != nil {
+		return "", err
+	}
+	return actor + "\n" + request.Params.Name + ":" + string(encoded), nil
+}
+
+// confirmationText is appended to the text of a preview
+func confirmationText(confirmation *ConfirmationOutput) string {
+	return fmt.Sprintf("\nNothing was changed. To run this, call the tool again with the same arguments and confirmation_token=%s (valid until %s)\n",
+		confirmation.ConfirmationToken, confirmation.ConfirmationExpires)
+}
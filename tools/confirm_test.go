@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func confirmationRequest(arguments map[string]interface{}) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "jira_delete_issue"
+	request.Params.Arguments = arguments
+	return request
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	SetConfirmDestructive(false)
+	if confirmation, err := requireConfirmation(context.Background(), confirmationRequest(map[string]interface{}{"issue_key": "KP-1"}), ""); confirmation != nil || err != nil {
+		t.Fatalf("confirmation mode off: got %+v, %v", confirmation, err)
+	}
+
+	SetConfirmDestructive(true)
+	defer SetConfirmDestructive(false)
+
+	preview, err := requireConfirmation(context.Background(), confirmationRequest(map[string]interface{}{"issue_key": "KP-1"}), "")
+	if err != nil || preview == nil || preview.ConfirmationToken == "" {
+		t.Fatalf("preview = %+v, %v", preview, err)
+	}
+	token := preview.ConfirmationToken
+
+	// The token is bound to the arguments of the preview
+	if _, err := requireConfirmation(context.Background(), confirmationRequest(map[string]interface{}{"issue_key": "KP-2", "confirmation_token": token}), token); err == nil {
+		t.Error("expected a token to be rejected for other arguments")
+	}
+
+	// The output format does not change what the call does
+	arguments := map[string]interface{}{"issue_key": "KP-1", "confirmation_token": token, "output_format": "json"}
+	if confirmation, err := requireConfirmation(context.Background(), confirmationRequest(arguments), token); confirmation != nil || err != nil {
+		t.Fatalf("confirmed call: got %+v, %v", confirmation, err)
+	}
+
+	if _, err := requireConfirmation(context.Background(), confirmationRequest(arguments), token); err == nil {
+		t.Error("expected a used token to be rejected")
+	}
+}
+
+func TestConfirmationIsBoundToTheUser(t *testing.T) {
+	SetConfirmDestructive(true)
+	defer SetConfirmDestructive(false)
+
+	actorContext := userContexts(t)
+	alice, bob := actorContext("alice@example.com", "alice-token"), actorContext("bob@example.com", "bob-token")
+
+	preview, err := requireConfirmation(alice, confirmationRequest(map[string]interface{}{"issue_key": "KP-1"}), "")
+	if err != nil || preview == nil {
+		t.Fatalf("preview = %+v, %v", preview, err)
+	}
+	arguments := map[string]interface{}{"issue_key": "KP-1", "confirmation_token": preview.ConfirmationToken}
+
+	if _, err := requireConfirmation(bob, confirmationRequest(arguments), preview.ConfirmationToken); err == nil {
+		t.Error("expected another user's token to be rejected")
+	}
+	// Sending Alice's email does not make a caller Alice
+	if _, err := requireConfirmation(actorContext("alice@example.com", "bob-token"), confirmationRequest(arguments), preview.ConfirmationToken); err == nil {
+		t.Error("expected a token to be rejected for forged credentials")
+	}
+	if confirmation, err := requireConfirmation(alice, confirmationRequest(arguments), preview.ConfirmationToken); confirmation != nil || err != nil {
+		t.Fatalf("confirmed call: got %+v, %v", confirmation, err)
+	}
+}
+
+func TestExpiredConfirmationsAreEvicted(t *testing.T) {
+	SetConfirmDestructive(true)
+	defer SetConfirmDestructive(false)
+
+	ctx := context.Background()
+	preview, err := requireConfirmation(ctx, confirmationRequest(map[string]interface{}{"issue_key": "KP-1"}), "")
+	if err != nil || preview == nil {
+		t.Fatalf("preview = %+v, %v", preview, err)
+	}
+
+	confirmations.Lock()
+	pending := confirmations.pending[preview.ConfirmationToken]
+	pending.expires = time.Now().Add(-time.Second)
+	confirmations.pending[preview.ConfirmationToken] = pending
+	confirmations.Unlock()
+
+	// Any call sweeps the expired preview
+	if _, err := requireConfirmation(ctx, confirmationRequest(map[string]interface{}{"issue_key": "KP-2"}), ""); err != nil {
+		t.Fatal(err)
+	}
+	confirmations.Lock()
+	_, ok := confirmations.pending[preview.ConfirmationToken]
+	confirmations.Unlock()
+	if ok {
+		t.Error("expired confirmation was not evicted")
+	}
+
+	arguments := map[string]interface{}{"issue_key": "KP-1", "confirmation_token": preview.ConfirmationToken}
+	if _, err := requireConfirmation(ctx, confirmationRequest(arguments), preview.ConfirmationToken); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expected an expired token to be rejected, got %v", err)
+	}
+}
@@ -14,18 +14,30 @@ import (
 
 type DownloadAttachmentInput struct {
 	AttachmentID string `json:"attachment_id" validate:"required"`
+	OutputFormatInput
+}
+
+// DownloadAttachmentOutput is the result of jira_download_attachment
+type DownloadAttachmentOutput struct {
+	AttachmentID string `json:"attachment_id"`
+	FilePath     string `json:"file_path" jsonschema_description:"Absolute path of the downloaded file"`
+	Filename     string `json:"filename"`
+	Size         int    `json:"size"`
+	MimeType     string `json:"mime_type,omitempty"`
 }
 
 func RegisterJiraAttachmentTool(s *server.MCPServer) {
 	tool := mcp.NewTool("jira_download_attachment",
+		readOnlyTool(),
 		mcp.WithDescription("Download a Jira attachment to a local temporary file and return the absolute file path. Use attachment IDs from jira_get_issue output."),
 		mcp.WithString("attachment_id", mcp.Required(), mcp.Description("The ID of the attachment to download (e.g., 10010)")),
+		withOutputFormat[DownloadAttachmentOutput](),
 	)
-	s.AddTool(tool, mcp.NewTypedToolHandler(jiraDownloadAttachmentHandler))
+	addTool(s, tool, mcp.NewTypedToolHandler(jiraDownloadAttachmentHandler))
 }
 
 func jiraDownloadAttachmentHandler(ctx context.Context, request mcp.CallToolRequest, input DownloadAttachmentInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	// Get attachment metadata to know the filename
 	metadata, response, err := client.Issue.Attachment.Metadata(ctx, input.AttachmentID)
@@ -68,5 +80,12 @@ func jiraDownloadAttachmentHandler(ctx context.Context, request mcp.CallToolRequ
 	result := fmt.Sprintf("Attachment downloaded successfully!\nFile: %s\nFilename: %s\nSize: %d bytes\nMIME Type: %s",
 		filePath, metadata.Filename, metadata.Size, metadata.MimeType)
 
-	return mcp.NewToolResultText(result), nil
+	output := DownloadAttachmentOutput{
+		AttachmentID: input.AttachmentID,
+		FilePath:     filePath,
+		Filename:     metadata.Filename,
+		Size:         metadata.Size,
+		MimeType:     metadata.MimeType,
+	}
+	return formatResult(input.OutputFormat, output, result)
 }
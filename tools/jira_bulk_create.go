@@ -0,0 +1,591 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Input types for typed tools
+type BulkCreateIssuesInput struct {
+	ProjectKey string           `json:"project_key" validate:"required"`
+	Issues     []BulkIssueInput `json:"issues" validate:"required"`
+	ConfirmationInput
+	OutputFormatInput
+}
+
+// BulkIssueInput is one issue of a bulk create plan, with its children nested below it
+type BulkIssueInput struct {
+	LocalID     string           `json:"local_id,omitempty"`
+	ProjectKey  string           `json:"project_key,omitempty"`
+	Summary     string           `json:"summary"`
+	Description string           `json:"description,omitempty"`
+	IssueType   string           `json:"issue_type,omitempty"`
+	Links       []BulkLinkInput  `json:"links,omitempty"`
+	Children    []BulkIssueInput `json:"children,omitempty"`
+	IssueFieldsInput
+}
+
+// BulkLinkInput links a planned issue to another planned issue or an existing one
+type BulkLinkInput struct {
+	Type      string `json:"type"`
+	Issue     string `json:"issue"`
+	Direction string `json:"direction,omitempty"`
+}
+
+// BulkCreatedIssueOutput is an issue created by jira_bulk_create_issues
+type BulkCreatedIssueOutput struct {
+	LocalID string `json:"local_id"`
+	Key     string `json:"key"`
+	ID      string `json:"id"`
+	Parent  string `json:"parent,omitempty" jsonschema_description:"Key of the parent issue"`
+}
+
+// BulkItemErrorOutput is an issue or link of the plan that could not be created
+type BulkItemErrorOutput struct {
+	LocalID string `json:"local_id"`
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error"`
+}
+
+// BulkCreateIssuesOutput is the result of jira_bulk_create_issues
+type BulkCreateIssuesOutput struct {
+	Keys    map[string]string        `json:"keys" jsonschema_description:"Created issue keys by local ID"`
+	Created []BulkCreatedIssueOutput `json:"created"`
+	Links   int                      `json:"links" jsonschema_description:"Number of links created"`
+	Errors  []BulkItemErrorOutput    `json:"errors,omitempty" jsonschema_description:"Items that failed; their children were not created"`
+	ConfirmationOutput
+}
+
+const (
+	// bulkCreateBatchSize is the most issues the bulk create endpoint accepts per request
+	bulkCreateBatchSize = 50
+	maxBulkCreateIssues = 250
+)
+
+var issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-\d+$`)
+
+func RegisterJiraBulkCreateTool(s *server.MCPServer) {
+	linkSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"type":      map[string]any{"type": "string", "description": "Link type name (e.g., Blocks, Relates)"},
+			"issue":     map[string]any{"type": "string", "description": "local_id of another issue in the plan, or the key of an existing issue"},
+			"direction": map[string]any{"type": "string", "enum": []string{"outward", "inward"}, "description": "outward (default): this issue blocks the other; inward: this issue is blocked by the other"},
+		},
+		"required": []string{"type", "issue"},
+	}
+
+	issueSchema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"local_id":      map[string]any{"type": "string", "description": "Identifier used to reference this issue from links and in the result (default: its position, e.g. 1.2)"},
+			"project_key":   map[string]any{"type": "string", "description": "Project of this issue (default: its parent's project or the top-level project_key)"},
+			"summary":       map[string]any{"type": "string"},
+			"description":   map[string]any{"type": "string", "description": "Description in markdown"},
+			"issue_type":    map[string]any{"type": "string", "description": "Required for top-level issues. Children default to the type one level below their parent (a story under an epic, a subtask under a story)"},
+			"assignee":      map[string]any{"type": "string", "description": "Account ID of the assignee"},
+			"priority":      map[string]any{"type": "string"},
+			"labels":        map[string]any{"type": "string", "description": "Comma-separated labels"},
+			"components":    map[string]any{"type": "string", "description": "Comma-separated component names"},
+			"fix_versions":  map[string]any{"type": "string", "description": "Comma-separated fix version names"},
+			"due_date":      map[string]any{"type": "string", "description": "YYYY-MM-DD"},
+			"custom_fields": map[string]any{"type": "object", "description": "Custom field values keyed by field name or ID"},
+			"links":         map[string]any{"type": "array", "items": linkSchema},
+			"children":      map[string]any{"type": "array", "description": "Child issues, with the same shape as this issue", "items": map[string]any{"type": "object"}},
+		},
+		"required": []string{"summary"},
+	}
+
+	jiraBulkCreateIssuesTool := mcp.NewTool("jira_bulk_create_issues",
+		additiveTool(),
+		mcp.WithDescription("Create a tree of issues (e.g., an epic with stories and subtasks) in as few requests as possible. Parents are created before their children, links can reference other issues of the plan by local_id, and the result maps each local_id to its created key. Failed items are reported with their error, and their children are skipped"),
+		mcp.WithString("project_key", mcp.Required(), mcp.Description("Default project for the issues (e.g., KP)")),
+		mcp.WithArray("issues", mcp.Required(), mcp.Items(issueSchema), mcp.Description("Top-level issues of the plan, with their children nested under children")),
+		withConfirmation(),
+		withOutputFormat[BulkCreateIssuesOutput](),
+	)
+	addTool(s, jiraBulkCreateIssuesTool, mcp.NewTypedToolHandler(jiraBulkCreateIssuesHandler))
+}
+
+// plannedIssue is an issue of the plan flattened with the position it was given in
+type plannedIssue struct {
+	BulkIssueInput
+	parent *plannedIssue
+	depth  int
+	key    string
+	id     string
+	failed bool
+}
+
+func jiraBulkCreateIssuesHandler(ctx context.Context, request mcp.CallToolRequest, input BulkCreateIssuesInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	planned, err := flattenIssuePlan(input.Issues, input.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPlanPolicy(ctx, client, planned); err != nil {
+		return nil, err
+	}
+
+	output := BulkCreateIssuesOutput{Keys: map[string]string{}, Created: []BulkCreatedIssueOutput{}}
+
+	confirmation, err := requireConfirmation(ctx, request, input.ConfirmationToken)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation != nil {
+		output.ConfirmationOutput = *confirmation
+		return formatResult(input.OutputFormat, output, formatIssuePlan(planned)+confirmationText(confirmation))
+	}
+	fail := func(issue *plannedIssue, err error) {
+		issue.failed = true
+		output.Errors = append(output.Errors, BulkItemErrorOutput{LocalID: issue.LocalID, Summary: issue.Summary, Error: err.Error()})
+	}
+
+	// Issue types are looked up once per project to default the children's types
+	projectIssueTypes := map[string][]*models.IssueTypeScheme{}
+	issueTypesOf := func(projectKey string) ([]*models.IssueTypeScheme, error) {
+		if issueTypes, ok := projectIssueTypes[projectKey]; ok {
+			return issueTypes, nil
+		}
+		issueTypes, err := getProjectIssueTypes(ctx, client, projectKey)
+		if err != nil {
+			return nil, err
+		}
+		projectIssueTypes[projectKey] = issueTypes
+		return issueTypes, nil
+	}
+
+	// Issues are created level by level so every parent has a key before its children are sent
+	for depth := 0; ; depth++ {
+		var batch []*plannedIssue
+		var payloads []map[string]interface{}
+
+		for _, issue := range planned {
+			if issue.depth != depth {
+				continue
+			}
+			if issue.parent != nil && issue.parent.failed {
+				fail(issue, fmt.Errorf("parent %s was not created", issue.parent.LocalID))
+				continue
+			}
+
+			payload, err := buildBulkIssuePayload(ctx, client, issue, issueTypesOf)
+			if err != nil {
+				fail(issue, err)
+				continue
+			}
+			batch = append(batch, issue)
+			payloads = append(payloads, payload)
+		}
+
+		if len(batch) == 0 && !hasDepth(planned, depth) {
+			break
+		}
+
+		for start := 0; start < len(batch); start += bulkCreateBatchSize {
+			end := start + bulkCreateBatchSize
+			if end > len(batch) {
+				end = len(batch)
+			}
+
+			results, err := bulkCreateIssues(ctx, client, payloads[start:end])
+			if err != nil {
+				for _, issue := range batch[start:end] {
+					fail(issue, err)
+				}
+				continue
+			}
+
+			for i, result := range results {
+				issue := batch[start+i]
+				if result.err != nil {
+					fail(issue, result.err)
+					continue
+				}
+
+				issue.key = result.key
+				issue.id = result.id
+				auditRequest(ctx, nil, issue.key)
+				auditCreated(ctx, AuditResource{Type: auditResourceIssue, Issue: issue.key})
+				output.Keys[issue.LocalID] = issue.key
+
+				created := BulkCreatedIssueOutput{LocalID: issue.LocalID, Key: issue.key, ID: issue.id}
+				if issue.parent != nil {
+					created.Parent = issue.parent.key
+				}
+				output.Created = append(output.Created, created)
+			}
+		}
+	}
+
+	for _, issue := range planned {
+		if issue.key == "" {
+			continue
+		}
+		for _, link := range issue.Links {
+			if err := createBulkLink(ctx, client, issue, link, output.Keys); err != nil {
+				output.Errors = append(output.Errors, BulkItemErrorOutput{
+					LocalID: issue.LocalID,
+					Summary: issue.Summary,
+					Error:   fmt.Sprintf("link %s %s: %v", link.Type, link.Issue, err),
+				})
+				continue
+			}
+			output.Links++
+		}
+	}
+
+	return formatResult(input.OutputFormat, output, formatBulkCreateResult(planned, output))
+}
+
+// flattenIssuePlan lists the issues of the plan depth-first, giving issues without a
+// local_id their position ("1", "1.2", ...) and checking that links point somewhere
+func flattenIssuePlan(issues []BulkIssueInput, projectKey string) ([]*plannedIssue, error) {
+	var planned []*plannedIssue
+	localIDs := map[string]bool{}
+
+	var walk func(issues []BulkIssueInput, parent *plannedIssue, prefix string, depth int) error
+	walk = func(issues []BulkIssueInput, parent *plannedIssue, prefix string, depth int) error {
+		for i, input := range issues {
+			position := prefix + strconv.Itoa(i+1)
+
+			issue := &plannedIssue{BulkIssueInput: input, parent: parent, depth: depth}
+			if issue.LocalID == "" {
+				issue.LocalID = position
+			}
+			if localIDs[issue.LocalID] {
+				return fmt.Errorf("local_id %q is used by more than one issue", issue.LocalID)
+			}
+			localIDs[issue.LocalID] = true
+
+			if strings.TrimSpace(issue.Summary) == "" {
+				return fmt.Errorf("issue %s has no summary", issue.LocalID)
+			}
+			if issue.ProjectKey == "" {
+				issue.ProjectKey = projectKey
+				if parent != nil {
+					issue.ProjectKey = parent.ProjectKey
+				}
+			}
+			if parent == nil && issue.IssueType == "" {
+				return fmt.Errorf("top-level issue %s has no issue_type", issue.LocalID)
+			}
+			if parent != nil && issue.Parent != "" {
+				return fmt.Errorf("issue %s is nested under %s and cannot also set parent", issue.LocalID, parent.LocalID)
+			}
+
+			planned = append(planned, issue)
+			if len(planned) > maxBulkCreateIssues {
+				return fmt.Errorf("the plan has more than %d issues, split it into several calls", maxBulkCreateIssues)
+			}
+
+			if err := walk(input.Children, issue, position+".", depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(issues, nil, "", 0); err != nil {
+		return nil, err
+	}
+
+	if len(planned) == 0 {
+		return nil, fmt.Errorf("issues is empty")
+	}
+
+	for _, issue := range planned {
+		for _, link := range issue.Links {
+			if link.Type == "" {
+				return nil, fmt.Errorf("a link of issue %s has no type", issue.LocalID)
+			}
+			if !localIDs[link.Issue] && !issueKeyPattern.MatchString(link.Issue) {
+				return nil, fmt.Errorf("issue %s links to %q, which is neither a local_id of the plan nor an issue key", issue.LocalID, link.Issue)
+			}
+			switch link.Direction {
+			case "", "outward", "inward":
+			default:
+				return nil, fmt.Errorf("issue %s has a link with invalid direction %q: must be outward or inward", issue.LocalID, link.Direction)
+			}
+		}
+	}
+
+	return planned, nil
+}
+
+// checkPlanPolicy refuses a plan before anything is created when the write policy forbids
+// creating one of its issues or linking to an existing issue
+func checkPlanPolicy(ctx context.Context, client *jira.Client, planned []*plannedIssue) error {
+	for _, issue := range planned {
+		if err := checkWritePolicy(actionCreate, issue.ProjectKey, issue.IssueType); err != nil {
+			return fmt.Errorf("issue %s: %w", issue.LocalID, err)
+		}
+		for _, link := range issue.Links {
+			if err := checkWritePolicy(actionLink, issue.ProjectKey, issue.IssueType); err != nil {
+				return fmt.Errorf("issue %s: %w", issue.LocalID, err)
+			}
+			if issueKeyPattern.MatchString(link.Issue) {
+				if err := checkIssuePolicy(ctx, client, actionLink, link.Issue); err != nil {
+					return fmt.Errorf("issue %s: %w", issue.LocalID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func hasDepth(planned []*plannedIssue, depth int) bool {
+	for _, issue := range planned {
+		if issue.depth == depth {
+			return true
+		}
+	}
+	return false
+}
+
+// buildBulkIssuePayload builds the create payload of a planned issue once its parent exists
+func buildBulkIssuePayload(ctx context.Context, client *jira.Client, issue *plannedIssue, issueTypesOf func(string) ([]*models.IssueTypeScheme, error)) (map[string]interface{}, error) {
+	fieldsInput := issue.IssueFieldsInput
+	issueType := issue.IssueType
+
+	if issue.parent != nil {
+		fieldsInput.Parent = issue.parent.key
+
+		if issueType == "" {
+			issueTypes, err := issueTypesOf(issue.ProjectKey)
+			if err != nil {
+				return nil, err
+			}
+
+			parentLevel := 0
+			for _, candidate := range issueTypes {
+				if strings.EqualFold(candidate.Name, issue.parent.IssueType) || candidate.ID == issue.parent.IssueType {
+					parentLevel = candidate.HierarchyLevel
+				}
+			}
+
+			issueType, err = childIssueType(issueTypes, issue.ProjectKey, parentLevel)
+			if err != nil {
+				return nil, err
+			}
+			// Grandchildren look up the level of the type that was picked
+			issue.IssueType = issueType
+		}
+	}
+
+	// Children's types are only known now, for rules limited to some issue types
+	if err := checkWritePolicy(actionCreate, issue.ProjectKey, issueType); err != nil {
+		return nil, err
+	}
+
+	fields := &models.IssueFieldsScheme{
+		Summary:   issue.Summary,
+		Project:   &models.ProjectScheme{Key: issue.ProjectKey},
+		IssueType: &models.IssueTypeScheme{Name: issueType},
+	}
+	if issue.Description != "" {
+		fields.Description = util.MarkdownToADF(issue.Description)
+	}
+
+	payload, err := buildIssuePayload(fields, fieldsInput)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyCustomFields(ctx, client, payload, issue.CustomFields); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// bulkCreateResult is the outcome of one issue of a bulk create request
+type bulkCreateResult struct {
+	key string
+	id  string
+	err error
+}
+
+type bulkCreateResponse struct {
+	Issues []struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	} `json:"issues"`
+	Errors []struct {
+		Status        int `json:"status"`
+		ElementErrors struct {
+			ErrorMessages []string          `json:"errorMessages"`
+			Errors        map[string]string `json:"errors"`
+		} `json:"elementErrors"`
+		FailedElementNumber int `json:"failedElementNumber"`
+	} `json:"errors"`
+}
+
+// bulkCreateIssues creates up to bulkCreateBatchSize issues in one request and returns the
+// outcome of each payload in order. Jira answers 400 when every issue failed, with the same
+// per-issue errors as a partial success, so those are read from the error body as well.
+func bulkCreateIssues(ctx context.Context, client *jira.Client, payloads []map[string]interface{}) ([]bulkCreateResult, error) {
+	if isServer(ctx) {
+		for _, payload := range payloads {
+			toServerIssuePayload(payload)
+		}
+	}
+
+	req, err := client.NewRequest(ctx, http.MethodPost, restAPI(ctx, "issue/bulk"), "", map[string]interface{}{"issueUpdates": payloads})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var body bulkCreateResponse
+	response, err := client.Call(req, &body)
+	auditRequest(ctx, response)
+	if err != nil {
+		if response == nil {
+			return nil, fmt.Errorf("failed to create issues: %v", err)
+		}
+		if jsonErr := json.Unmarshal(response.Bytes.Bytes(), &body); jsonErr != nil || len(body.Errors) == 0 {
+			return nil, fmt.Errorf("failed to create issues: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+	}
+
+	return bulkCreateResults(body, len(payloads)), nil
+}
+
+// bulkCreateResults matches the response to the payloads: failed payloads are named by
+// their index, and created issues are listed in order for the others
+func bulkCreateResults(body bulkCreateResponse, count int) []bulkCreateResult {
+	results := make([]bulkCreateResult, count)
+
+	for _, failure := range body.Errors {
+		if failure.FailedElementNumber < 0 || failure.FailedElementNumber >= count {
+			continue
+		}
+
+		messages := append([]string{}, failure.ElementErrors.ErrorMessages...)
+		fieldIDs := make([]string, 0, len(failure.ElementErrors.Errors))
+		for field := range failure.ElementErrors.Errors {
+			fieldIDs = append(fieldIDs, field)
+		}
+		sort.Strings(fieldIDs)
+		for _, field := range fieldIDs {
+			messages = append(messages, fmt.Sprintf("%s: %s", field, failure.ElementErrors.Errors[field]))
+		}
+		if len(messages) == 0 {
+			messages = append(messages, fmt.Sprintf("status %d", failure.Status))
+		}
+
+		results[failure.FailedElementNumber].err = fmt.Errorf("%s", strings.Join(messages, "; "))
+	}
+
+	created := body.Issues
+	for i := range results {
+		if results[i].err != nil {
+			continue
+		}
+		if len(created) == 0 {
+			results[i].err = fmt.Errorf("the issue is missing from the bulk create response")
+			continue
+		}
+		results[i].key = created[0].Key
+		results[i].id = created[0].ID
+		created = created[1:]
+	}
+
+	return results
+}
+
+// createBulkLink links a created issue to a planned or existing issue.
+// The link API reads inwardIssue as the issue the outward description applies to.
+func createBulkLink(ctx context.Context, client *jira.Client, issue *plannedIssue, link BulkLinkInput, keys map[string]string) error {
+	target := link.Issue
+	if key, ok := keys[target]; ok {
+		target = key
+	} else if !issueKeyPattern.MatchString(target) {
+		return fmt.Errorf("%s was not created", link.Issue)
+	}
+
+	payload := &models.LinkPayloadSchemeV3{
+		InwardIssue:  &models.LinkedIssueScheme{Key: issue.key},
+		OutwardIssue: &models.LinkedIssueScheme{Key: target},
+		Type:         &models.LinkTypeScheme{Name: link.Type},
+	}
+	if link.Direction == "inward" {
+		payload.InwardIssue, payload.OutwardIssue = payload.OutwardIssue, payload.InwardIssue
+	}
+
+	response, err := createIssueLink(ctx, client, payload)
+	auditRequest(ctx, response, issue.key, target)
+	if err != nil {
+		if response != nil {
+			return fmt.Errorf("%s", response.Bytes.String())
+		}
+		return err
+	}
+
+	auditCreated(ctx, AuditResource{Type: auditResourceLink, Issue: payload.InwardIssue.Key, Target: payload.OutwardIssue.Key, LinkType: link.Type})
+	return nil
+}
+
+// formatIssuePlan describes the issues a plan would create, for confirmation mode
+func formatIssuePlan(planned []*plannedIssue) string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Preview: %d issues would be created\n\n", len(planned)))
+	for _, issue := range planned {
+		issueType := issue.IssueType
+		if issueType == "" {
+			issueType = "child type"
+		}
+		result.WriteString(fmt.Sprintf("%s- %s: %s (%s, %s)\n", strings.Repeat("  ", issue.depth), issue.LocalID, issue.Summary, issueType, issue.ProjectKey))
+		for _, link := range issue.Links {
+			result.WriteString(fmt.Sprintf("%s  link %s %s\n", strings.Repeat("  ", issue.depth), link.Type, link.Issue))
+		}
+	}
+
+	return result.String()
+}
+
+func formatBulkCreateResult(planned []*plannedIssue, output BulkCreateIssuesOutput) string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Created %d of %d issues", len(output.Created), len(planned)))
+	if output.Links > 0 {
+		result.WriteString(fmt.Sprintf(" and %d links", output.Links))
+	}
+	result.WriteString("\n")
+
+	if len(output.Created) > 0 {
+		result.WriteString("\nCreated:\n")
+		for _, issue := range planned {
+			if issue.key == "" {
+				continue
+			}
+			result.WriteString(fmt.Sprintf("%s- %s: %s (%s, %s)\n", strings.Repeat("  ", issue.depth), issue.LocalID, issue.key, issue.IssueType, issue.Summary))
+		}
+	}
+
+	if len(output.Errors) > 0 {
+		result.WriteString("\nErrors:\n")
+		for _, item := range output.Errors {
+			result.WriteString(fmt.Sprintf("- %s (%s): %s\n", item.LocalID, item.Summary, item.Error))
+		}
+	}
+
+	return result.String()
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFlattenIssuePlan(t *testing.T) {
+	var issues []BulkIssueInput
+	err := json.Unmarshal([]byte(`[
+		{"local_id": "epic", "summary": "Checkout", "issue_type": "Epic", "children": [
+			{"local_id": "api", "summary": "Payment API", "children": [{"summary": "Write handler"}]},
+			{"summary": "Payment form", "project_key": "WEB", "links": [{"type": "Blocks", "issue": "api", "direction": "inward"}]}
+		]},
+		{"summary": "Release notes", "issue_type": "Task", "links": [{"type": "Relates", "issue": "KP-12"}]}
+	]`), &issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	planned, err := flattenIssuePlan(issues, "KP")
+	if err != nil {
+		t.Fatalf("flattenIssuePlan() error = %v", err)
+	}
+
+	var ids []string
+	for _, issue := range planned {
+		ids = append(ids, issue.LocalID)
+	}
+	if got := strings.Join(ids, " "); got != "epic api 1.1.1 1.2 2" {
+		t.Errorf("local IDs = %q", got)
+	}
+
+	if planned[2].depth != 2 || planned[2].parent != planned[1] || planned[2].ProjectKey != "KP" {
+		t.Errorf("grandchild = %+v", planned[2])
+	}
+	if planned[3].ProjectKey != "WEB" {
+		t.Errorf("explicit project_key = %q, want WEB", planned[3].ProjectKey)
+	}
+}
+
+func TestFlattenIssuePlanErrors(t *testing.T) {
+	tests := map[string]string{
+		"duplicate local_id":  `[{"local_id": "a", "summary": "A", "issue_type": "Task"}, {"local_id": "a", "summary": "B", "issue_type": "Task"}]`,
+		"missing issue_type":  `[{"summary": "A"}]`,
+		"missing summary":     `[{"issue_type": "Task"}]`,
+		"unknown link target": `[{"summary": "A", "issue_type": "Task", "links": [{"type": "Blocks", "issue": "nowhere"}]}]`,
+		"nested with parent":  `[{"summary": "A", "issue_type": "Epic", "children": [{"summary": "B", "parent": "KP-1"}]}]`,
+		"empty plan":          `[]`,
+	}
+
+	for name, body := range tests {
+		var issues []BulkIssueInput
+		if err := json.Unmarshal([]byte(body), &issues); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := flattenIssuePlan(issues, "KP"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBulkCreateResults(t *testing.T) {
+	var body bulkCreateResponse
+	err := json.Unmarshal([]byte(`{
+		"issues": [{"id": "100", "key": "KP-1"}, {"id": "102", "key": "KP-3"}],
+		"errors": [{"status": 400, "failedElementNumber": 1, "elementErrors": {"errors": {"components": "Component/s is required."}}}]
+	}`), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := bulkCreateResults(body, 3)
+
+	if results[0].key != "KP-1" || results[2].key != "KP-3" {
+		t.Errorf("keys = %q, %q", results[0].key, results[2].key)
+	}
+	if results[1].err == nil || !strings.Contains(results[1].err.Error(), "Component/s is required") {
+		t.Errorf("error = %v", results[1].err)
+	}
+}
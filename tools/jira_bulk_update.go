@@ -0,0 +1,468 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// Input types for typed tools
+type BulkUpdateInput struct {
+	JQL           string `json:"jql,omitempty"`
+	IssueKeys     string `json:"issue_keys,omitempty"`
+	TargetStatus  string `json:"target_status,omitempty"`
+	Apply         bool   `json:"apply,omitempty"`
+	ExpectedCount int    `json:"expected_count,omitempty"`
+	MaxIssues     int    `json:"max_issues,omitempty"`
+	Concurrency   int    `json:"concurrency,omitempty"`
+	ConfirmationInput
+	IssueFieldsInput
+	OutputFormatInput
+}
+
+// BulkUpdateIssueOutput is the preview or outcome of the change on one issue
+type BulkUpdateIssueOutput struct {
+	Key      string   `json:"key"`
+	Summary  string   `json:"summary,omitempty"`
+	Status   string   `json:"status,omitempty" jsonschema_description:"Status before the update"`
+	Assignee string   `json:"assignee,omitempty" jsonschema_description:"Assignee before the update"`
+	Success  bool     `json:"success" jsonschema_description:"Always false in a preview"`
+	Path     []string `json:"path,omitempty" jsonschema_description:"Statuses the issue moved through when target_status was set"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// BulkUpdateOutput is the result of jira_bulk_update
+type BulkUpdateOutput struct {
+	DryRun    bool                    `json:"dry_run" jsonschema_description:"True for a preview, nothing was changed"`
+	Changes   []string                `json:"changes" jsonschema_description:"The change set applied to every issue"`
+	Count     int                     `json:"count" jsonschema_description:"Number of selected issues, pass it as expected_count to apply"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Issues    []BulkUpdateIssueOutput `json:"issues"`
+	ConfirmationOutput
+}
+
+const (
+	defaultBulkUpdateLimit       = 100
+	maxBulkUpdateLimit           = 1000
+	defaultBulkUpdateConcurrency = 4
+	maxBulkUpdateConcurrency     = 10
+)
+
+func RegisterJiraBulkUpdateTool(s *server.MCPServer) {
+	jiraBulkUpdateTool := mcp.NewTool("jira_bulk_update", append([]mcp.ToolOption{
+		destructiveTool(false),
+		mcp.WithDescription("Apply one change set to many issues selected by JQL or by key: field edits, label and fix version changes, assignment and a transition to a named status. " +
+			"Without apply, returns a preview of the selected issues and the changes; call again with apply=true and expected_count from the preview to run it. Returns a per-issue success or failure report. The assignee may be an email, display name, account ID, 'me' or 'unassigned'"),
+		mcp.WithString("jql", mcp.Description("JQL selecting the issues (e.g., 'sprint = 42 AND status != Done'). Either jql or issue_keys is required")),
+		mcp.WithString("issue_keys", mcp.Description("Comma-separated issue keys (e.g., 'KP-1, KP-2')")),
+		mcp.WithString("target_status", mcp.Description("Status to move every issue to, walking intermediate workflow statuses when needed (e.g., Done)")),
+		mcp.WithBoolean("apply", mcp.Description("If true, apply the changes. Defaults to false, which only previews them")),
+		mcp.WithNumber("expected_count", mcp.Description("Number of issues from the preview, required with apply. The update is refused when the selection no longer has this many issues")),
+		mcp.WithNumber("max_issues", mcp.Description("Refuse selections larger than this (default: 100, max: 1000)")),
+		mcp.WithNumber("concurrency", mcp.Description("Number of issues updated in parallel (default: 4, max: 10)")),
+		withConfirmation(),
+		withOutputFormat[BulkUpdateOutput](),
+	}, issueFieldToolOptions()...)...)
+	addTool(s, jiraBulkUpdateTool, mcp.NewTypedToolHandler(jiraBulkUpdateHandler))
+}
+
+func jiraBulkUpdateHandler(ctx context.Context, request mcp.CallToolRequest, input BulkUpdateInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	jql, err := bulkSelectionJQL(input.JQL, input.IssueKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := input.MaxIssues
+	if limit <= 0 {
+		limit = defaultBulkUpdateLimit
+	}
+	if limit > maxBulkUpdateLimit {
+		limit = maxBulkUpdateLimit
+	}
+
+	// Assignment goes through the assign endpoint, which resolves names and needs no edit permission
+	fieldsInput := input.IssueFieldsInput
+	assignee := fieldsInput.Assignee
+	fieldsInput.Assignee = ""
+
+	payload, err := buildIssuePayload(nil, fieldsInput)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCustomFields(ctx, client, payload, fieldsInput.CustomFields); err != nil {
+		return nil, err
+	}
+
+	if len(payload) == 0 && assignee == "" && input.TargetStatus == "" {
+		return nil, fmt.Errorf("the change set is empty: set fields to edit, assignee or target_status")
+	}
+
+	result, err := searchAllIssuesJQL(ctx, client, jql, []string{"summary", "status", "assignee", "project", "issuetype"}, nil, "", limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Issues) > limit {
+		return nil, fmt.Errorf("the selection has more than %d issues, narrow it or raise max_issues", limit)
+	}
+	if len(result.Issues) == 0 {
+		return nil, fmt.Errorf("no issues match the selection")
+	}
+
+	output := BulkUpdateOutput{DryRun: !input.Apply, Count: len(result.Issues)}
+	for _, issue := range result.Issues {
+		output.Issues = append(output.Issues, newBulkUpdateIssueOutput(issue))
+	}
+	if input.IssueKeys != "" {
+		if missing := missingIssueKeys(splitList(input.IssueKeys), result.Issues); len(missing) > 0 {
+			return nil, fmt.Errorf("issues not found or not visible: %s", strings.Join(missing, ", "))
+		}
+	}
+
+	if err := checkBulkPolicy(result.Issues, len(payload) > 0, assignee != "", input.TargetStatus != ""); err != nil {
+		return nil, err
+	}
+
+	var assigneeAccountID *string
+	if assignee != "" && !isUnassignedValue(assignee) {
+		user, err := resolveBulkAssignee(ctx, client, result.Issues, assignee)
+		if err != nil {
+			return nil, err
+		}
+		id := userID(user)
+		assigneeAccountID = &id
+		assignee = user.DisplayName
+	}
+
+	output.Changes = describeBulkChanges(payload, assignee, input.TargetStatus)
+
+	if !input.Apply {
+		return formatResult(input.OutputFormat, output, formatBulkUpdate(output))
+	}
+
+	if input.ExpectedCount != len(result.Issues) {
+		return nil, fmt.Errorf("the selection has %d issues but expected_count is %d; preview the update again and pass its count", len(result.Issues), input.ExpectedCount)
+	}
+
+	confirmation, err := requireConfirmation(ctx, request, input.ConfirmationToken)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation != nil {
+		output.DryRun = true
+		output.ConfirmationOutput = *confirmation
+		return formatResult(input.OutputFormat, output, formatBulkUpdate(output)+confirmationText(confirmation))
+	}
+
+	concurrency := input.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkUpdateConcurrency
+	}
+	if concurrency > maxBulkUpdateConcurrency {
+		concurrency = maxBulkUpdateConcurrency
+	}
+
+	change := bulkChange{
+		payload:           payload,
+		assign:            assignee != "",
+		assigneeAccountID: assigneeAccountID,
+		targetStatus:      input.TargetStatus,
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for i := range output.Issues {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(issue *BulkUpdateIssueOutput) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			applyBulkChange(ctx, client, issue, change)
+		}(&output.Issues[i])
+	}
+	wg.Wait()
+
+	for _, issue := range output.Issues {
+		if issue.Success {
+			output.Succeeded++
+		} else {
+			output.Failed++
+		}
+	}
+
+	return formatResult(input.OutputFormat, output, formatBulkUpdate(output))
+}
+
+// resolveBulkAssignee resolves the assignee once per project of the selection, since the Assignable
+// User permission is granted per project. Every project must resolve to the same user.
+func resolveBulkAssignee(ctx context.Context, client *jira.Client, issues []*models.IssueScheme, assignee string) (*models.UserScheme, error) {
+	var user *models.UserScheme
+	var userProject string
+	resolved := map[string]bool{}
+
+	for _, issue := range issues {
+		projectKey := issueProjectKey(issue.Key)
+		if issue.Fields != nil && issue.Fields.Project != nil {
+			projectKey = issue.Fields.Project.Key
+		}
+		if resolved[projectKey] {
+			continue
+		}
+		resolved[projectKey] = true
+
+		projectUser, candidates, err := resolveAssignableUser(ctx, client, issue.Key, assignee)
+		if err != nil {
+			return nil, fmt.Errorf("in project %s: %w", projectKey, err)
+		}
+		if projectUser == nil {
+			var names []string
+			for _, candidate := range candidates {
+				names = append(names, formatUser(candidate))
+			}
+			return nil, fmt.Errorf("%q matches several assignable users in project %s, use one of their account IDs: %s", assignee, projectKey, strings.Join(names, "; "))
+		}
+
+		if user != nil && userID(projectUser) != userID(user) {
+			return nil, fmt.Errorf("%q is %s in project %s but %s in project %s, use an account ID", assignee, formatUser(user), userProject, formatUser(projectUser), projectKey)
+		}
+		user, userProject = projectUser, projectKey
+	}
+	return user, nil
+}
+
+// bulkChange is the change set of jira_bulk_update, resolved once for every issue
+type bulkChange struct {
+	payload           map[string]interface{}
+	assign            bool
+	assigneeAccountID *string
+	targetStatus      string
+}
+
+// applyBulkChange edits, assigns and transitions one issue, in that order so a transition
+// screen sees the edited fields. The first failing step ends the issue's update.
+func applyBulkChange(ctx context.Context, client *jira.Client, issue *BulkUpdateIssueOutput, change bulkChange) {
+	before := auditSnapshot(ctx, client, issue.Key, change.fieldIDs())
+	defer auditChanges(ctx, client, issue.Key, before)
+
+	if len(change.payload) > 0 {
+		if response, err := editIssueWithPayload(ctx, client, issue.Key, change.payload); err != nil {
+			issue.Error = bulkStepError("edit", response, err)
+			return
+		}
+	}
+
+	if change.assign {
+		if response, err := assignIssue(ctx, client, issue.Key, change.assigneeAccountID); err != nil {
+			issue.Error = bulkStepError("assign", response, err)
+			return
+		}
+	}
+
+	if change.targetStatus != "" {
+		path, err := transitionToStatus(ctx, client, issue.Key, change.targetStatus, nil, "")
+		issue.Path = path
+		if err != nil {
+			issue.Error = fmt.Sprintf("transition: %v", err)
+			return
+		}
+	}
+
+	issue.Success = true
+}
+
+// fieldIDs lists the fields the change set may modify
+func (c bulkChange) fieldIDs() []string {
+	ids := payloadFieldIDs(c.payload)
+	if c.assign {
+		ids = append(ids, "assignee")
+	}
+	if c.targetStatus != "" {
+		ids = append(ids, "status")
+	}
+	return ids
+}
+
+func bulkStepError(step string, response *models.ResponseScheme, err error) string {
+	if response != nil {
+		return fmt.Sprintf("%s: %s", step, response.Bytes.String())
+	}
+	return fmt.Sprintf("%s: %v", step, err)
+}
+
+// checkBulkPolicy refuses the whole update when the write policy forbids a step on any
+// selected issue, so a policy never leaves the selection half updated
+func checkBulkPolicy(issues []*models.IssueScheme, edit, assign, transition bool) error {
+	var actions []string
+	if edit {
+		actions = append(actions, actionEdit)
+	}
+	if assign {
+		actions = append(actions, actionAssign)
+	}
+	if transition {
+		actions = append(actions, actionTransition)
+	}
+
+	var denied []string
+	var firstErr error
+	for _, issue := range issues {
+		var projectKey, issueType string
+		if issue.Fields != nil && issue.Fields.Project != nil {
+			projectKey = issue.Fields.Project.Key
+		}
+		if issue.Fields != nil && issue.Fields.IssueType != nil {
+			issueType = issue.Fields.IssueType.Name
+		}
+
+		for _, action := range actions {
+			if err := checkWritePolicy(action, projectKey, issueType); err != nil {
+				denied = append(denied, issue.Key)
+				if firstErr == nil {
+					firstErr = err
+				}
+				break
+			}
+		}
+	}
+
+	if len(denied) > 0 {
+		return fmt.Errorf("%d of the %d selected issues may not be changed (%s), narrow the selection: %w", len(denied), len(issues), strings.Join(denied, ", "), firstErr)
+	}
+	return nil
+}
+
+// bulkSelectionJQL returns the JQL of the selection, turning a key list into a key query
+func bulkSelectionJQL(jql, issueKeys string) (string, error) {
+	keys := splitList(issueKeys)
+	switch {
+	case jql != "" && len(keys) > 0:
+		return "", fmt.Errorf("set either jql or issue_keys, not both")
+	case jql != "":
+		return jql, nil
+	case len(keys) > 0:
+		for _, key := range keys {
+			if !issueKeyPattern.MatchString(key) {
+				return "", fmt.Errorf("invalid issue key %q", key)
+			}
+		}
+		return fmt.Sprintf("key in (%s) ORDER BY key", strings.Join(keys, ", ")), nil
+	}
+	return "", fmt.Errorf("either jql or issue_keys is required")
+}
+
+func missingIssueKeys(keys []string, issues []*models.IssueScheme) []string {
+	found := map[string]bool{}
+	for _, issue := range issues {
+		found[issue.Key] = true
+	}
+
+	var missing []string
+	for _, key := range keys {
+		if !found[key] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+func newBulkUpdateIssueOutput(issue *models.IssueScheme) BulkUpdateIssueOutput {
+	output := BulkUpdateIssueOutput{Key: issue.Key}
+	if issue.Fields == nil {
+		return output
+	}
+
+	output.Summary = issue.Fields.Summary
+	if issue.Fields.Status != nil {
+		output.Status = issue.Fields.Status.Name
+	}
+	if issue.Fields.Assignee != nil {
+		output.Assignee = issue.Fields.Assignee.DisplayName
+	}
+	return output
+}
+
+// describeBulkChanges lists the change set in words for the preview and the report
+func describeBulkChanges(payload map[string]interface{}, assignee, targetStatus string) []string {
+	var changes []string
+
+	fields, _ := payload["fields"].(map[string]interface{})
+	for _, id := range payloadFieldIDs(map[string]interface{}{"fields": fields}) {
+		if fields[id] == nil {
+			changes = append(changes, fmt.Sprintf("clear %s", id))
+		} else {
+			changes = append(changes, fmt.Sprintf("set %s", id))
+		}
+	}
+
+	update, _ := payload["update"].(map[string]interface{})
+	for _, id := range payloadFieldIDs(map[string]interface{}{"update": update}) {
+		operations, _ := update[id].([]map[string]interface{})
+		for _, operation := range operations {
+			for verb, value := range operation {
+				if object, ok := value.(map[string]interface{}); ok {
+					value = object["name"]
+				}
+				changes = append(changes, fmt.Sprintf("%s %s %v", verb, id, value))
+			}
+		}
+	}
+
+	switch {
+	case assignee == "":
+	case isUnassignedValue(assignee):
+		changes = append(changes, "unassign")
+	default:
+		changes = append(changes, fmt.Sprintf("assign to %s", assignee))
+	}
+
+	if targetStatus != "" {
+		changes = append(changes, fmt.Sprintf("transition to %s", targetStatus))
+	}
+
+	return changes
+}
+
+func formatBulkUpdate(output BulkUpdateOutput) string {
+	var result strings.Builder
+
+	if output.DryRun {
+		result.WriteString(fmt.Sprintf("Preview: %d issues would be updated, nothing was changed.\n", output.Count))
+	} else {
+		result.WriteString(fmt.Sprintf("Updated %d of %d issues, %d failed.\n", output.Succeeded, output.Count, output.Failed))
+	}
+
+	result.WriteString("\nChanges:\n")
+	for _, change := range output.Changes {
+		result.WriteString(fmt.Sprintf("- %s\n", change))
+	}
+
+	result.WriteString("\nIssues:\n")
+	for _, issue := range output.Issues {
+		result.WriteString(fmt.Sprintf("- %s: %s [%s]", issue.Key, issue.Summary, issue.Status))
+		switch {
+		case output.DryRun:
+		case issue.Success && len(issue.Path) > 1:
+			result.WriteString(fmt.Sprintf(" OK (%s)", strings.Join(issue.Path, " -> ")))
+		case issue.Success:
+			result.WriteString(" OK")
+		default:
+			result.WriteString(fmt.Sprintf(" FAILED: %s", issue.Error))
+		}
+		result.WriteString("\n")
+	}
+
+	if output.DryRun && output.ConfirmationToken == "" {
+		result.WriteString(fmt.Sprintf("\nTo apply, call again with apply=true and expected_count=%d\n", output.Count))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestBulkSelectionJQL(t *testing.T) {
+	jql, err := bulkSelectionJQL("", "KP-1, KP-2")
+	if err != nil || jql != "key in (KP-1, KP-2) ORDER BY key" {
+		t.Errorf("bulkSelectionJQL(keys) = %q, %v", jql, err)
+	}
+
+	if jql, err := bulkSelectionJQL("sprint = 42", ""); err != nil || jql != "sprint = 42" {
+		t.Errorf("bulkSelectionJQL(jql) = %q, %v", jql, err)
+	}
+
+	for _, args := range [][2]string{{"", ""}, {"sprint = 42", "KP-1"}, {"", "KP-1, x) OR (project = OTHER"}} {
+		if _, err := bulkSelectionJQL(args[0], args[1]); err == nil {
+			t.Errorf("bulkSelectionJQL(%q, %q): expected an error", args[0], args[1])
+		}
+	}
+}
+
+func TestDescribeBulkChanges(t *testing.T) {
+	payload, err := buildIssuePayload(nil, IssueFieldsInput{
+		Priority:       "High",
+		Assignee:       "unassigned",
+		AddLabels:      "cleanup",
+		AddFixVersions: "2.0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Join(describeBulkChanges(payload, "Jane", "Done"), "; ")
+	want := "clear assignee; set priority; add fixVersions 2.0; add labels cleanup; assign to Jane; transition to Done"
+	if got != want {
+		t.Errorf("describeBulkChanges() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildIssuePayloadFixVersionConflict(t *testing.T) {
+	if _, err := buildIssuePayload(nil, IssueFieldsInput{FixVersions: "1.0", RemoveFixVersions: "2.0"}); err == nil {
+		t.Error("expected fix_versions combined with remove_fix_versions to be rejected")
+	}
+}
+
+func TestResolveBulkAssignee(t *testing.T) {
+	const alice = `{"accountId": "5b10a2844c20165700ede21g", "displayName": "Alice", "emailAddress": "alice@example.com", "active": true, "accountType": "atlassian"}`
+	const otherAlice = `{"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "Alice", "active": true, "accountType": "atlassian"}`
+
+	issue := func(key, project string) *models.IssueScheme {
+		return &models.IssueScheme{Key: key, Fields: &models.IssueFieldsScheme{Project: &models.ProjectScheme{Key: project}}}
+	}
+	issues := []*models.IssueScheme{issue("PLAT-1", "PLAT"), issue("PLAT-2", "PLAT"), issue("OPS-1", "OPS")}
+
+	tests := []struct {
+		name    string
+		ops     string
+		wantErr string
+	}{
+		{"assignable everywhere", "[" + alice + "]", ""},
+		{"not assignable in one project", "[]", `in project OPS: no user matching "Alice" can be assigned to OPS-1`},
+		{"another user in one project", "[" + otherAlice + "]", `"Alice" is Alice <alice@example.com> (5b10a2844c20165700ede21g) in project PLAT but Alice (5b10ac8d82e05b22cc7d4ef5) in project OPS`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searched := map[string]int{}
+			jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/rest/api/3/user/assignable/search":
+					issueKey := r.URL.Query().Get("issueKey")
+					searched[issueKey]++
+					if strings.HasPrefix(issueKey, "OPS-") {
+						w.Write([]byte(tt.ops))
+						return
+					}
+					w.Write([]byte("[" + alice + "]"))
+				case "/rest/api/3/user/search":
+					w.Write([]byte("[" + alice + "]"))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer jiraServer.Close()
+			ctx := stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+
+			user, err := resolveBulkAssignee(ctx, services.JiraClientFor(ctx), issues, "Alice")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil || user == nil || user.AccountID != "5b10a2844c20165700ede21g" {
+				t.Fatalf("user = %+v, %v", user, err)
+			}
+
+			// Assignability is checked once per project
+			if searched["PLAT-1"] != 1 || searched["PLAT-2"] != 0 || searched["OPS-1"] != 1 {
+				t.Errorf("assignable searches = %v", searched)
+			}
+		})
+	}
+}
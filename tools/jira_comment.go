@@ -15,35 +15,61 @@ import (
 type AddCommentInput struct {
 	IssueKey string `json:"issue_key" validate:"required"`
 	Comment  string `json:"comment" validate:"required"`
+	OutputFormatInput
 }
 
 type GetCommentsInput struct {
 	IssueKey string `json:"issue_key" validate:"required"`
+	OutputFormatInput
+}
+
+// CommentOutput is an issue comment, the body is rendered as markdown
+type CommentOutput struct {
+	ID      string `json:"id"`
+	Author  string `json:"author"`
+	Created string `json:"created"`
+	Updated string `json:"updated,omitempty"`
+	Body    string `json:"body,omitempty"`
+}
+
+// GetCommentsOutput is the result of jira_get_comments
+type GetCommentsOutput struct {
+	IssueKey string          `json:"issue_key"`
+	Comments []CommentOutput `json:"comments"`
 }
 
 func RegisterJiraCommentTools(s *server.MCPServer) {
 	jiraAddCommentTool := mcp.NewTool("jira_add_comment",
+		additiveTool(),
 		mcp.WithDescription("Add a comment to a Jira issue"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
 		mcp.WithString("comment", mcp.Required(), mcp.Description("The comment text to add to the issue")),
+		withOutputFormat[CommentOutput](),
 	)
-	s.AddTool(jiraAddCommentTool, mcp.NewTypedToolHandler(jiraAddCommentHandler))
+	addTool(s, jiraAddCommentTool, mcp.NewTypedToolHandler(jiraAddCommentHandler))
 
 	jiraGetCommentsTool := mcp.NewTool("jira_get_comments",
+		readOnlyTool(),
 		mcp.WithDescription("Retrieve all comments from a Jira issue"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		withOutputFormat[GetCommentsOutput](),
 	)
-	s.AddTool(jiraGetCommentsTool, mcp.NewTypedToolHandler(jiraGetCommentsHandler))
+	addTool(s, jiraGetCommentsTool, mcp.NewTypedToolHandler(jiraGetCommentsHandler))
 }
 
 func jiraAddCommentHandler(ctx context.Context, request mcp.CallToolRequest, input AddCommentInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
+
+	if err := checkIssuePolicy(ctx, client, actionComment, input.IssueKey); err != nil {
+		return nil, err
+	}
 
 	commentPayload := &models.CommentPayloadScheme{
 		Body: util.MarkdownToADF(input.Comment),
 	}
 
-	comment, response, err := client.Issue.Comment.Add(ctx, input.IssueKey, commentPayload, nil)
+	comment, response, err := addComment(ctx, client, input.IssueKey, commentPayload)
+	auditRequest(ctx, response, input.IssueKey)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to add comment: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -51,20 +77,23 @@ func jiraAddCommentHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		return nil, fmt.Errorf("failed to add comment: %v", err)
 	}
 
+	auditCreated(ctx, AuditResource{Type: auditResourceComment, Issue: input.IssueKey, ID: comment.ID})
+
 	result := fmt.Sprintf("Comment added successfully!\nID: %s\nAuthor: %s\nCreated: %s",
 		comment.ID,
 		comment.Author.DisplayName,
 		comment.Created)
 
-	return mcp.NewToolResultText(result), nil
+	output := CommentOutput{ID: comment.ID, Author: comment.Author.DisplayName, Created: comment.Created, Updated: comment.Updated}
+	return formatResult(input.OutputFormat, output, result)
 }
 
 func jiraGetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, input GetCommentsInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	// Retrieve up to 50 comments starting from the first one.
 	// Passing 0 for maxResults results in Jira returning only the first comment.
-	comments, response, err := client.Issue.Comment.Gets(ctx, input.IssueKey, "", nil, 0, 50)
+	comments, response, err := getComments(ctx, client, input.IssueKey, 0, 50)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to get comments: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -72,8 +101,10 @@ func jiraGetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, in
 		return nil, fmt.Errorf("failed to get comments: %v", err)
 	}
 
+	output := GetCommentsOutput{IssueKey: input.IssueKey, Comments: []CommentOutput{}}
+
 	if len(comments.Comments) == 0 {
-		return mcp.NewToolResultText("No comments found for this issue."), nil
+		return formatResult(input.OutputFormat, output, "No comments found for this issue.")
 	}
 
 	var result string
@@ -86,6 +117,14 @@ func jiraGetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, in
 		// Render ADF body to readable text
 		bodyText := util.RenderADF(comment.Body)
 
+		output.Comments = append(output.Comments, CommentOutput{
+			ID:      comment.ID,
+			Author:  authorName,
+			Created: comment.Created,
+			Updated: comment.Updated,
+			Body:    bodyText,
+		})
+
 		result += fmt.Sprintf("ID: %s\nAuthor: %s\nCreated: %s\nUpdated: %s\nBody:\n%s\n\n",
 			comment.ID,
 			authorName,
@@ -94,5 +133,5 @@ func jiraGetCommentsHandler(ctx context.Context, request mcp.CallToolRequest, in
 			bodyText)
 	}
 
-	return mcp.NewToolResultText(result), nil
+	return formatResult(input.OutputFormat, output, result)
 }
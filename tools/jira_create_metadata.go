@@ -0,0 +1,468 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// Input types for typed tools
+type GetCreateMetadataInput struct {
+	ProjectKey string `json:"project_key" validate:"required"`
+	IssueType  string `json:"issue_type,omitempty"`
+	OutputFormatInput
+}
+
+// AllowedValueOutput is a value a field accepts, such as an option, component or version
+type AllowedValueOutput struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name"`
+	Children []string `json:"children,omitempty" jsonschema_description:"Child options of a cascading select, set as 'Parent > Child'"`
+}
+
+// CreateFieldOutput is a field on the create screen of an issue type
+type CreateFieldOutput struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Required      bool                 `json:"required"`
+	Type          string               `json:"type,omitempty" jsonschema_description:"Schema type, e.g. number or array<option>"`
+	CustomType    string               `json:"custom_type,omitempty"`
+	HasDefault    bool                 `json:"has_default" jsonschema_description:"True when Jira fills the field if it is not sent"`
+	DefaultValue  string               `json:"default_value,omitempty"`
+	AllowedValues []AllowedValueOutput `json:"allowed_values,omitempty"`
+}
+
+// CreateIssueTypeOutput is an issue type that can be created in a project
+type CreateIssueTypeOutput struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchy_level" jsonschema_description:"-1 for subtasks, 0 for standard issues, 1 for epics and higher for levels above"`
+}
+
+// CreateMetadataOutput is the result of jira_get_create_metadata
+type CreateMetadataOutput struct {
+	ProjectKey     string                  `json:"project_key"`
+	IssueType      *CreateIssueTypeOutput  `json:"issue_type,omitempty" jsonschema_description:"The requested issue type, absent when no issue_type was given"`
+	RequiredFields []CreateFieldOutput     `json:"required_fields,omitempty" jsonschema_description:"Fields that must be sent; fields with has_default are filled by Jira when omitted"`
+	OptionalFields []CreateFieldOutput     `json:"optional_fields,omitempty"`
+	IssueTypes     []CreateIssueTypeOutput `json:"issue_types,omitempty" jsonschema_description:"Issue types that can be created in the project, returned when no issue_type was given"`
+}
+
+func RegisterJiraCreateMetadataTool(s *server.MCPServer) {
+	jiraGetCreateMetadataTool := mcp.NewTool("jira_get_create_metadata",
+		readOnlyTool(),
+		mcp.WithDescription("Get the fields needed to create an issue of a type in a project: required and optional fields with their IDs, types, allowed values and defaults. Without issue_type, lists the issue types that can be created in the project"),
+		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier (e.g., KP, PROJ)")),
+		mcp.WithString("issue_type", mcp.Description("Issue type name or ID (e.g., Bug, Story, 10001)")),
+		withOutputFormat[CreateMetadataOutput](),
+	)
+	addTool(s, jiraGetCreateMetadataTool, mcp.NewTypedToolHandler(jiraGetCreateMetadataHandler))
+}
+
+func jiraGetCreateMetadataHandler(ctx context.Context, request mcp.CallToolRequest, input GetCreateMetadataInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	issueTypes, err := getCreateMetaIssueTypes(ctx, client, input.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	output := CreateMetadataOutput{ProjectKey: input.ProjectKey}
+
+	if input.IssueType == "" {
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("Issue types that can be created in %s:\n\n", input.ProjectKey))
+		for _, issueType := range issueTypes {
+			output.IssueTypes = append(output.IssueTypes, newCreateIssueTypeOutput(issueType))
+			result.WriteString(fmt.Sprintf("- %s (ID: %s)", issueType.Name, issueType.ID))
+			if issueType.Subtask {
+				result.WriteString(" [subtask]")
+			}
+			result.WriteString("\n")
+		}
+		result.WriteString("\nPass issue_type to get the fields of one of them.\n")
+		return formatResult(input.OutputFormat, output, result.String())
+	}
+
+	issueType, err := findCreateMetaIssueType(issueTypes, input.ProjectKey, input.IssueType)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := getCreateMetaFields(ctx, client, input.ProjectKey, issueType.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	issueTypeOutput := newCreateIssueTypeOutput(issueType)
+	output.IssueType = &issueTypeOutput
+
+	for _, field := range fields {
+		fieldOutput := newCreateFieldOutput(field)
+		if field.Required {
+			output.RequiredFields = append(output.RequiredFields, fieldOutput)
+		} else {
+			output.OptionalFields = append(output.OptionalFields, fieldOutput)
+		}
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Create metadata for %s in %s (issue type ID: %s)\n", issueType.Name, input.ProjectKey, issueType.ID))
+
+	result.WriteString("\nRequired Fields:\n")
+	if len(output.RequiredFields) == 0 {
+		result.WriteString("None\n")
+	}
+	for _, field := range output.RequiredFields {
+		result.WriteString(formatCreateField(field))
+	}
+
+	if len(output.OptionalFields) > 0 {
+		result.WriteString("\nOptional Fields:\n")
+		for _, field := range output.OptionalFields {
+			result.WriteString(formatCreateField(field))
+		}
+	}
+
+	return formatResult(input.OutputFormat, output, result.String())
+}
+
+// createMetaIssueType is an issue type from the createmeta issue types endpoint
+type createMetaIssueType struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
+}
+
+// createMetaField is a field from the createmeta fields endpoint
+type createMetaField struct {
+	FieldID         string                         `json:"fieldId"`
+	Key             string                         `json:"key"`
+	Name            string                         `json:"name"`
+	Required        bool                           `json:"required"`
+	HasDefaultValue bool                           `json:"hasDefaultValue"`
+	DefaultValue    interface{}                    `json:"defaultValue"`
+	AllowedValues   []interface{}                  `json:"allowedValues"`
+	Schema          *models.IssueFieldSchemaScheme `json:"schema"`
+}
+
+// createMetaPage is a page of the createmeta endpoints. Jira documents the entries under
+// "issueTypes" and "fields" but returns them under "values", so both are read.
+type createMetaPage struct {
+	Total      int             `json:"total"`
+	IssueTypes json.RawMessage `json:"issueTypes"`
+	Fields     json.RawMessage `json:"fields"`
+	Values     json.RawMessage `json:"values"`
+}
+
+const createMetaPageSize = 200
+
+// getCreateMetaIssueTypes lists the issue types the user can create in a project.
+// client.Issue.Metadata only wraps the createmeta endpoint Jira Cloud removed.
+func getCreateMetaIssueTypes(ctx context.Context, client *jira.Client, projectKey string) ([]createMetaIssueType, error) {
+	var issueTypes []createMetaIssueType
+	endpoint := restAPI(ctx, fmt.Sprintf("issue/createmeta/%s/issuetypes", url.PathEscape(projectKey)))
+	err := getCreateMetaPages(ctx, client, endpoint, func(page createMetaPage) (int, error) {
+		var values []createMetaIssueType
+		if err := decodeCreateMetaValues(page, page.IssueTypes, &values); err != nil {
+			return 0, err
+		}
+		issueTypes = append(issueTypes, values...)
+		return len(values), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue types of project %s: %w", projectKey, err)
+	}
+
+	return issueTypes, nil
+}
+
+// getCreateMetaFields lists the fields of the create screen of an issue type in a project,
+// sorted with required fields first and then by name
+func getCreateMetaFields(ctx context.Context, client *jira.Client, projectKey, issueTypeID string) ([]createMetaField, error) {
+	var fields []createMetaField
+	endpoint := restAPI(ctx, fmt.Sprintf("issue/createmeta/%s/issuetypes/%s", url.PathEscape(projectKey), url.PathEscape(issueTypeID)))
+	err := getCreateMetaPages(ctx, client, endpoint, func(page createMetaPage) (int, error) {
+		var values []createMetaField
+		if err := decodeCreateMetaValues(page, page.Fields, &values); err != nil {
+			return 0, err
+		}
+		fields = append(fields, values...)
+		return len(values), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get create fields of project %s: %w", projectKey, err)
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].Required != fields[j].Required {
+			return fields[i].Required
+		}
+		return strings.ToLower(fields[i].Name) < strings.ToLower(fields[j].Name)
+	})
+
+	return fields, nil
+}
+
+// getCreateMetaPages calls read for each page of a createmeta endpoint until every entry was read
+func getCreateMetaPages(ctx context.Context, client *jira.Client, endpoint string, read func(createMetaPage) (int, error)) error {
+	startAt := 0
+	for {
+		params := url.Values{}
+		params.Set("startAt", fmt.Sprint(startAt))
+		params.Set("maxResults", fmt.Sprint(createMetaPageSize))
+
+		req, err := client.NewRequest(ctx, http.MethodGet, endpoint+"?"+params.Encode(), "", nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+
+		var page createMetaPage
+		response, err := client.Call(req, &page)
+		if err != nil {
+			if response != nil {
+				return fmt.Errorf("%s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return err
+		}
+
+		count, err := read(page)
+		if err != nil {
+			return err
+		}
+
+		startAt += count
+		if count == 0 || startAt >= page.Total {
+			return nil
+		}
+	}
+}
+
+func decodeCreateMetaValues(page createMetaPage, named json.RawMessage, values interface{}) error {
+	raw := page.Values
+	if len(named) > 0 && string(named) != "null" {
+		raw = named
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, values); err != nil {
+		return fmt.Errorf("failed to decode create metadata: %w", err)
+	}
+	return nil
+}
+
+// findCreateMetaIssueType finds an issue type by ID or case-insensitive name
+func findCreateMetaIssueType(issueTypes []createMetaIssueType, projectKey, nameOrID string) (createMetaIssueType, error) {
+	var names []string
+	for _, issueType := range issueTypes {
+		if issueType.ID == nameOrID || strings.EqualFold(issueType.Name, nameOrID) {
+			return issueType, nil
+		}
+		names = append(names, issueType.Name)
+	}
+	return createMetaIssueType{}, fmt.Errorf("issue type %q cannot be created in project %s, available issue types: %s", nameOrID, projectKey, strings.Join(names, ", "))
+}
+
+// missingRequiredFields returns the required fields without a default that a payload built
+// by buildIssuePayload does not set, or sets to an empty string or list
+func missingRequiredFields(fields []createMetaField, payload map[string]interface{}) []createMetaField {
+	values, _ := payload["fields"].(map[string]interface{})
+	update, _ := payload["update"].(map[string]interface{})
+
+	var missing []createMetaField
+	for _, field := range fields {
+		if !field.Required || field.HasDefaultValue {
+			continue
+		}
+		id := createMetaFieldID(field)
+		if !isEmptyFieldValue(values[id]) || !isEmptyFieldValue(update[id]) {
+			continue
+		}
+		missing = append(missing, field)
+	}
+	return missing
+}
+
+// isEmptyFieldValue reports whether a payload value leaves a field blank: nil, a blank string,
+// or an empty list or object
+func isEmptyFieldValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if text, ok := value.(string); ok {
+		return strings.TrimSpace(text) == ""
+	}
+	switch reflected := reflect.ValueOf(value); reflected.Kind() {
+	case reflect.Slice, reflect.Map:
+		return reflected.Len() == 0
+	}
+	return false
+}
+
+// checkRequiredFields is the pre-flight check of jira_create_issue: it reports every required
+// field the payload is missing in one error, rather than one Jira error per attempt.
+// When the metadata cannot be read the check is skipped and Jira validates the request.
+func checkRequiredFields(ctx context.Context, client *jira.Client, projectKey, issueType string, payload map[string]interface{}) error {
+	issueTypes, err := getCreateMetaIssueTypes(ctx, client, projectKey)
+	if err != nil {
+		return nil
+	}
+
+	metaIssueType, err := findCreateMetaIssueType(issueTypes, projectKey, issueType)
+	if err != nil {
+		return err
+	}
+
+	fields, err := getCreateMetaFields(ctx, client, projectKey, metaIssueType.ID)
+	if err != nil {
+		return nil
+	}
+
+	missing := missingRequiredFields(fields, payload)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("cannot create %s in %s, %d required field(s) are missing:\n", metaIssueType.Name, projectKey, len(missing)))
+	for _, field := range missing {
+		fieldOutput := newCreateFieldOutput(field)
+		message.WriteString(fmt.Sprintf("- %s (%s", fieldOutput.Name, fieldOutput.ID))
+		if fieldOutput.Type != "" {
+			message.WriteString(", " + fieldOutput.Type)
+		}
+		message.WriteString(")")
+		if names := allowedValueNames(fieldOutput.AllowedValues, 10); names != "" {
+			message.WriteString(": one of " + names)
+		}
+		message.WriteString("\n")
+	}
+	message.WriteString("Set standard fields with their arguments and the others with custom_fields; jira_get_create_metadata lists every field")
+
+	return fmt.Errorf("%s", message.String())
+}
+
+func createMetaFieldID(field createMetaField) string {
+	if field.FieldID != "" {
+		return field.FieldID
+	}
+	return field.Key
+}
+
+func newCreateIssueTypeOutput(issueType createMetaIssueType) CreateIssueTypeOutput {
+	return CreateIssueTypeOutput{
+		ID:             issueType.ID,
+		Name:           issueType.Name,
+		Description:    issueType.Description,
+		Subtask:        issueType.Subtask,
+		HierarchyLevel: issueType.HierarchyLevel,
+	}
+}
+
+func newCreateFieldOutput(field createMetaField) CreateFieldOutput {
+	output := CreateFieldOutput{
+		ID:         createMetaFieldID(field),
+		Name:       field.Name,
+		Required:   field.Required,
+		HasDefault: field.HasDefaultValue,
+	}
+
+	if field.Schema != nil {
+		output.Type = fieldSchemaType(field.Schema)
+		output.CustomType = field.Schema.Custom
+	}
+
+	if field.DefaultValue != nil {
+		output.DefaultValue = metaValueName(field.DefaultValue)
+	}
+
+	for _, value := range field.AllowedValues {
+		allowed := AllowedValueOutput{Name: metaValueName(value)}
+		if object, ok := value.(map[string]interface{}); ok {
+			if id, ok := object["id"]; ok && id != nil {
+				allowed.ID = fmt.Sprint(id)
+			}
+			children, _ := object["children"].([]interface{})
+			for _, child := range children {
+				allowed.Children = append(allowed.Children, metaValueName(child))
+			}
+		}
+		output.AllowedValues = append(output.AllowedValues, allowed)
+	}
+
+	return output
+}
+
+// metaValueName renders an allowed or default value by the property users know it by:
+// options have a value, most other objects a name
+func metaValueName(value interface{}) string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range []string{"name", "value", "displayName", "key", "id"} {
+			if name, ok := v[key]; ok && name != nil {
+				return fmt.Sprint(name)
+			}
+		}
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	case []interface{}:
+		var names []string
+		for _, item := range v {
+			names = append(names, metaValueName(item))
+		}
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// allowedValueNames joins up to limit allowed value names, noting how many were left out
+func allowedValueNames(values []AllowedValueOutput, limit int) string {
+	var names []string
+	for i, value := range values {
+		if i == limit {
+			names = append(names, fmt.Sprintf("... and %d more", len(values)-limit))
+			break
+		}
+		names = append(names, value.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatCreateField(field CreateFieldOutput) string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("- %s (ID: %s)", field.Name, field.ID))
+	if field.Type != "" {
+		result.WriteString(fmt.Sprintf(" | Type: %s", field.Type))
+	}
+	if field.HasDefault {
+		if field.DefaultValue != "" {
+			result.WriteString(fmt.Sprintf(" | Default: %s", field.DefaultValue))
+		} else {
+			result.WriteString(" | Has default")
+		}
+	}
+	result.WriteString("\n")
+
+	if names := allowedValueNames(field.AllowedValues, 25); names != "" {
+		result.WriteString(fmt.Sprintf("  Allowed: %s\n", names))
+	}
+
+	return result.String()
+}
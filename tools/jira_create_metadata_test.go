@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMissingRequiredFields(t *testing.T) {
+	var fields []createMetaField
+	err := json.Unmarshal([]byte(`[
+		{"fieldId": "summary", "name": "Summary", "required": true},
+		{"fieldId": "reporter", "name": "Reporter", "required": true, "hasDefaultValue": true},
+		{"fieldId": "components", "name": "Components", "required": true, "allowedValues": [{"id": "1", "name": "Backend"}]},
+		{"fieldId": "customfield_10050", "name": "Severity", "required": true, "allowedValues": [{"id": "2", "value": "S1"}]},
+		{"fieldId": "labels", "name": "Labels", "required": true},
+		{"fieldId": "assignee", "name": "Assignee", "required": true},
+		{"fieldId": "duedate", "name": "Due date", "required": false}
+	]`), &fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := map[string]interface{}{
+		"fields": map[string]interface{}{"summary": "Login page", "assignee": nil},
+		"update": map[string]interface{}{"labels": []map[string]interface{}{{"add": "ui"}}},
+	}
+
+	var missing []string
+	for _, field := range missingRequiredFields(fields, payload) {
+		missing = append(missing, createMetaFieldID(field))
+	}
+
+	want := []string{"components", "customfield_10050", "assignee"}
+	if len(missing) != len(want) {
+		t.Fatalf("missing = %v, want %v", missing, want)
+	}
+	for i := range want {
+		if missing[i] != want[i] {
+			t.Errorf("missing = %v, want %v", missing, want)
+		}
+	}
+}
+
+func TestNewCreateFieldOutput(t *testing.T) {
+	var field createMetaField
+	err := json.Unmarshal([]byte(`{
+		"key": "customfield_10060", "name": "Region", "required": false, "hasDefaultValue": true,
+		"schema": {"type": "option-with-child", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:cascadingselect"},
+		"defaultValue": {"id": "10", "value": "EU"},
+		"allowedValues": [{"id": "10", "value": "EU", "children": [{"id": "11", "value": "Germany"}]}, {"value": "US"}]
+	}`), &field)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := newCreateFieldOutput(field)
+
+	if output.ID != "customfield_10060" || output.DefaultValue != "EU" || !output.HasDefault {
+		t.Errorf("output = %+v", output)
+	}
+	if len(output.AllowedValues) != 2 || output.AllowedValues[0].ID != "10" || output.AllowedValues[1].ID != "" {
+		t.Fatalf("AllowedValues = %+v", output.AllowedValues)
+	}
+	if len(output.AllowedValues[0].Children) != 1 || output.AllowedValues[0].Children[0] != "Germany" {
+		t.Errorf("Children = %v", output.AllowedValues[0].Children)
+	}
+}
+
+func TestDecodeCreateMetaValues(t *testing.T) {
+	for _, body := range []string{
+		`{"total": 1, "values": [{"id": "10001", "name": "Bug"}]}`,
+		`{"total": 1, "issueTypes": [{"id": "10001", "name": "Bug"}]}`,
+	} {
+		var page createMetaPage
+		if err := json.Unmarshal([]byte(body), &page); err != nil {
+			t.Fatal(err)
+		}
+
+		var issueTypes []createMetaIssueType
+		if err := decodeCreateMetaValues(page, page.IssueTypes, &issueTypes); err != nil {
+			t.Fatal(err)
+		}
+		if len(issueTypes) != 1 || issueTypes[0].Name != "Bug" {
+			t.Errorf("decoding %s = %+v", body, issueTypes)
+		}
+	}
+}
+
+func TestMissingRequiredFieldsTreatsEmptyValuesAsMissing(t *testing.T) {
+	fields := []createMetaField{
+		{FieldID: "summary", Required: true},
+		{FieldID: "components", Required: true},
+		{FieldID: "labels", Required: true},
+		{FieldID: "customfield_10050", Required: true},
+		{FieldID: "customfield_10051", Required: true},
+		{FieldID: "customfield_10052", Required: true},
+	}
+
+	tests := []struct {
+		name    string
+		payload map[string]interface{}
+		want    []string
+	}{
+		{
+			name: "empty strings and lists",
+			payload: map[string]interface{}{"fields": map[string]interface{}{
+				"summary":           "  ",
+				"components":        []map[string]interface{}{},
+				"labels":            []string{},
+				"customfield_10050": []interface{}{},
+				"customfield_10051": "",
+				"customfield_10052": map[string]interface{}{},
+			}},
+			want: []string{"summary", "components", "labels", "customfield_10050", "customfield_10051", "customfield_10052"},
+		},
+		{
+			name: "empty update operations",
+			payload: map[string]interface{}{
+				"fields": map[string]interface{}{"summary": "Login page", "components": []interface{}{map[string]interface{}{"name": "API"}}, "customfield_10050": 0, "customfield_10051": false, "customfield_10052": map[string]interface{}{"value": "S1"}},
+				"update": map[string]interface{}{"labels": []map[string]interface{}{}},
+			},
+			want: []string{"labels"},
+		},
+		{
+			name: "every field set",
+			payload: map[string]interface{}{
+				"fields": map[string]interface{}{"summary": "Login page", "components": []interface{}{map[string]interface{}{"name": "API"}}, "customfield_10050": []interface{}{"a"}, "customfield_10051": "S1", "customfield_10052": map[string]interface{}{"value": "S1"}},
+				"update": map[string]interface{}{"labels": []map[string]interface{}{{"add": "ui"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var missing []string
+			for _, field := range missingRequiredFields(fields, tt.payload) {
+				missing = append(missing, createMetaFieldID(field))
+			}
+			if !reflect.DeepEqual(missing, tt.want) {
+				t.Errorf("missing = %v, want %v", missing, tt.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,18 @@ type GetDevelopmentInfoInput struct {
 	IncludePullRequests bool   `json:"include_pull_requests,omitempty"`
 	IncludeCommits      bool   `json:"include_commits,omitempty"`
 	IncludeBuilds       bool   `json:"include_builds,omitempty"`
+	OutputFormatInput
+}
+
+// DevelopmentInfoOutput is the result of jira_get_development_information.
+// Branches, pull requests, repositories and builds keep the shape of the dev-status API.
+type DevelopmentInfoOutput struct {
+	IssueKey     string        `json:"issue_key"`
+	Message      string        `json:"message,omitempty" jsonschema_description:"Set when the issue has no development integrations or the dev-status API is unavailable"`
+	Branches     []Branch      `json:"branches"`
+	PullRequests []PullRequest `json:"pull_requests"`
+	Repositories []Repository  `json:"repositories" jsonschema_description:"Repositories with the commits linked to the issue"`
+	Builds       []Build       `json:"builds"`
 }
 
 // DevStatusResponse is the top-level response from /rest/dev-status/1.0/issue/detail endpoint.
@@ -199,7 +211,8 @@ type RefInfo struct {
 // RegisterJiraDevelopmentTool registers the jira_get_development_information tool
 func RegisterJiraDevelopmentTool(s *server.MCPServer) {
 	tool := mcp.NewTool("jira_get_development_information",
-		mcp.WithDescription("Retrieve branches, pull requests, commits, and builds linked to a Jira issue via development tool integrations (GitHub, GitLab, Bitbucket, CI/CD providers). Returns human-readable formatted text showing all development work associated with the issue."),
+		readOnlyTool(),
+		mcp.WithDescription("Retrieve branches, pull requests, commits, and builds linked to a Jira issue via development tool integrations (GitHub, GitLab, Bitbucket, CI/CD providers). Returns YAML text showing all development work associated with the issue, or JSON with output_format=json."),
 		mcp.WithString("issue_key",
 			mcp.Required(),
 			mcp.Description("The Jira issue key (e.g., PROJ-123)")),
@@ -211,8 +224,9 @@ func RegisterJiraDevelopmentTool(s *server.MCPServer) {
 			mcp.Description("Include commits in the response (default: true)")),
 		mcp.WithBoolean("include_builds",
 			mcp.Description("Include CI/CD builds in the response (default: true)")),
+		withOutputFormat[DevelopmentInfoOutput](),
 	)
-	s.AddTool(tool, mcp.NewTypedToolHandler(jiraGetDevelopmentInfoHandler))
+	addTool(s, tool, mcp.NewTypedToolHandler(jiraGetDevelopmentInfoHandler))
 }
 
 // jiraGetDevelopmentInfoHandler retrieves development information for a Jira issue.
@@ -224,7 +238,7 @@ func RegisterJiraDevelopmentTool(s *server.MCPServer) {
 // The detail endpoint REQUIRES the applicationType parameter (e.g., "GitLab", "GitHub", "Bitbucket").
 // Supported dataType values: repository, pullrequest, branch, build (but NOT deployment).
 func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequest, input GetDevelopmentInfoInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	// Default all filters to true if not explicitly set to false
 	includeBranches := input.IncludeBranches
@@ -242,13 +256,13 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 
 	// Step 1: Convert issue key to numeric ID
 	// The dev-status endpoint requires numeric issue ID, not the issue key
-	issue, response, err := client.Issue.Get(ctx, input.IssueKey, nil, []string{"id"})
+	issue, response, err := getIssue(ctx, client, input.IssueKey, nil, []string{"id"})
 	if err != nil {
 		if response != nil && response.Code == 404 {
-			return nil, fmt.Errorf("failed to retrieve development information: issue not found (endpoint: /rest/api/3/issue/%s)", input.IssueKey)
+			return nil, fmt.Errorf("failed to retrieve development information: issue not found (endpoint: /%s)", restAPI(ctx, "issue/"+input.IssueKey))
 		}
 		if response != nil && response.Code == 401 {
-			return nil, fmt.Errorf("failed to retrieve development information: authentication failed (endpoint: /rest/api/3/issue/%s)", input.IssueKey)
+			return nil, fmt.Errorf("failed to retrieve development information: authentication failed (endpoint: /%s)", restAPI(ctx, "issue/"+input.IssueKey))
 		}
 		return nil, fmt.Errorf("failed to retrieve issue: %w", err)
 	}
@@ -275,7 +289,9 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal error response to YAML: %w", err)
 			}
-			return mcp.NewToolResultText(string(yamlBytes)), nil
+			output := newDevelopmentInfoOutput(input.IssueKey)
+			output.Message = "Dev-status API endpoint not found"
+			return formatResult(input.OutputFormat, output, string(yamlBytes))
 		}
 		return nil, fmt.Errorf("failed to retrieve development summary: %w", err)
 	}
@@ -305,7 +321,9 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal empty response to YAML: %w", err)
 		}
-		return mcp.NewToolResultText(string(yamlBytes)), nil
+		output := newDevelopmentInfoOutput(input.IssueKey)
+		output.Message = "No development integrations found"
+		return formatResult(input.OutputFormat, output, string(yamlBytes))
 	}
 
 	// Step 3: Call detail endpoint for each (appType, dataType) pair from summary
@@ -379,5 +397,23 @@ func jiraGetDevelopmentInfoHandler(ctx context.Context, request mcp.CallToolRequ
 		return nil, fmt.Errorf("failed to marshal result to YAML: %w", err)
 	}
 
-	return mcp.NewToolResultText(string(yamlBytes)), nil
+	output := DevelopmentInfoOutput{
+		IssueKey:     input.IssueKey,
+		Branches:     filteredBranches,
+		PullRequests: filteredPullRequests,
+		Repositories: filteredRepositories,
+		Builds:       filteredBuilds,
+	}
+	return formatResult(input.OutputFormat, output, string(yamlBytes))
+}
+
+// newDevelopmentInfoOutput returns an output with empty lists, so JSON consumers never see null
+func newDevelopmentInfoOutput(issueKey string) DevelopmentInfoOutput {
+	return DevelopmentInfoOutput{
+		IssueKey:     issueKey,
+		Branches:     []Branch{},
+		PullRequests: []PullRequest{},
+		Repositories: []Repository{},
+		Builds:       []Build{},
+	}
 }
@@ -0,0 +1,371 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Input types for typed tools
+type ListFieldsInput struct {
+	Query      string `json:"query,omitempty"`
+	CustomOnly bool   `json:"custom_only,omitempty"`
+	OutputFormatInput
+}
+
+// FieldOutput is a field in the result of jira_list_fields
+type FieldOutput struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Custom     bool   `json:"custom"`
+	Type       string `json:"type,omitempty" jsonschema_description:"Schema type, e.g. number or array<option>"`
+	CustomType string `json:"custom_type,omitempty"`
+}
+
+// ListFieldsOutput is the result of jira_list_fields
+type ListFieldsOutput struct {
+	Fields []FieldOutput `json:"fields"`
+}
+
+func RegisterJiraFieldTool(s *server.MCPServer) {
+	jiraListFieldsTool := mcp.NewTool("jira_list_fields",
+		readOnlyTool(),
+		mcp.WithDescription("List Jira fields with their IDs (e.g., customfield_10016) and schema types. Use the field names with the custom_fields argument of jira_create_issue and jira_update_issue"),
+		mcp.WithString("query", mcp.Description("Only return fields whose name or ID contains this text (case-insensitive)")),
+		mcp.WithBoolean("custom_only", mcp.Description("If true, only return custom fields")),
+		withOutputFormat[ListFieldsOutput](),
+	)
+	addTool(s, jiraListFieldsTool, mcp.NewTypedToolHandler(jiraListFieldsHandler))
+}
+
+func jiraListFieldsHandler(ctx context.Context, request mcp.CallToolRequest, input ListFieldsInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	fields, err := getFields(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(input.Query)
+
+	var result strings.Builder
+	output := ListFieldsOutput{Fields: []FieldOutput{}}
+	count := 0
+	for _, field := range fields {
+		if input.CustomOnly && !field.Custom {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(field.Name), query) && !strings.Contains(strings.ToLower(field.ID), query) {
+			continue
+		}
+
+		fieldOutput := FieldOutput{ID: field.ID, Name: field.Name, Custom: field.Custom}
+		if field.Schema != nil {
+			fieldOutput.Type = fieldSchemaType(field.Schema)
+			fieldOutput.CustomType = field.Schema.Custom
+		}
+		output.Fields = append(output.Fields, fieldOutput)
+
+		result.WriteString(fmt.Sprintf("- %s (ID: %s)", field.Name, field.ID))
+		if field.Schema != nil {
+			result.WriteString(fmt.Sprintf(" | Type: %s", fieldSchemaType(field.Schema)))
+			if field.Schema.Custom != "" {
+				result.WriteString(fmt.Sprintf(" | Custom Type: %s", field.Schema.Custom))
+			}
+		}
+		result.WriteString("\n")
+		count++
+	}
+
+	if count == 0 {
+		return formatResult(input.OutputFormat, output, "No fields found matching the criteria.")
+	}
+
+	return formatResult(input.OutputFormat, output, fmt.Sprintf("Fields (%d):\n\n%s", count, result.String()))
+}
+
+// fieldCacheTTL controls how long the site's field list is reused between calls.
+const fieldCacheTTL = 10 * time.Minute
+
+var fieldCache = struct {
+	sync.Mutex
+	fields  map[string][]*models.IssueFieldScheme
+	fetched map[string]time.Time
+}{
+	fields:  map[string][]*models.IssueFieldScheme{},
+	fetched: map[string]time.Time{},
+}
+
+// getFields returns every field of the site sorted by name, cached per site for fieldCacheTTL.
+func getFields(ctx context.Context, client *jira.Client) ([]*models.IssueFieldScheme, error) {
+	site := client.Site.String()
+
+	fieldCache.Lock()
+	defer fieldCache.Unlock()
+
+	if fields, ok := fieldCache.fields[site]; ok && time.Since(fieldCache.fetched[site]) < fieldCacheTTL {
+		return fields, nil
+	}
+
+	fields, response, err := client.Issue.Field.Gets(ctx)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get fields: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get fields: %v", err)
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return strings.ToLower(fields[i].Name) < strings.ToLower(fields[j].Name)
+	})
+
+	fieldCache.fields[site] = fields
+	fieldCache.fetched[site] = time.Now()
+
+	return fields, nil
+}
+
+// resolveField finds a field by ID, key or case-insensitive name.
+// A name shared by several fields is rejected so the caller can pick the ID instead.
+func resolveField(fields []*models.IssueFieldScheme, nameOrID string) (*models.IssueFieldScheme, error) {
+	for _, field := range fields {
+		if field.ID == nameOrID || field.Key == nameOrID {
+			return field, nil
+		}
+	}
+
+	var matches []*models.IssueFieldScheme
+	for _, field := range fields {
+		if strings.EqualFold(field.Name, nameOrID) {
+			matches = append(matches, field)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown field %q, use jira_list_fields to find the field name or ID", nameOrID)
+	case 1:
+		return matches[0], nil
+	}
+
+	var ids []string
+	for _, match := range matches {
+		ids = append(ids, match.ID)
+	}
+	return nil, fmt.Errorf("field name %q is ambiguous, use one of these IDs instead: %s", nameOrID, strings.Join(ids, ", "))
+}
+
+// applyCustomFields resolves custom field names and merges their coerced values into the
+// "fields" block of a payload built by buildIssuePayload.
+func applyCustomFields(ctx context.Context, client *jira.Client, payload map[string]interface{}, customFields map[string]interface{}) error {
+	if len(customFields) == 0 {
+		return nil
+	}
+
+	fields, err := getFields(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	fieldsMap, _ := payload["fields"].(map[string]interface{})
+	if fieldsMap == nil {
+		fieldsMap = map[string]interface{}{}
+		payload["fields"] = fieldsMap
+	}
+
+	var problems []string
+	for name, value := range customFields {
+		field, err := resolveField(fields, name)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+
+		coerced, err := coerceFieldValue(field, value)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if isServer(ctx) {
+			coerced = toServerFieldValue(coerced)
+		}
+
+		fieldsMap[field.ID] = coerced
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid custom_fields:\n- %s", strings.Join(problems, "\n- "))
+	}
+
+	return nil
+}
+
+// coerceFieldValue converts a loosely typed tool argument into the JSON shape Jira expects
+// for the field's schema. Objects are passed through unchanged so callers can always send
+// the raw Jira representation, and null clears the field.
+func coerceFieldValue(field *models.IssueFieldScheme, value interface{}) (interface{}, error) {
+	if value == nil || field.Schema == nil {
+		return value, nil
+	}
+	if _, ok := value.(map[string]interface{}); ok {
+		return value, nil
+	}
+
+	schema := field.Schema
+	customType := schema.Custom
+	if index := strings.LastIndex(customType, ":"); index >= 0 {
+		customType = customType[index+1:]
+	}
+
+	switch customType {
+	case "cascadingselect":
+		parent, child, err := cascadingValues(value)
+		if err != nil {
+			return nil, fieldValueError(field, err)
+		}
+		option := map[string]interface{}{"value": parent}
+		if child != "" {
+			option["child"] = map[string]interface{}{"value": child}
+		}
+		return option, nil
+	case "gh-sprint":
+		id, err := toInt(value)
+		if err != nil {
+			return nil, fieldValueError(field, fmt.Errorf("expected a sprint ID: %v", err))
+		}
+		return id, nil
+	case "textarea":
+		return util.MarkdownToADF(fmt.Sprint(value)), nil
+	}
+
+	switch schema.Type {
+	case "number":
+		number, err := toFloat(value)
+		if err != nil {
+			return nil, fieldValueError(field, err)
+		}
+		return number, nil
+	case "string":
+		return fmt.Sprint(value), nil
+	case "date":
+		date := fmt.Sprint(value)
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, fieldValueError(field, fmt.Errorf("expected YYYY-MM-DD, got %q", date))
+		}
+		return date, nil
+	case "datetime":
+		return fmt.Sprint(value), nil
+	case "array":
+		var items []interface{}
+		for _, item := range toList(value) {
+			if _, ok := item.(map[string]interface{}); ok {
+				items = append(items, item)
+				continue
+			}
+			items = append(items, referenceValue(schema.Items, fmt.Sprint(item)))
+		}
+		return items, nil
+	case "option", "user", "group", "version", "project", "priority", "component", "resolution", "securitylevel":
+		return referenceValue(schema.Type, fmt.Sprint(value)), nil
+	}
+
+	return value, nil
+}
+
+// referenceValue wraps a scalar in the object Jira uses to reference an entity of the given schema type.
+func referenceValue(schemaType, value string) interface{} {
+	switch schemaType {
+	case "option":
+		return map[string]interface{}{"value": value}
+	case "user":
+		return map[string]interface{}{"accountId": value}
+	case "project":
+		return map[string]interface{}{"key": value}
+	case "group", "version", "component", "priority", "resolution", "securitylevel":
+		return map[string]interface{}{"name": value}
+	}
+	return value
+}
+
+// cascadingValues accepts "Parent > Child", ["Parent", "Child"] or a single parent value.
+func cascadingValues(value interface{}) (string, string, error) {
+	switch v := value.(type) {
+	case string:
+		parts := strings.SplitN(v, ">", 2)
+		parent := strings.TrimSpace(parts[0])
+		if len(parts) == 1 {
+			return parent, "", nil
+		}
+		return parent, strings.TrimSpace(parts[1]), nil
+	case []interface{}:
+		if len(v) == 0 || len(v) > 2 {
+			return "", "", fmt.Errorf("expected [parent, child], got %d values", len(v))
+		}
+		if len(v) == 1 {
+			return fmt.Sprint(v[0]), "", nil
+		}
+		return fmt.Sprint(v[0]), fmt.Sprint(v[1]), nil
+	}
+	return "", "", fmt.Errorf("expected \"Parent > Child\", got %v", value)
+}
+
+func fieldValueError(field *models.IssueFieldScheme, err error) error {
+	return fmt.Errorf("field %q (%s): %v", field.Name, field.ID, err)
+}
+
+func fieldSchemaType(schema *models.IssueFieldSchemaScheme) string {
+	if schema.Type == "array" && schema.Items != "" {
+		return fmt.Sprintf("array<%s>", schema.Items)
+	}
+	return schema.Type
+}
+
+func toList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case string:
+		var items []interface{}
+		for _, item := range splitList(v) {
+			items = append(items, item)
+		}
+		return items
+	}
+	return []interface{}{value}
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected a number, got %q", v)
+		}
+		return number, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %v", value)
+}
+
+func toInt(value interface{}) (int, error) {
+	number, err := toFloat(value)
+	if err != nil {
+		return 0, err
+	}
+	return int(number), nil
+}
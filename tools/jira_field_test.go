@@ -0,0 +1,106 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func TestCoerceFieldValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *models.IssueFieldSchemaScheme
+		value  interface{}
+		want   interface{}
+	}{
+		{
+			name:   "story points from string",
+			schema: &models.IssueFieldSchemaScheme{Type: "number", Custom: "com.atlassian.jira.plugin.system.customfieldtypes:float"},
+			value:  "5",
+			want:   5.0,
+		},
+		{
+			name:   "single select",
+			schema: &models.IssueFieldSchemaScheme{Type: "option", Custom: "com.atlassian.jira.plugin.system.customfieldtypes:select"},
+			value:  "High",
+			want:   map[string]interface{}{"value": "High"},
+		},
+		{
+			name:   "multi select from comma list",
+			schema: &models.IssueFieldSchemaScheme{Type: "array", Items: "option", Custom: "com.atlassian.jira.plugin.system.customfieldtypes:multiselect"},
+			value:  "iOS, Android",
+			want:   []interface{}{map[string]interface{}{"value": "iOS"}, map[string]interface{}{"value": "Android"}},
+		},
+		{
+			name:   "user picker",
+			schema: &models.IssueFieldSchemaScheme{Type: "user", Custom: "com.atlassian.jira.plugin.system.customfieldtypes:userpicker"},
+			value:  "5b10a2844c20165700ede21g",
+			want:   map[string]interface{}{"accountId": "5b10a2844c20165700ede21g"},
+		},
+		{
+			name:   "cascading select",
+			schema: &models.IssueFieldSchemaScheme{Type: "option-with-child", Custom: "com.atlassian.jira.plugin.system.customfieldtypes:cascadingselect"},
+			value:  "EU > Germany",
+			want:   map[string]interface{}{"value": "EU", "child": map[string]interface{}{"value": "Germany"}},
+		},
+		{
+			name:   "sprint",
+			schema: &models.IssueFieldSchemaScheme{Type: "array", Items: "json", Custom: "com.pyxis.greenhopper.jira:gh-sprint"},
+			value:  "42",
+			want:   42,
+		},
+		{
+			name:   "raw object passes through",
+			schema: &models.IssueFieldSchemaScheme{Type: "option"},
+			value:  map[string]interface{}{"id": "10001"},
+			want:   map[string]interface{}{"id": "10001"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := &models.IssueFieldScheme{ID: "customfield_10000", Name: tt.name, Schema: tt.schema}
+			got, err := coerceFieldValue(field, tt.value)
+			if err != nil {
+				t.Fatalf("coerceFieldValue() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coerceFieldValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoerceFieldValue_InvalidDate(t *testing.T) {
+	field := &models.IssueFieldScheme{ID: "customfield_10001", Name: "Target Date", Schema: &models.IssueFieldSchemaScheme{Type: "date"}}
+	if _, err := coerceFieldValue(field, "next friday"); err == nil {
+		t.Fatal("expected an error for an invalid date")
+	}
+}
+
+func TestResolveField(t *testing.T) {
+	fields := []*models.IssueFieldScheme{
+		{ID: "customfield_10016", Name: "Story Points"},
+		{ID: "customfield_10020", Name: "Team"},
+		{ID: "customfield_10021", Name: "Team"},
+	}
+
+	field, err := resolveField(fields, "story points")
+	if err != nil || field.ID != "customfield_10016" {
+		t.Fatalf("resolveField(story points) = %v, %v", field, err)
+	}
+
+	field, err = resolveField(fields, "customfield_10021")
+	if err != nil || field.ID != "customfield_10021" {
+		t.Fatalf("resolveField(customfield_10021) = %v, %v", field, err)
+	}
+
+	if _, err := resolveField(fields, "Team"); err == nil {
+		t.Fatal("expected an error for an ambiguous field name")
+	}
+
+	if _, err := resolveField(fields, "Severity"); err == nil {
+		t.Fatal("expected an error for an unknown field name")
+	}
+}
@@ -3,6 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -13,6 +14,7 @@ import (
 // GetIssueHistoryInput defines the input parameters for getting issue history
 type GetIssueHistoryInput struct {
 	IssueKey string `json:"issue_key" validate:"required"`
+	OutputFormatInput
 }
 
 // HistoryItem represents a single change in the issue history
@@ -38,17 +40,19 @@ type GetIssueHistoryOutput struct {
 
 func RegisterJiraHistoryTool(s *server.MCPServer) {
 	jiraGetIssueHistoryTool := mcp.NewTool("jira_get_issue_history",
+		readOnlyTool(),
 		mcp.WithDescription("Retrieve the complete change history of a Jira issue"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		withOutputFormat[GetIssueHistoryOutput](),
 	)
-	s.AddTool(jiraGetIssueHistoryTool, mcp.NewTypedToolHandler(jiraGetIssueHistoryHandler))
+	addTool(s, jiraGetIssueHistoryTool, mcp.NewTypedToolHandler(jiraGetIssueHistoryHandler))
 }
 
 func jiraGetIssueHistoryHandler(ctx context.Context, request mcp.CallToolRequest, input GetIssueHistoryInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 	
 	// Get issue with changelog expanded
-	issue, response, err := client.Issue.Get(ctx, input.IssueKey, nil, []string{"changelog"})
+	issue, response, err := getIssue(ctx, client, input.IssueKey, nil, []string{"changelog"})
 	if err != nil {
 		if response != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("failed to get issue history: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)), nil
@@ -56,8 +60,9 @@ func jiraGetIssueHistoryHandler(ctx context.Context, request mcp.CallToolRequest
 		return mcp.NewToolResultError(fmt.Sprintf("failed to get issue history: %v", err)), nil
 	}
 
-	if len(issue.Changelog.Histories) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No history found for issue %s", input.IssueKey)), nil
+	if issue.Changelog == nil || len(issue.Changelog.Histories) == 0 {
+		output := GetIssueHistoryOutput{IssueKey: input.IssueKey, History: []HistoryEntry{}}
+		return formatResult(input.OutputFormat, output, fmt.Sprintf("No history found for issue %s", input.IssueKey))
 	}
 
 	// Build structured output
@@ -110,5 +115,14 @@ func jiraGetIssueHistoryHandler(ctx context.Context, request mcp.CallToolRequest
 		Count:    len(historyEntries),
 	}
 
-	return mcp.NewToolResultJSON(output)
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("History for %s (%d entries):\n", output.IssueKey, output.Count))
+	for _, entry := range output.History {
+		result.WriteString(fmt.Sprintf("\n%s by %s\n", entry.Date, entry.Author))
+		for _, change := range entry.Changes {
+			result.WriteString(fmt.Sprintf("  - %s: %s -> %s\n", change.Field, change.FromString, change.ToString))
+		}
+	}
+
+	return formatResult(input.OutputFormat, output, result.String())
 } 
\ No newline at end of file
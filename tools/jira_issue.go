@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -17,6 +18,7 @@ type GetIssueInput struct {
 	IssueKey string `json:"issue_key" validate:"required"`
 	Fields   string `json:"fields,omitempty"`
 	Expand   string `json:"expand,omitempty"`
+	OutputFormatInput
 }
 
 type CreateIssueInput struct {
@@ -24,6 +26,8 @@ type CreateIssueInput struct {
 	Summary     string `json:"summary" validate:"required"`
 	Description string `json:"description" validate:"required"`
 	IssueType   string `json:"issue_type" validate:"required"`
+	IssueFieldsInput
+	OutputFormatInput
 }
 
 type CreateChildIssueInput struct {
@@ -31,72 +35,134 @@ type CreateChildIssueInput struct {
 	Summary        string `json:"summary" validate:"required"`
 	Description    string `json:"description" validate:"required"`
 	IssueType      string `json:"issue_type,omitempty"`
+	OutputFormatInput
 }
 
 type UpdateIssueInput struct {
 	IssueKey    string `json:"issue_key" validate:"required"`
 	Summary     string `json:"summary,omitempty"`
 	Description string `json:"description,omitempty"`
+	IssueFieldsInput
+	OutputFormatInput
 }
 
 type ListIssueTypesInput struct {
 	ProjectKey string `json:"project_key" validate:"required"`
+	OutputFormatInput
 }
 
 type DeleteIssueInput struct {
-	IssueKey string `json:"issue_key" validate:"required"`
+	IssueKey       string `json:"issue_key" validate:"required"`
+	DeleteSubtasks bool   `json:"delete_subtasks,omitempty"`
+	ConfirmationInput
+	OutputFormatInput
+}
+
+// CreatedIssueOutput is the result of jira_create_issue and jira_create_child_issue
+type CreatedIssueOutput struct {
+	Key    string `json:"key"`
+	ID     string `json:"id"`
+	URL    string `json:"url" jsonschema_description:"REST URL of the created issue"`
+	Parent string `json:"parent,omitempty" jsonschema_description:"Parent issue key of a child issue"`
+}
+
+// UpdatedIssueOutput is the result of jira_update_issue
+type UpdatedIssueOutput struct {
+	Key           string   `json:"key"`
+	UpdatedFields []string `json:"updated_fields" jsonschema_description:"IDs of the fields sent in the update"`
+}
+
+// DeletedIssueOutput is the result of jira_delete_issue
+type DeletedIssueOutput struct {
+	Key          string `json:"key"`
+	Summary      string `json:"summary,omitempty"`
+	SubtaskCount int    `json:"subtask_count" jsonschema_description:"Subtasks deleted with the issue, or that would be deleted in a preview"`
+	Deleted      bool   `json:"deleted" jsonschema_description:"False for a preview"`
+	ConfirmationOutput
+}
+
+// IssueTypeOutput is an issue type in the result of jira_list_issue_types
+type IssueTypeOutput struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Subtask     bool   `json:"subtask"`
+	// HierarchyLevel follows Jira: -1 for subtasks, 0 for standard issues, 1 for epics
+	HierarchyLevel int    `json:"hierarchy_level"`
+	Hierarchy      string `json:"hierarchy" jsonschema_description:"subtask, standard, epic, or level N above epics"`
+	IconURL        string `json:"icon_url,omitempty"`
+	Scope          string `json:"scope,omitempty"`
+}
+
+// ListIssueTypesOutput is the result of jira_list_issue_types
+type ListIssueTypesOutput struct {
+	IssueTypes []IssueTypeOutput `json:"issue_types"`
 }
 
 func RegisterJiraIssueTool(s *server.MCPServer) {
 	jiraGetIssueTool := mcp.NewTool("jira_get_issue",
+		readOnlyTool(),
 		mcp.WithDescription("Retrieve detailed information about a specific Jira issue including its status, assignee, description, subtasks, and available transitions"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
 		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to retrieve (e.g., 'summary,status,assignee'). If not specified, all fields are returned.")),
 		mcp.WithString("expand", mcp.Description("Comma-separated list of fields to expand for additional details (e.g., 'transitions,changelog,subtasks'). Default: 'transitions,changelog'")),
+		withOutputFormat[IssueOutput](),
 	)
-	s.AddTool(jiraGetIssueTool, mcp.NewTypedToolHandler(jiraGetIssueHandler))
+	addTool(s, jiraGetIssueTool, mcp.NewTypedToolHandler(jiraGetIssueHandler))
 
-	jiraCreateIssueTool := mcp.NewTool("jira_create_issue",
-		mcp.WithDescription("Create a new Jira issue with specified details. Returns the created issue's key, ID, and URL"),
+	jiraCreateIssueTool := mcp.NewTool("jira_create_issue", append([]mcp.ToolOption{
+		additiveTool(),
+		mcp.WithDescription("Create a new Jira issue with specified details such as assignee, priority, labels, components, versions, due date and parent. Returns the created issue's key, ID, and URL"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier where the issue will be created (e.g., KP, PROJ)")),
 		mcp.WithString("summary", mcp.Required(), mcp.Description("Brief title or headline of the issue")),
 		mcp.WithString("description", mcp.Required(), mcp.Description("Detailed explanation of the issue")),
 		mcp.WithString("issue_type", mcp.Required(), mcp.Description("Type of issue to create (common types: Bug, Task, Subtask, Story, Epic)")),
-	)
-	s.AddTool(jiraCreateIssueTool, mcp.NewTypedToolHandler(jiraCreateIssueHandler))
+		withOutputFormat[CreatedIssueOutput](),
+	}, issueFieldToolOptions()...)...)
+	addTool(s, jiraCreateIssueTool, mcp.NewTypedToolHandler(jiraCreateIssueHandler))
 
 	jiraCreateChildIssueTool := mcp.NewTool("jira_create_child_issue",
+		additiveTool(),
 		mcp.WithDescription("Create a child issue (sub-task) linked to a parent issue in Jira. Returns the created issue's key, ID, and URL"),
 		mcp.WithString("parent_issue_key", mcp.Required(), mcp.Description("The parent issue key to which this child issue will be linked (e.g., KP-2)")),
 		mcp.WithString("summary", mcp.Required(), mcp.Description("Brief title or headline of the child issue")),
 		mcp.WithString("description", mcp.Required(), mcp.Description("Detailed explanation of the child issue")),
-		mcp.WithString("issue_type", mcp.Description("Type of child issue to create (defaults to 'Subtask' if not specified)")),
+		mcp.WithString("issue_type", mcp.Description("Type of child issue to create. Defaults to the subtask type of the parent's project (e.g., Sub-task or Subtask)")),
+		withOutputFormat[CreatedIssueOutput](),
 	)
-	s.AddTool(jiraCreateChildIssueTool, mcp.NewTypedToolHandler(jiraCreateChildIssueHandler))
+	addTool(s, jiraCreateChildIssueTool, mcp.NewTypedToolHandler(jiraCreateChildIssueHandler))
 
-	jiraUpdateIssueTool := mcp.NewTool("jira_update_issue",
-		mcp.WithDescription("Modify an existing Jira issue's details. Supports partial updates - only specified fields will be changed"),
+	jiraUpdateIssueTool := mcp.NewTool("jira_update_issue", append([]mcp.ToolOption{
+		destructiveTool(true),
+		mcp.WithDescription("Modify an existing Jira issue's details. Supports partial updates - only specified fields will be changed. Use add_labels/remove_labels to edit labels without replacing them"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the issue to update (e.g., KP-2)")),
 		mcp.WithString("summary", mcp.Description("New title for the issue (optional)")),
 		mcp.WithString("description", mcp.Description("New description for the issue (optional)")),
-	)
-	s.AddTool(jiraUpdateIssueTool, mcp.NewTypedToolHandler(jiraUpdateIssueHandler))
+		withOutputFormat[UpdatedIssueOutput](),
+	}, issueFieldToolOptions()...)...)
+	addTool(s, jiraUpdateIssueTool, mcp.NewTypedToolHandler(jiraUpdateIssueHandler))
 
 	jiraListIssueTypesTool := mcp.NewTool("jira_list_issue_types",
-		mcp.WithDescription("List all available issue types in a Jira project with their IDs, names, descriptions, and other attributes"),
+		readOnlyTool(),
+		mcp.WithDescription("List the issue types available in a Jira project's issue type scheme with their IDs, names, descriptions and hierarchy level (epic, standard or subtask)"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier to list issue types for (e.g., KP, PROJ)")),
+		withOutputFormat[ListIssueTypesOutput](),
 	)
-	s.AddTool(jiraListIssueTypesTool, mcp.NewTypedToolHandler(jiraListIssueTypesHandler))
+	addTool(s, jiraListIssueTypesTool, mcp.NewTypedToolHandler(jiraListIssueTypesHandler))
 
 	jiraDeleteIssueTool := mcp.NewTool("jira_delete_issue",
-		mcp.WithDescription("Delete a Jira issue permanently. This action cannot be undone."),
+		destructiveTool(true),
+		mcp.WithDescription("Delete a Jira issue permanently. This action cannot be undone. An issue with subtasks is only deleted when delete_subtasks is set"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the issue to delete (e.g., SHTP-6216, PROJ-123)")),
+		mcp.WithBoolean("delete_subtasks", mcp.Description("If true, also delete the issue's subtasks. Required when the issue has subtasks")),
+		withConfirmation(),
+		withOutputFormat[DeletedIssueOutput](),
 	)
-	s.AddTool(jiraDeleteIssueTool, mcp.NewTypedToolHandler(jiraDeleteIssueHandler))
+	addTool(s, jiraDeleteIssueTool, mcp.NewTypedToolHandler(jiraDeleteIssueHandler))
 }
 
 func jiraGetIssueHandler(ctx context.Context, request mcp.CallToolRequest, input GetIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	// Parse fields parameter
 	var fields []string
@@ -109,8 +175,9 @@ func jiraGetIssueHandler(ctx context.Context, request mcp.CallToolRequest, input
 	if input.Expand != "" {
 		expand = strings.Split(strings.ReplaceAll(input.Expand, " ", ""), ",")
 	}
+	expand = appendExpand(appendExpand(expand, "names"), "schema")
 	
-	issue, response, err := client.Issue.Get(ctx, input.IssueKey, fields, expand)
+	issue, response, err := getIssue(ctx, client, input.IssueKey, fields, expand)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -118,25 +185,41 @@ func jiraGetIssueHandler(ctx context.Context, request mcp.CallToolRequest, input
 		return nil, fmt.Errorf("failed to get issue: %v", err)
 	}
 
+	// Custom fields are only present in the raw response, models.IssueScheme drops them
+	customFields, _ := util.ExtractIssueCustomFields(response.Bytes.Bytes(), nil, util.CustomFieldFilterFromEnv())
+
 	// Use the new util function to format the issue
-	formattedIssue := util.FormatJiraIssue(issue)
+	formattedIssue := util.FormatJiraIssueWithCustomFields(issue, customFields)
 
-	return mcp.NewToolResultText(formattedIssue), nil
+	return formatResult(input.OutputFormat, newIssueOutput(issue, customFields), formattedIssue)
 }
 
 func jiraCreateIssueHandler(ctx context.Context, request mcp.CallToolRequest, input CreateIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
-	var payload = models.IssueScheme{
-		Fields: &models.IssueFieldsScheme{
-			Summary:     input.Summary,
-			Project:     &models.ProjectScheme{Key: input.ProjectKey},
-			Description: util.MarkdownToADF(input.Description),
-			IssueType:   &models.IssueTypeScheme{Name: input.IssueType},
-		},
+	if err := checkWritePolicy(actionCreate, input.ProjectKey, input.IssueType); err != nil {
+		return nil, err
+	}
+
+	payload, err := buildIssuePayload(&models.IssueFieldsScheme{
+		Summary:     input.Summary,
+		Project:     &models.ProjectScheme{Key: input.ProjectKey},
+		Description: util.MarkdownToADF(input.Description),
+		IssueType:   &models.IssueTypeScheme{Name: input.IssueType},
+	}, input.IssueFieldsInput)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyCustomFields(ctx, client, payload, input.CustomFields); err != nil {
+		return nil, err
+	}
+
+	if err := checkRequiredFields(ctx, client, input.ProjectKey, input.IssueType, payload); err != nil {
+		return nil, err
 	}
 
-	issue, response, err := client.Issue.Create(ctx, &payload, nil)
+	issue, response, err := createIssueWithPayload(ctx, client, payload)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to create issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -145,14 +228,14 @@ func jiraCreateIssueHandler(ctx context.Context, request mcp.CallToolRequest, in
 	}
 
 	result := fmt.Sprintf("Issue created successfully!\nKey: %s\nID: %s\nURL: %s", issue.Key, issue.ID, issue.Self)
-	return mcp.NewToolResultText(result), nil
+	return formatResult(input.OutputFormat, CreatedIssueOutput{Key: issue.Key, ID: issue.ID, URL: issue.Self}, result)
 }
 
 func jiraCreateChildIssueHandler(ctx context.Context, request mcp.CallToolRequest, input CreateChildIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	// Get the parent issue to retrieve its project
-	parentIssue, response, err := client.Issue.Get(ctx, input.ParentIssueKey, nil, nil)
+	parentIssue, response, err := getIssue(ctx, client, input.ParentIssueKey, nil, nil)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to get parent issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -160,10 +243,28 @@ func jiraCreateChildIssueHandler(ctx context.Context, request mcp.CallToolReques
 		return nil, fmt.Errorf("failed to get parent issue: %v", err)
 	}
 
-	// Default issue type is Sub-task if not specified
-	issueType := "Subtask"
-	if input.IssueType != "" {
-		issueType = input.IssueType
+	// Without an explicit type, use the type one level below the parent in its project,
+	// subtask types are named differently across projects ("Sub-task", "Subtask", ...)
+	issueType := input.IssueType
+	if issueType == "" {
+		issueTypes, err := getProjectIssueTypes(ctx, client, parentIssue.Fields.Project.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		parentLevel := 0
+		if parentIssue.Fields.IssueType != nil {
+			parentLevel = parentIssue.Fields.IssueType.HierarchyLevel
+		}
+
+		issueType, err = childIssueType(issueTypes, parentIssue.Fields.Project.Key, parentLevel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkWritePolicy(actionCreate, parentIssue.Fields.Project.Key, issueType); err != nil {
+		return nil, err
 	}
 
 	var payload = models.IssueScheme{
@@ -176,7 +277,13 @@ func jiraCreateChildIssueHandler(ctx context.Context, request mcp.CallToolReques
 		},
 	}
 
-	issue, response, err := client.Issue.Create(ctx, &payload, nil)
+	payloadMap, err := payload.ToMap()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build issue payload: %w", err)
+	}
+
+	issue, response, err := createIssueWithPayload(ctx, client, payloadMap)
+	auditRequest(ctx, response, input.ParentIssueKey)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to create child issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -190,25 +297,44 @@ func jiraCreateChildIssueHandler(ctx context.Context, request mcp.CallToolReques
 	if issueType == "Bug" {
 		result += "\n\nA bug should be linked to a Story or Task. Next step should be to create relationship between the bug and the story or task."
 	}
-	return mcp.NewToolResultText(result), nil
+
+	output := CreatedIssueOutput{Key: issue.Key, ID: issue.ID, URL: issue.Self, Parent: input.ParentIssueKey}
+	return formatResult(input.OutputFormat, output, result)
 }
 
 func jiraUpdateIssueHandler(ctx context.Context, request mcp.CallToolRequest, input UpdateIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
-	payload := &models.IssueScheme{
-		Fields: &models.IssueFieldsScheme{},
-	}
+	fields := &models.IssueFieldsScheme{}
 
 	if input.Summary != "" {
-		payload.Fields.Summary = input.Summary
+		fields.Summary = input.Summary
 	}
 
 	if input.Description != "" {
-		payload.Fields.Description = util.MarkdownToADF(input.Description)
+		fields.Description = util.MarkdownToADF(input.Description)
 	}
 
-	response, err := client.Issue.Update(ctx, input.IssueKey, true, payload, nil, nil)
+	payload, err := buildIssuePayload(fields, input.IssueFieldsInput)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkIssueActions(ctx, client, input.IssueKey, updateActions(input, payload)...); err != nil {
+		return nil, err
+	}
+
+	if err := applyCustomFields(ctx, client, payload, input.CustomFields); err != nil {
+		return nil, err
+	}
+
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("no fields to update: provide at least one field to change")
+	}
+
+	before := auditSnapshot(ctx, client, input.IssueKey, payloadFieldIDs(payload))
+
+	response, err := editIssueWithPayload(ctx, client, input.IssueKey, payload)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to update issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -216,34 +342,65 @@ func jiraUpdateIssueHandler(ctx context.Context, request mcp.CallToolRequest, in
 		return nil, fmt.Errorf("failed to update issue: %v", err)
 	}
 
-	return mcp.NewToolResultText("Issue updated successfully!"), nil
+	auditChanges(ctx, client, input.IssueKey, before)
+
+	output := UpdatedIssueOutput{Key: input.IssueKey, UpdatedFields: payloadFieldIDs(payload)}
+	return formatResult(input.OutputFormat, output, "Issue updated successfully!")
+}
+
+// updateActions lists the write policy actions an update performs: setting the assignee assigns
+// the issue, setting any other field edits it
+func updateActions(input UpdateIssueInput, payload map[string]interface{}) []string {
+	edits := len(input.CustomFields) > 0
+	for _, id := range payloadFieldIDs(payload) {
+		if id != "assignee" {
+			edits = true
+		}
+	}
+
+	var actions []string
+	if edits || input.Assignee == "" {
+		actions = append(actions, actionEdit)
+	}
+	if input.Assignee != "" {
+		actions = append(actions, actionAssign)
+	}
+	return actions
 }
 
 func jiraListIssueTypesHandler(ctx context.Context, request mcp.CallToolRequest, input ListIssueTypesInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
-	issueTypes, response, err := client.Issue.Type.Gets(ctx)
+	issueTypes, err := getProjectIssueTypes(ctx, client, input.ProjectKey)
 	if err != nil {
-		if response != nil {
-			return nil, fmt.Errorf("failed to get issue types: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
-		}
-		return nil, fmt.Errorf("failed to get issue types: %v", err)
+		return nil, err
 	}
 
+	output := ListIssueTypesOutput{IssueTypes: []IssueTypeOutput{}}
+
 	if len(issueTypes) == 0 {
-		return mcp.NewToolResultText("No issue types found for this project."), nil
+		return formatResult(input.OutputFormat, output, "No issue types found for this project.")
 	}
 
 	var result strings.Builder
-	result.WriteString("Available Issue Types:\n\n")
+	result.WriteString(fmt.Sprintf("Issue Types in %s:\n\n", input.ProjectKey))
 
 	for _, issueType := range issueTypes {
-		subtaskType := ""
-		if issueType.Subtask {
-			subtaskType = " (Subtask Type)"
+		typeOutput := IssueTypeOutput{
+			ID:             issueType.ID,
+			Name:           issueType.Name,
+			Description:    issueType.Description,
+			Subtask:        issueType.Subtask,
+			HierarchyLevel: issueType.HierarchyLevel,
+			Hierarchy:      hierarchyName(issueType),
+			IconURL:        issueType.IconURL,
+		}
+		if issueType.Scope != nil {
+			typeOutput.Scope = issueType.Scope.Type
 		}
-		
-		result.WriteString(fmt.Sprintf("ID: %s\nName: %s%s\n", issueType.ID, issueType.Name, subtaskType))
+		output.IssueTypes = append(output.IssueTypes, typeOutput)
+
+		result.WriteString(fmt.Sprintf("ID: %s\nName: %s\nHierarchy: %s\n", issueType.ID, issueType.Name, typeOutput.Hierarchy))
 		if issueType.Description != "" {
 			result.WriteString(fmt.Sprintf("Description: %s\n", issueType.Description))
 		}
@@ -256,13 +413,53 @@ func jiraListIssueTypesHandler(ctx context.Context, request mcp.CallToolRequest,
 		result.WriteString("\n")
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return formatResult(input.OutputFormat, output, result.String())
 }
 
 func jiraDeleteIssueHandler(ctx context.Context, request mcp.CallToolRequest, input DeleteIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
-	response, err := client.Issue.Delete(ctx, input.IssueKey, false)
+	if err := checkIssuePolicy(ctx, client, actionDelete, input.IssueKey); err != nil {
+		return nil, err
+	}
+
+	issue, response, err := getIssue(ctx, client, input.IssueKey, []string{"summary", "subtasks"}, nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get issue: %v", err)
+	}
+
+	output := DeletedIssueOutput{Key: input.IssueKey}
+	var subtaskKeys []string
+	if issue.Fields != nil {
+		output.Summary = issue.Fields.Summary
+		for _, subtask := range issue.Fields.Subtasks {
+			subtaskKeys = append(subtaskKeys, subtask.Key)
+		}
+	}
+	output.SubtaskCount = len(subtaskKeys)
+
+	if len(subtaskKeys) > 0 && !input.DeleteSubtasks {
+		return nil, fmt.Errorf("issue %s has %d subtasks (%s), set delete_subtasks to delete them with the issue", input.IssueKey, len(subtaskKeys), strings.Join(subtaskKeys, ", "))
+	}
+
+	confirmation, err := requireConfirmation(ctx, request, input.ConfirmationToken)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation != nil {
+		output.ConfirmationOutput = *confirmation
+		text := fmt.Sprintf("Preview: issue %s (%s) would be deleted permanently", input.IssueKey, output.Summary)
+		if len(subtaskKeys) > 0 {
+			text += fmt.Sprintf(" with its %d subtasks: %s", len(subtaskKeys), strings.Join(subtaskKeys, ", "))
+		}
+		return formatResult(input.OutputFormat, output, text+"\n"+confirmationText(confirmation))
+	}
+
+	response, err = deleteIssue(ctx, client, input.IssueKey, input.DeleteSubtasks)
+	auditRequest(ctx, response, append([]string{input.IssueKey}, subtaskKeys...)...)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to delete issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -270,5 +467,66 @@ func jiraDeleteIssueHandler(ctx context.Context, request mcp.CallToolRequest, in
 		return nil, fmt.Errorf("failed to delete issue: %v", err)
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Issue %s deleted successfully!", input.IssueKey)), nil
+	output.Deleted = true
+	result := fmt.Sprintf("Issue %s deleted successfully!", input.IssueKey)
+	if len(subtaskKeys) > 0 {
+		result += fmt.Sprintf(" %d subtasks were deleted with it.", len(subtaskKeys))
+	}
+	return formatResult(input.OutputFormat, output, result)
+}
+
+// getProjectIssueTypes returns the issue types of a project's issue type scheme,
+// or the project's own types when it is team-managed
+func getProjectIssueTypes(ctx context.Context, client *jira.Client, projectKey string) ([]*models.IssueTypeScheme, error) {
+	project, response, err := client.Project.Get(ctx, projectKey, []string{"issueTypes"})
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get issue types: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get issue types: %v", err)
+	}
+	return project.IssueTypes, nil
+}
+
+// childIssueType picks the issue type one hierarchy level below a parent: the subtask type
+// under a standard issue, or the only standard type under an epic. When several types fit,
+// the caller has to choose, so they are listed in the error.
+func childIssueType(issueTypes []*models.IssueTypeScheme, projectKey string, parentLevel int) (string, error) {
+	if parentLevel < 0 {
+		return "", fmt.Errorf("subtasks cannot have child issues")
+	}
+
+	var candidates []string
+	for _, issueType := range issueTypes {
+		if parentLevel == 0 && issueType.Subtask {
+			return issueType.Name, nil
+		}
+		if parentLevel > 0 && !issueType.Subtask && issueType.HierarchyLevel == parentLevel-1 {
+			candidates = append(candidates, issueType.Name)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		if parentLevel == 0 {
+			return "", fmt.Errorf("project %s has no subtask issue type, set issue_type or use jira_create_issue with parent", projectKey)
+		}
+		return "", fmt.Errorf("project %s has no issue type below hierarchy level %d, set issue_type", projectKey, parentLevel)
+	case 1:
+		return candidates[0], nil
+	}
+	return "", fmt.Errorf("several issue types can be children of the parent in project %s, set issue_type to one of: %s", projectKey, strings.Join(candidates, ", "))
+}
+
+// hierarchyName describes an issue type's hierarchy level
+func hierarchyName(issueType *models.IssueTypeScheme) string {
+	switch {
+	case issueType.Subtask || issueType.HierarchyLevel < 0:
+		return "subtask"
+	case issueType.HierarchyLevel == 0:
+		return "standard"
+	case issueType.HierarchyLevel == 1:
+		return "epic"
+	}
+	return fmt.Sprintf("level %d", issueType.HierarchyLevel)
 }
@@ -0,0 +1,246 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// IssueFieldsInput holds the optional fields shared by jira_create_issue and jira_update_issue.
+// List values are comma-separated names. CustomFields is keyed by field name or ID and is
+// applied separately by applyCustomFields because it needs the site's field list.
+type IssueFieldsInput struct {
+	Assignee          string                 `json:"assignee,omitempty"`
+	Reporter          string                 `json:"reporter,omitempty"`
+	Priority          string                 `json:"priority,omitempty"`
+	Labels            string                 `json:"labels,omitempty"`
+	AddLabels         string                 `json:"add_labels,omitempty"`
+	RemoveLabels      string                 `json:"remove_labels,omitempty"`
+	Components        string                 `json:"components,omitempty"`
+	FixVersions       string                 `json:"fix_versions,omitempty"`
+	AddFixVersions    string                 `json:"add_fix_versions,omitempty"`
+	RemoveFixVersions string                 `json:"remove_fix_versions,omitempty"`
+	AffectsVersions   string                 `json:"affects_versions,omitempty"`
+	DueDate           string                 `json:"due_date,omitempty"`
+	OriginalEstimate  string                 `json:"original_estimate,omitempty"`
+	Environment       string                 `json:"environment,omitempty"`
+	Parent            string                 `json:"parent,omitempty"`
+	CustomFields      map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// issueFieldToolOptions declares the IssueFieldsInput arguments on a tool.
+func issueFieldToolOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("assignee", mcp.Description("Account ID of the assignee. Use 'unassigned' to clear the assignee")),
+		mcp.WithString("reporter", mcp.Description("Account ID of the reporter")),
+		mcp.WithString("priority", mcp.Description("Priority name (e.g., Highest, High, Medium, Low)")),
+		mcp.WithString("labels", mcp.Description("Comma-separated labels. Replaces all existing labels")),
+		mcp.WithString("add_labels", mcp.Description("Comma-separated labels to add while keeping existing labels")),
+		mcp.WithString("remove_labels", mcp.Description("Comma-separated labels to remove while keeping other labels")),
+		mcp.WithString("components", mcp.Description("Comma-separated component names")),
+		mcp.WithString("fix_versions", mcp.Description("Comma-separated fix version names. Replaces all existing fix versions")),
+		mcp.WithString("add_fix_versions", mcp.Description("Comma-separated fix version names to add while keeping existing fix versions")),
+		mcp.WithString("remove_fix_versions", mcp.Description("Comma-separated fix version names to remove while keeping other fix versions")),
+		mcp.WithString("affects_versions", mcp.Description("Comma-separated affected version names")),
+		mcp.WithString("due_date", mcp.Description("Due date in YYYY-MM-DD format")),
+		mcp.WithString("original_estimate", mcp.Description("Original time estimate (e.g., 3h, 2d, 1w 2d)")),
+		mcp.WithString("environment", mcp.Description("Environment details (supports markdown)")),
+		mcp.WithString("parent", mcp.Description("Key of the parent issue (e.g., an epic for a story, or a story for a subtask)")),
+		mcp.WithObject("custom_fields", mcp.Description("Custom field values keyed by field name or ID (e.g., {\"Story Points\": 5, \"Team\": \"Platform\", \"Severity\": \"High\", \"Region\": \"EU > Germany\"}). Values are converted to the field's type; use jira_list_fields to discover names")),
+	}
+}
+
+// buildIssuePayload merges the standard fields of input into fields and returns the
+// create/edit request body with its "fields" and "update" blocks.
+// Label and fix version additions and removals go through the update block so existing values are kept.
+func buildIssuePayload(fields *models.IssueFieldsScheme, input IssueFieldsInput) (map[string]interface{}, error) {
+	if fields == nil {
+		fields = &models.IssueFieldsScheme{}
+	}
+
+	var clearAssignee bool
+	if input.Assignee != "" {
+		if isUnassignedValue(input.Assignee) {
+			clearAssignee = true
+		} else {
+			fields.Assignee = &models.UserScheme{AccountID: input.Assignee}
+		}
+	}
+
+	if input.Reporter != "" {
+		fields.Reporter = &models.UserScheme{AccountID: input.Reporter}
+	}
+
+	if input.Priority != "" {
+		fields.Priority = &models.PriorityScheme{Name: input.Priority}
+	}
+
+	if labels := splitList(input.Labels); len(labels) > 0 {
+		fields.Labels = labels
+	}
+
+	for _, name := range splitList(input.Components) {
+		fields.Components = append(fields.Components, &models.ComponentScheme{Name: name})
+	}
+
+	for _, name := range splitList(input.FixVersions) {
+		fields.FixVersions = append(fields.FixVersions, &models.VersionScheme{Name: name})
+	}
+
+	for _, name := range splitList(input.AffectsVersions) {
+		fields.Versions = append(fields.Versions, &models.VersionScheme{Name: name})
+	}
+
+	if input.Parent != "" {
+		fields.Parent = &models.ParentScheme{Key: input.Parent}
+	}
+
+	payload, err := (&models.IssueScheme{Fields: fields}).ToMap()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build issue payload: %w", err)
+	}
+
+	fieldsMap, _ := payload["fields"].(map[string]interface{})
+	if fieldsMap == nil {
+		fieldsMap = map[string]interface{}{}
+	}
+
+	// IssueFieldsScheme has no due date, environment or time tracking fields
+	if input.DueDate != "" {
+		if _, err := time.Parse("2006-01-02", input.DueDate); err != nil {
+			return nil, fmt.Errorf("invalid due_date %q: expected YYYY-MM-DD", input.DueDate)
+		}
+		fieldsMap["duedate"] = input.DueDate
+	}
+
+	if input.Environment != "" {
+		fieldsMap["environment"] = util.MarkdownToADF(input.Environment)
+	}
+
+	if input.OriginalEstimate != "" {
+		fieldsMap["timetracking"] = map[string]interface{}{"originalEstimate": input.OriginalEstimate}
+	}
+
+	if clearAssignee {
+		fieldsMap["assignee"] = nil
+	}
+
+	update := map[string]interface{}{}
+	var labelOperations []map[string]interface{}
+	for _, label := range splitList(input.AddLabels) {
+		labelOperations = append(labelOperations, map[string]interface{}{"add": label})
+	}
+	for _, label := range splitList(input.RemoveLabels) {
+		labelOperations = append(labelOperations, map[string]interface{}{"remove": label})
+	}
+	if len(labelOperations) > 0 {
+		if _, ok := fieldsMap["labels"]; ok {
+			return nil, fmt.Errorf("labels cannot be combined with add_labels or remove_labels")
+		}
+		update["labels"] = labelOperations
+	}
+
+	var fixVersionOperations []map[string]interface{}
+	for _, name := range splitList(input.AddFixVersions) {
+		fixVersionOperations = append(fixVersionOperations, map[string]interface{}{"add": map[string]interface{}{"name": name}})
+	}
+	for _, name := range splitList(input.RemoveFixVersions) {
+		fixVersionOperations = append(fixVersionOperations, map[string]interface{}{"remove": map[string]interface{}{"name": name}})
+	}
+	if len(fixVersionOperations) > 0 {
+		if _, ok := fieldsMap["fixVersions"]; ok {
+			return nil, fmt.Errorf("fix_versions cannot be combined with add_fix_versions or remove_fix_versions")
+		}
+		update["fixVersions"] = fixVersionOperations
+	}
+
+	result := map[string]interface{}{}
+	if len(fieldsMap) > 0 {
+		result["fields"] = fieldsMap
+	}
+	if len(update) > 0 {
+		result["update"] = update
+	}
+
+	return result, nil
+}
+
+// payloadFieldIDs lists the fields set or edited by a payload built by buildIssuePayload, sorted by ID.
+func payloadFieldIDs(payload map[string]interface{}) []string {
+	var ids []string
+	for _, section := range []string{"fields", "update"} {
+		values, _ := payload[section].(map[string]interface{})
+		for id := range values {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// createIssueWithPayload posts a payload built by buildIssuePayload to the create issue endpoint.
+func createIssueWithPayload(ctx context.Context, client *jira.Client, payload map[string]interface{}) (*models.IssueResponseScheme, *models.ResponseScheme, error) {
+	if isServer(ctx) {
+		payload = toServerIssuePayload(payload)
+	}
+
+	req, err := client.NewRequest(ctx, http.MethodPost, restAPI(ctx, "issue"), "", payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	issue := new(models.IssueResponseScheme)
+	response, err := client.Call(req, issue)
+	if err != nil {
+		auditRequest(ctx, response)
+		return nil, response, err
+	}
+
+	auditRequest(ctx, response, issue.Key)
+	auditCreated(ctx, AuditResource{Type: auditResourceIssue, Issue: issue.Key})
+	return issue, response, nil
+}
+
+// editIssueWithPayload sends a payload built by buildIssuePayload to the edit issue endpoint.
+func editIssueWithPayload(ctx context.Context, client *jira.Client, issueKey string, payload map[string]interface{}) (*models.ResponseScheme, error) {
+	if isServer(ctx) {
+		payload = toServerIssuePayload(payload)
+	}
+
+	endpoint := restAPI(ctx, fmt.Sprintf("issue/%s?notifyUsers=true", issueKey))
+	req, err := client.NewRequest(ctx, http.MethodPut, endpoint, "", payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	response, err := client.Call(req, nil)
+	auditRequest(ctx, response, issueKey)
+	return response, err
+}
+
+// splitList splits a comma-separated argument into trimmed, non-empty values.
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func isUnassignedValue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "unassigned", "none", "-1":
+		return true
+	}
+	return false
+}
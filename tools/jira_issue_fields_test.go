@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildIssuePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   IssueFieldsInput
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "labels and components",
+			input: IssueFieldsInput{Labels: "backend, ,urgent", Components: "API,Web"},
+			want: map[string]string{
+				"fields.labels":     `["backend","urgent"]`,
+				"fields.components": `[{"name":"API"},{"name":"Web"}]`,
+			},
+		},
+		{
+			name:  "label and fix version changes",
+			input: IssueFieldsInput{AddLabels: "urgent", RemoveLabels: "triage", AddFixVersions: "2.0", RemoveFixVersions: "1.9"},
+			want: map[string]string{
+				"update.labels":      `[{"add":"urgent"},{"remove":"triage"}]`,
+				"update.fixVersions": `[{"add":{"name":"2.0"}},{"remove":{"name":"1.9"}}]`,
+			},
+		},
+		{
+			name:  "priority, assignee and versions",
+			input: IssueFieldsInput{Priority: "High", Assignee: "5b10a2844c20165700ede21g", FixVersions: "1.0", AffectsVersions: "0.9"},
+			want: map[string]string{
+				"fields.priority":    `{"name":"High"}`,
+				"fields.assignee":    `{"accountId":"5b10a2844c20165700ede21g"}`,
+				"fields.fixVersions": `[{"name":"1.0"}]`,
+				"fields.versions":    `[{"name":"0.9"}]`,
+			},
+		},
+		{
+			name:  "unassign",
+			input: IssueFieldsInput{Assignee: " Unassigned "},
+			want:  map[string]string{"fields.assignee": `null`},
+		},
+		{
+			name:  "due date, estimate and parent",
+			input: IssueFieldsInput{DueDate: "2024-12-31", OriginalEstimate: "3d", Parent: "KP-1"},
+			want: map[string]string{
+				"fields.duedate":      `"2024-12-31"`,
+				"fields.timetracking": `{"originalEstimate":"3d"}`,
+				"fields.parent":       `{"key":"KP-1"}`,
+			},
+		},
+		{name: "invalid due date", input: IssueFieldsInput{DueDate: "31/12/2024"}, wantErr: "invalid due_date"},
+		{name: "impossible due date", input: IssueFieldsInput{DueDate: "2024-02-30"}, wantErr: "invalid due_date"},
+		{name: "labels with label changes", input: IssueFieldsInput{Labels: "a", AddLabels: "b"}, wantErr: "labels cannot be combined"},
+		{name: "empty input", input: IssueFieldsInput{}, want: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := buildIssuePayload(nil, tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]string{}
+			for _, section := range []string{"fields", "update"} {
+				values, _ := payload[section].(map[string]interface{})
+				for id, value := range values {
+					encoded, _ := json.Marshal(value)
+					got[section+"."+id] = string(encoded)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("payload = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := map[string][]string{
+		"":               nil,
+		" , ,":           nil,
+		"a":              {"a"},
+		" a , b,,c ":     {"a", "b", "c"},
+		"Won't Do, Done": {"Won't Do", "Done"},
+	}
+	for value, want := range tests {
+		if got := splitList(value); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitList(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestIsUnassignedValue(t *testing.T) {
+	tests := map[string]bool{
+		"unassigned":  true,
+		" Unassigned": true,
+		"NONE":        true,
+		"-1":          true,
+		"":            false,
+		"alice":       false,
+		"none@x.com":  false,
+	}
+	for value, want := range tests {
+		if got := isUnassignedValue(value); got != want {
+			t.Errorf("isUnassignedValue(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
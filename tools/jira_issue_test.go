@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func TestChildIssueType(t *testing.T) {
+	issueTypes := []*models.IssueTypeScheme{
+		{Name: "Epic", HierarchyLevel: 1},
+		{Name: "Story", HierarchyLevel: 0},
+		{Name: "Bug", HierarchyLevel: 0},
+		{Name: "Sub-task", Subtask: true, HierarchyLevel: -1},
+	}
+
+	if name, err := childIssueType(issueTypes, "KP", 0); err != nil || name != "Sub-task" {
+		t.Errorf("child of a standard issue = %q, %v; want Sub-task", name, err)
+	}
+	if _, err := childIssueType(issueTypes, "KP", 1); err == nil {
+		t.Error("expected an error when several standard types can be children of an epic")
+	}
+	if _, err := childIssueType(issueTypes, "KP", -1); err == nil {
+		t.Error("expected an error for a child of a subtask")
+	}
+
+	if name, err := childIssueType(issueTypes[:2], "KP", 1); err != nil || name != "Story" {
+		t.Errorf("child of an epic = %q, %v; want Story", name, err)
+	}
+	if _, err := childIssueType(issueTypes[:3], "KP", 0); err == nil {
+		t.Error("expected an error for a project without a subtask type")
+	}
+}
+
+func TestHierarchyName(t *testing.T) {
+	tests := map[string]*models.IssueTypeScheme{
+		"subtask":  {Subtask: true, HierarchyLevel: -1},
+		"standard": {HierarchyLevel: 0},
+		"epic":     {HierarchyLevel: 1},
+		"level 2":  {HierarchyLevel: 2},
+	}
+	for want, issueType := range tests {
+		if got := hierarchyName(issueType); got != want {
+			t.Errorf("hierarchyName(%+v) = %q, want %q", issueType, got, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Input types for typed tools
+type ExplainPolicyInput struct {
+	ProjectKey string `json:"project_key,omitempty"`
+	IssueType  string `json:"issue_type,omitempty"`
+	OutputFormatInput
+}
+
+// WriteRuleOutput is a rule of the write policy
+type WriteRuleOutput struct {
+	Project    string   `json:"project" jsonschema_description:"Project key or glob"`
+	Actions    []string `json:"actions"`
+	IssueTypes []string `json:"issue_types,omitempty" jsonschema_description:"When set, the actions only apply to issues of these types"`
+}
+
+// ExplainPolicyOutput is the result of jira_explain_policy
+type ExplainPolicyOutput struct {
+	ReadOnly           bool              `json:"read_only" jsonschema_description:"True when the server only offers tools that never change Jira"`
+	ConfirmDestructive bool              `json:"confirm_destructive" jsonschema_description:"True when delete, bulk and transition tools need a confirmation token"`
+	Configured         bool              `json:"configured" jsonschema_description:"False when no write policy is loaded, every project may then be changed"`
+	Source             string            `json:"source,omitempty" jsonschema_description:"File the write policy was loaded from"`
+	Actions            []string          `json:"actions" jsonschema_description:"Every action the policy controls"`
+	DefaultActions     []string          `json:"default_actions" jsonschema_description:"Actions allowed in projects no rule matches"`
+	Rules              []WriteRuleOutput `json:"rules" jsonschema_description:"Rules in order, the first one matching a project applies"`
+	ProjectKey         string            `json:"project_key,omitempty"`
+	IssueType          string            `json:"issue_type,omitempty"`
+	AllowedActions     []string          `json:"allowed_actions,omitempty" jsonschema_description:"Actions allowed for project_key and issue_type"`
+}
+
+func RegisterJiraPolicyTool(s *server.MCPServer) {
+	jiraExplainPolicyTool := mcp.NewTool("jira_explain_policy",
+		readOnlyTool(),
+		mcp.WithDescription("Explain which changes this server may make: read-only mode, confirmation mode and the write policy that limits create, edit, assign, transition, comment, worklog, link and delete to some projects and issue types. "+
+			"Pass project_key (and issue_type) to list what is allowed there before trying a change"),
+		mcp.WithString("project_key", mcp.Description("Project to check (e.g., KP)")),
+		mcp.WithString("issue_type", mcp.Description("Issue type to check within the project (e.g., Bug)")),
+		withOutputFormat[ExplainPolicyOutput](),
+	)
+	addTool(s, jiraExplainPolicyTool, mcp.NewTypedToolHandler(jiraExplainPolicyHandler))
+}
+
+func jiraExplainPolicyHandler(ctx context.Context, request mcp.CallToolRequest, input ExplainPolicyInput) (*mcp.CallToolResult, error) {
+	output := explainPolicy(input.ProjectKey, input.IssueType)
+	return formatResult(input.OutputFormat, output, formatPolicy(output))
+}
+
+func explainPolicy(projectKey, issueType string) ExplainPolicyOutput {
+	toolPolicy.RLock()
+	readOnly := toolPolicy.policy.ReadOnly
+	toolPolicy.RUnlock()
+
+	policy, source := currentWritePolicy()
+
+	output := ExplainPolicyOutput{
+		ReadOnly:           readOnly,
+		ConfirmDestructive: confirmDestructive.Load(),
+		Configured:         policy != nil,
+		Source:             source,
+		Actions:            writeActions,
+		DefaultActions:     []string{},
+		Rules:              []WriteRuleOutput{},
+		ProjectKey:         strings.ToUpper(projectKey),
+		IssueType:          issueType,
+	}
+
+	if policy == nil {
+		output.DefaultActions = writeActions
+	} else {
+		output.DefaultActions = append(output.DefaultActions, policy.Default...)
+		for _, rule := range policy.Projects {
+			output.Rules = append(output.Rules, WriteRuleOutput{Project: rule.Project, Actions: rule.Actions, IssueTypes: rule.IssueTypes})
+		}
+	}
+
+	if projectKey != "" {
+		output.AllowedActions = []string{}
+		if !readOnly {
+			for _, action := range writeActions {
+				if checkWritePolicy(action, projectKey, issueType) == nil {
+					output.AllowedActions = append(output.AllowedActions, action)
+				}
+			}
+		}
+	}
+
+	return output
+}
+
+func formatPolicy(output ExplainPolicyOutput) string {
+	var result strings.Builder
+
+	if output.ReadOnly {
+		result.WriteString("Read-only mode: no tool that changes Jira is available.\n")
+	}
+	if output.ConfirmDestructive {
+		result.WriteString("Confirmation mode: delete, bulk and transition tools return a preview and need its confirmation_token.\n")
+	}
+
+	if !output.Configured {
+		result.WriteString("No write policy is loaded: every project may be changed.\n")
+	} else {
+		result.WriteString(fmt.Sprintf("Write policy from %s (the first rule matching a project applies):\n", output.Source))
+		for _, rule := range output.Rules {
+			result.WriteString(fmt.Sprintf("- %s: %s", rule.Project, formatActions(rule.Actions)))
+			if len(rule.IssueTypes) > 0 {
+				result.WriteString(fmt.Sprintf(" (only %s issues)", strings.Join(rule.IssueTypes, ", ")))
+			}
+			result.WriteString("\n")
+		}
+		result.WriteString(fmt.Sprintf("- any other project: %s\n", formatActions(output.DefaultActions)))
+	}
+
+	if output.ProjectKey != "" {
+		target := output.ProjectKey
+		if output.IssueType != "" {
+			target = fmt.Sprintf("%s issues in %s", output.IssueType, output.ProjectKey)
+		}
+		result.WriteString(fmt.Sprintf("\nAllowed on %s: %s\n", target, formatActions(output.AllowedActions)))
+	}
+
+	return result.String()
+}
+
+func formatActions(actions []string) string {
+	if len(actions) == 0 {
+		return "nothing"
+	}
+	return strings.Join(actions, ", ")
+}
@@ -0,0 +1,521 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// Input types for project tools
+type ListProjectsInput struct {
+	Query       string `json:"query,omitempty"`
+	Category    string `json:"category,omitempty"`
+	ProjectType string `json:"project_type,omitempty"`
+	StartAt     int    `json:"start_at,omitempty"`
+	MaxResults  int    `json:"max_results,omitempty"`
+	OutputFormatInput
+}
+
+type GetProjectInput struct {
+	ProjectKey string `json:"project_key" validate:"required"`
+	OutputFormatInput
+}
+
+// ProjectSummaryOutput is a project in the results of jira_list_projects
+type ProjectSummaryOutput struct {
+	Key         string      `json:"key"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	ProjectType string      `json:"project_type,omitempty" jsonschema:"enum=software,enum=business,enum=service_desk"`
+	Style       string      `json:"style,omitempty" jsonschema_description:"company-managed or team-managed"`
+	Category    string      `json:"category,omitempty"`
+	Lead        *UserOutput `json:"lead,omitempty"`
+	URL         string      `json:"url,omitempty" jsonschema_description:"Browse URL of the project"`
+}
+
+// ListProjectsOutput is the result of jira_list_projects
+type ListProjectsOutput struct {
+	Projects []ProjectSummaryOutput `json:"projects"`
+	Total    int                    `json:"total" jsonschema_description:"Number of projects matching the filters across all pages"`
+	IsLast   bool                   `json:"is_last"`
+}
+
+// ComponentOutput is a project component
+type ComponentOutput struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Lead        *UserOutput `json:"lead,omitempty"`
+}
+
+// ProjectIssueTypeOutput is an issue type available in a project
+type ProjectIssueTypeOutput struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchy_level" jsonschema_description:"-1 for subtasks, 0 for standard issues, 1 for epics and higher for levels above"`
+}
+
+// IssueTypeSchemeOutput is the issue type scheme of a company-managed project
+type IssueTypeSchemeOutput struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	DefaultIssueTypeID string `json:"default_issue_type_id,omitempty"`
+	IsDefault          bool   `json:"is_default" jsonschema_description:"True when the project uses the site's default scheme"`
+}
+
+// VersionsSummaryOutput counts a project's versions by status
+type VersionsSummaryOutput struct {
+	Total       int            `json:"total"`
+	Unreleased  int            `json:"unreleased"`
+	Released    int            `json:"released"`
+	Archived    int            `json:"archived"`
+	NextRelease *VersionOutput `json:"next_release,omitempty" jsonschema_description:"Unreleased version with the earliest release date, or the first unreleased version when none has a date"`
+}
+
+// BoardOutput is an agile board
+type BoardOutput struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type" jsonschema_description:"scrum, kanban or simple"`
+}
+
+// ProjectOutput is the result of jira_get_project
+type ProjectOutput struct {
+	ProjectSummaryOutput
+	Description     string                   `json:"description,omitempty"`
+	DefaultAssignee string                   `json:"default_assignee,omitempty" jsonschema:"enum=PROJECT_LEAD,enum=UNASSIGNED"`
+	Archived        bool                     `json:"archived,omitempty"`
+	IssueTypes      []ProjectIssueTypeOutput `json:"issue_types,omitempty"`
+	IssueTypeScheme *IssueTypeSchemeOutput   `json:"issue_type_scheme,omitempty" jsonschema_description:"Absent for team-managed projects and when the user cannot read issue type schemes"`
+	Components      []ComponentOutput        `json:"components,omitempty"`
+	Versions        VersionsSummaryOutput    `json:"versions"`
+	Boards          []BoardOutput            `json:"boards,omitempty"`
+	Warnings        []string                 `json:"warnings,omitempty" jsonschema_description:"Details that could not be loaded, the rest of the project is still returned"`
+}
+
+const (
+	defaultProjectSearchResults = 50
+	maxProjectSearchResults     = 100
+)
+
+var projectTypes = []string{"software", "business", "service_desk"}
+
+func RegisterJiraProjectTool(s *server.MCPServer) {
+	jiraListProjectsTool := mcp.NewTool("jira_list_projects",
+		readOnlyTool(),
+		mcp.WithDescription("List the Jira projects visible to the user with their keys, names, types, categories and leads. Use it to find the project_key other tools need"),
+		mcp.WithString("query", mcp.Description("Only return projects whose key or name contains this text (case-insensitive)")),
+		mcp.WithString("category", mcp.Description("Only return projects in this project category, by name or ID")),
+		mcp.WithString("project_type", mcp.Enum(projectTypes...), mcp.Description("Only return projects of this type")),
+		mcp.WithNumber("start_at", mcp.Description("Index of the first project to return, for paging (default: 0)")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of projects to return (default: 50, max: 100)")),
+		withOutputFormat[ListProjectsOutput](),
+	)
+	addTool(s, jiraListProjectsTool, mcp.NewTypedToolHandler(jiraListProjectsHandler))
+
+	jiraGetProjectTool := mcp.NewTool("jira_get_project",
+		readOnlyTool(),
+		mcp.WithDescription("Get a Jira project's metadata: lead, default assignee, issue types and issue type scheme, components, a summary of its versions and its agile boards"),
+		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project key or ID (e.g., KP, PROJ)")),
+		withOutputFormat[ProjectOutput](),
+	)
+	addTool(s, jiraGetProjectTool, mcp.NewTypedToolHandler(jiraGetProjectHandler))
+}
+
+func jiraListProjectsHandler(ctx context.Context, request mcp.CallToolRequest, input ListProjectsInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultProjectSearchResults
+	}
+	if maxResults > maxProjectSearchResults {
+		maxResults = maxProjectSearchResults
+	}
+
+	options := &models.ProjectSearchOptionsScheme{
+		Query:   input.Query,
+		OrderBy: "key",
+		Expand:  []string{"lead"},
+	}
+
+	if input.ProjectType != "" {
+		options.TypeKeys = []string{input.ProjectType}
+	}
+
+	if input.Category != "" {
+		categoryID, err := resolveProjectCategory(ctx, client, input.Category)
+		if err != nil {
+			return nil, err
+		}
+		options.CategoryID = categoryID
+	}
+
+	page, response, err := searchProjects(ctx, client, options, input.StartAt, maxResults)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to list projects: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to list projects: %v", err)
+	}
+
+	output := ListProjectsOutput{Projects: []ProjectSummaryOutput{}, Total: page.Total, IsLast: page.IsLast}
+
+	if len(page.Values) == 0 {
+		return formatResult(input.OutputFormat, output, "No projects found.")
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Found %d of %d projects:\n\n", len(page.Values), page.Total))
+
+	for _, project := range page.Values {
+		summary := newProjectSummaryOutput(project)
+		output.Projects = append(output.Projects, summary)
+
+		result.WriteString(fmt.Sprintf("- %s: %s", project.Key, project.Name))
+
+		var details []string
+		if summary.ProjectType != "" {
+			details = append(details, summary.ProjectType)
+		}
+		if summary.Category != "" {
+			details = append(details, "category: "+summary.Category)
+		}
+		if summary.Lead != nil {
+			details = append(details, "lead: "+summary.Lead.DisplayName)
+		}
+		if len(details) > 0 {
+			result.WriteString(fmt.Sprintf(" (%s)", strings.Join(details, ", ")))
+		}
+		result.WriteString("\n")
+	}
+
+	if !page.IsLast {
+		result.WriteString(fmt.Sprintf("\nMore projects available, use start_at: %d to get the next page\n", input.StartAt+len(page.Values)))
+	}
+
+	return formatResult(input.OutputFormat, output, result.String())
+}
+
+func jiraGetProjectHandler(ctx context.Context, request mcp.CallToolRequest, input GetProjectInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	project, response, err := client.Project.Get(ctx, input.ProjectKey, []string{"description", "lead", "issueTypes"})
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get project: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get project: %v", err)
+	}
+
+	output := ProjectOutput{
+		ProjectSummaryOutput: newProjectSummaryOutput(project),
+		Description:          project.Description,
+		DefaultAssignee:      project.AssigneeType,
+		Archived:             project.Archived,
+		Versions:             summarizeVersions(project.Versions),
+	}
+
+	for _, issueType := range project.IssueTypes {
+		output.IssueTypes = append(output.IssueTypes, ProjectIssueTypeOutput{
+			ID:             issueType.ID,
+			Name:           issueType.Name,
+			Subtask:        issueType.Subtask,
+			HierarchyLevel: issueType.HierarchyLevel,
+		})
+	}
+
+	for _, component := range project.Components {
+		output.Components = append(output.Components, ComponentOutput{
+			ID:          component.ID,
+			Name:        component.Name,
+			Description: component.Description,
+			Lead:        newUserOutput(component.Lead),
+		})
+	}
+
+	// Team-managed projects have no issue type scheme, reading schemes needs admin rights,
+	// and Server and Data Center do not expose them
+	if !project.Simplified && !isServer(ctx) {
+		scheme, err := getProjectIssueTypeScheme(ctx, client, project.ID)
+		if err != nil {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("issue type scheme: %v", err))
+		}
+		output.IssueTypeScheme = scheme
+	}
+
+	boards, response, err := services.AgileClientFor(ctx).Board.Gets(ctx, &models.GetBoardsOptions{
+		ProjectKeyOrID: project.Key,
+	}, 0, 50)
+	if err != nil {
+		if response != nil {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("boards: %s", response.Bytes.String()))
+		} else {
+			output.Warnings = append(output.Warnings, fmt.Sprintf("boards: %v", err))
+		}
+	} else {
+		for _, board := range boards.Values {
+			output.Boards = append(output.Boards, BoardOutput{ID: board.ID, Name: board.Name, Type: board.Type})
+		}
+	}
+
+	return formatResult(input.OutputFormat, output, formatProject(output))
+}
+
+// resolveProjectCategory accepts a category ID or a case-insensitive category name
+func resolveProjectCategory(ctx context.Context, client *jira.Client, category string) (int, error) {
+	if id, err := strconv.Atoi(category); err == nil {
+		return id, nil
+	}
+
+	categories, response, err := client.Project.Category.Gets(ctx)
+	if err != nil {
+		if response != nil {
+			return 0, fmt.Errorf("failed to list project categories: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return 0, fmt.Errorf("failed to list project categories: %v", err)
+	}
+
+	var names []string
+	for _, candidate := range categories {
+		if strings.EqualFold(candidate.Name, category) {
+			return strconv.Atoi(candidate.ID)
+		}
+		names = append(names, candidate.Name)
+	}
+
+	if len(names) == 0 {
+		return 0, fmt.Errorf("project category %q not found, this site has no project categories", category)
+	}
+	return 0, fmt.Errorf("project category %q not found, available categories: %s", category, strings.Join(names, ", "))
+}
+
+func getProjectIssueTypeScheme(ctx context.Context, client *jira.Client, projectID string) (*IssueTypeSchemeOutput, error) {
+	id, err := strconv.Atoi(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID %q", projectID)
+	}
+
+	page, response, err := client.Issue.Type.Scheme.Projects(ctx, []int{id}, 0, 1)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("%s", response.Bytes.String())
+		}
+		return nil, err
+	}
+
+	if len(page.Values) == 0 || page.Values[0].IssueTypeScheme == nil {
+		return nil, nil
+	}
+
+	scheme := page.Values[0].IssueTypeScheme
+	return &IssueTypeSchemeOutput{
+		ID:                 scheme.ID,
+		Name:               scheme.Name,
+		DefaultIssueTypeID: scheme.DefaultIssueTypeID,
+		IsDefault:          scheme.IsDefault,
+	}, nil
+}
+
+func newProjectSummaryOutput(project *models.ProjectScheme) ProjectSummaryOutput {
+	output := ProjectSummaryOutput{
+		Key:         project.Key,
+		ID:          project.ID,
+		Name:        project.Name,
+		ProjectType: project.ProjectTypeKey,
+		Lead:        newUserOutput(project.Lead),
+		URL:         issueBrowseURL(project.Self, project.Key),
+	}
+
+	switch project.Style {
+	case "classic":
+		output.Style = "company-managed"
+	case "next-gen":
+		output.Style = "team-managed"
+	}
+
+	if project.Category != nil {
+		output.Category = project.Category.Name
+	}
+
+	return output
+}
+
+// summarizeVersions counts versions by status and picks the next release
+func summarizeVersions(versions []*models.VersionScheme) VersionsSummaryOutput {
+	summary := VersionsSummaryOutput{Total: len(versions)}
+
+	var next *models.VersionScheme
+	for _, version := range versions {
+		switch {
+		case version.Archived:
+			summary.Archived++
+			continue
+		case version.Released:
+			summary.Released++
+			continue
+		}
+
+		summary.Unreleased++
+		// Release dates are YYYY-MM-DD, so they compare as strings
+		if next == nil || (version.ReleaseDate != "" && (next.ReleaseDate == "" || version.ReleaseDate < next.ReleaseDate)) {
+			next = version
+		}
+	}
+
+	if next != nil {
+		nextOutput := newVersionOutput(next)
+		summary.NextRelease = &nextOutput
+	}
+
+	return summary
+}
+
+func formatProject(project ProjectOutput) string {
+	var result strings.Builder
+
+	result.WriteString(fmt.Sprintf("Project: %s (%s)\n", project.Name, project.Key))
+	result.WriteString(fmt.Sprintf("ID: %s\n", project.ID))
+	if project.URL != "" {
+		result.WriteString(fmt.Sprintf("URL: %s\n", project.URL))
+	}
+	if project.ProjectType != "" {
+		result.WriteString(fmt.Sprintf("Type: %s\n", project.ProjectType))
+	}
+	if project.Style != "" {
+		result.WriteString(fmt.Sprintf("Style: %s\n", project.Style))
+	}
+	if project.Category != "" {
+		result.WriteString(fmt.Sprintf("Category: %s\n", project.Category))
+	}
+	if project.Archived {
+		result.WriteString("Archived: true\n")
+	}
+	if project.Lead != nil {
+		result.WriteString(fmt.Sprintf("Lead: %s\n", project.Lead.DisplayName))
+	}
+	switch project.DefaultAssignee {
+	case "PROJECT_LEAD":
+		result.WriteString("Default Assignee: Project lead\n")
+	case "UNASSIGNED":
+		result.WriteString("Default Assignee: Unassigned\n")
+	}
+	if project.Description != "" {
+		result.WriteString(fmt.Sprintf("Description: %s\n", project.Description))
+	}
+
+	if len(project.IssueTypes) > 0 {
+		result.WriteString("\nIssue Types:\n")
+		for _, issueType := range project.IssueTypes {
+			kind := "standard"
+			switch {
+			case issueType.Subtask:
+				kind = "subtask"
+			case issueType.HierarchyLevel > 0:
+				kind = fmt.Sprintf("level %d", issueType.HierarchyLevel)
+			}
+			result.WriteString(fmt.Sprintf("- %s (ID: %s, %s)\n", issueType.Name, issueType.ID, kind))
+		}
+	}
+
+	if project.IssueTypeScheme != nil {
+		result.WriteString(fmt.Sprintf("\nIssue Type Scheme: %s (ID: %s", project.IssueTypeScheme.Name, project.IssueTypeScheme.ID))
+		if project.IssueTypeScheme.IsDefault {
+			result.WriteString(", default scheme")
+		}
+		result.WriteString(")\n")
+	}
+
+	if len(project.Components) > 0 {
+		result.WriteString("\nComponents:\n")
+		for _, component := range project.Components {
+			result.WriteString(fmt.Sprintf("- %s (ID: %s)", component.Name, component.ID))
+			if component.Lead != nil {
+				result.WriteString(fmt.Sprintf(", lead: %s", component.Lead.DisplayName))
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	versions := project.Versions
+	result.WriteString(fmt.Sprintf("\nVersions: %d total, %d unreleased, %d released, %d archived\n", versions.Total, versions.Unreleased, versions.Released, versions.Archived))
+	if versions.NextRelease != nil {
+		result.WriteString(fmt.Sprintf("Next Release: %s (ID: %s", versions.NextRelease.Name, versions.NextRelease.ID))
+		if versions.NextRelease.ReleaseDate != "" {
+			result.WriteString(fmt.Sprintf(", due %s", versions.NextRelease.ReleaseDate))
+		}
+		result.WriteString(")\n")
+	}
+
+	if len(project.Boards) > 0 {
+		result.WriteString("\nBoards:\n")
+		for _, board := range project.Boards {
+			result.WriteString(fmt.Sprintf("- %s (ID: %d, %s)\n", board.Name, board.ID, board.Type))
+		}
+	}
+
+	if len(project.Warnings) > 0 {
+		result.WriteString("\nNot loaded:\n")
+		for _, warning := range project.Warnings {
+			result.WriteString(fmt.Sprintf("- %s\n", warning))
+		}
+	}
+
+	return result.String()
+}
+
+// searchProjects pages through the projects matching options. Server and Data Center have no
+// /project/search, so there the full project list is filtered and paged the same way.
+func searchProjects(ctx context.Context, client *jira.Client, options *models.ProjectSearchOptionsScheme, startAt, maxResults int) (*models.ProjectSearchScheme, *models.ResponseScheme, error) {
+	if !isServer(ctx) {
+		return client.Project.Search(ctx, options, startAt, maxResults)
+	}
+
+	req, err := client.NewRequest(ctx, http.MethodGet, "rest/api/2/project?expand=lead", "", nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var projects []*models.ProjectScheme
+	response, err := client.Call(req, &projects)
+	if err != nil {
+		return nil, response, err
+	}
+
+	var matching []*models.ProjectScheme
+	for _, project := range projects {
+		if options.Query != "" && !strings.Contains(strings.ToLower(project.Key), strings.ToLower(options.Query)) &&
+			!strings.Contains(strings.ToLower(project.Name), strings.ToLower(options.Query)) {
+			continue
+		}
+		if len(options.TypeKeys) > 0 && !containsString(options.TypeKeys, project.ProjectTypeKey) {
+			continue
+		}
+		if options.CategoryID != 0 && (project.Category == nil || project.Category.ID != strconv.Itoa(options.CategoryID)) {
+			continue
+		}
+		matching = append(matching, project)
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].Key < matching[j].Key })
+
+	page := &models.ProjectSearchScheme{StartAt: startAt, MaxResults: maxResults, Total: len(matching)}
+	if startAt < len(matching) {
+		end := startAt + maxResults
+		if end > len(matching) {
+			end = len(matching)
+		}
+		page.Values = matching[startAt:end]
+	}
+	page.IsLast = startAt+len(page.Values) >= len(matching)
+	return page, response, nil
+}
@@ -0,0 +1,53 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+func TestSummarizeVersions(t *testing.T) {
+	summary := summarizeVersions([]*models.VersionScheme{
+		{ID: "1", Name: "1.0", Released: true, ReleaseDate: "2024-01-10"},
+		{ID: "2", Name: "1.1", Archived: true},
+		{ID: "3", Name: "Backlog"},
+		{ID: "4", Name: "2.1", ReleaseDate: "2024-09-01"},
+		{ID: "5", Name: "2.0", ReleaseDate: "2024-06-01"},
+	})
+
+	if summary.Total != 5 || summary.Released != 1 || summary.Archived != 1 || summary.Unreleased != 3 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if summary.NextRelease == nil || summary.NextRelease.Name != "2.0" {
+		t.Errorf("NextRelease = %+v, want 2.0", summary.NextRelease)
+	}
+
+	summary = summarizeVersions([]*models.VersionScheme{{ID: "1", Name: "Someday"}, {ID: "2", Name: "Later"}})
+	if summary.NextRelease == nil || summary.NextRelease.Name != "Someday" {
+		t.Errorf("NextRelease = %+v, want the first undated version", summary.NextRelease)
+	}
+
+	if summary := summarizeVersions(nil); summary.NextRelease != nil || summary.Total != 0 {
+		t.Errorf("summary of no versions = %+v", summary)
+	}
+}
+
+func TestNewProjectSummaryOutput(t *testing.T) {
+	output := newProjectSummaryOutput(&models.ProjectScheme{
+		ID:             "10000",
+		Key:            "KP",
+		Name:           "Key Project",
+		Self:           "https://example.atlassian.net/rest/api/3/project/10000",
+		ProjectTypeKey: "software",
+		Style:          "next-gen",
+		Category:       &models.ProjectCategoryScheme{ID: "10001", Name: "Engineering"},
+		Lead:           &models.UserScheme{AccountID: "abc", DisplayName: "Jane"},
+	})
+
+	if output.URL != "https://example.atlassian.net/browse/KP" {
+		t.Errorf("URL = %q", output.URL)
+	}
+	if output.Style != "team-managed" || output.Category != "Engineering" || output.Lead == nil {
+		t.Errorf("output = %+v", output)
+	}
+}
@@ -14,6 +14,7 @@ import (
 // Input types for typed tools
 type GetRelatedIssuesInput struct {
 	IssueKey string `json:"issue_key" validate:"required"`
+	OutputFormatInput
 }
 
 type LinkIssuesInput struct {
@@ -21,30 +22,48 @@ type LinkIssuesInput struct {
 	OutwardIssue string `json:"outward_issue" validate:"required"`
 	LinkType     string `json:"link_type" validate:"required"`
 	Comment      string `json:"comment,omitempty"`
+	OutputFormatInput
+}
+
+// GetRelatedIssuesOutput is the result of jira_get_related_issues
+type GetRelatedIssuesOutput struct {
+	IssueKey string            `json:"issue_key"`
+	Links    []IssueLinkOutput `json:"links"`
+}
+
+// LinkIssuesOutput is the result of jira_link_issues
+type LinkIssuesOutput struct {
+	InwardIssue  string `json:"inward_issue"`
+	OutwardIssue string `json:"outward_issue"`
+	LinkType     string `json:"link_type"`
 }
 
 func RegisterJiraRelationshipTool(s *server.MCPServer) {
 	jiraRelationshipTool := mcp.NewTool("jira_get_related_issues",
+		readOnlyTool(),
 		mcp.WithDescription("Retrieve issues that have a relationship with a given issue, such as blocks, is blocked by, relates to, etc."),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
+		withOutputFormat[GetRelatedIssuesOutput](),
 	)
-	s.AddTool(jiraRelationshipTool, mcp.NewTypedToolHandler(jiraRelationshipHandler))
+	addTool(s, jiraRelationshipTool, mcp.NewTypedToolHandler(jiraRelationshipHandler))
 
 	jiraLinkTool := mcp.NewTool("jira_link_issues",
+		additiveTool(),
 		mcp.WithDescription("Create a link between two Jira issues, defining their relationship (e.g., blocks, duplicates, relates to)"),
 		mcp.WithString("inward_issue", mcp.Required(), mcp.Description("The key of the inward issue (e.g., KP-1, PROJ-123)")),
 		mcp.WithString("outward_issue", mcp.Required(), mcp.Description("The key of the outward issue (e.g., KP-2, PROJ-123)")),
 		mcp.WithString("link_type", mcp.Required(), mcp.Description("The type of link between issues (e.g., Duplicate, Blocks, Relates)")),
 		mcp.WithString("comment", mcp.Description("Optional comment to add when creating the link")),
+		withOutputFormat[LinkIssuesOutput](),
 	)
-	s.AddTool(jiraLinkTool, mcp.NewTypedToolHandler(jiraLinkHandler))
+	addTool(s, jiraLinkTool, mcp.NewTypedToolHandler(jiraLinkHandler))
 }
 
 func jiraRelationshipHandler(ctx context.Context, request mcp.CallToolRequest, input GetRelatedIssuesInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 	
 	// Get the issue with the 'issuelinks' field
-	issue, response, err := client.Issue.Get(ctx, input.IssueKey, nil, []string{"issuelinks"})
+	issue, response, err := getIssue(ctx, client, input.IssueKey, nil, []string{"issuelinks"})
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -52,14 +71,20 @@ func jiraRelationshipHandler(ctx context.Context, request mcp.CallToolRequest, i
 		return nil, fmt.Errorf("failed to get issue: %v", err)
 	}
 
+	output := GetRelatedIssuesOutput{IssueKey: input.IssueKey, Links: []IssueLinkOutput{}}
+
 	if issue.Fields.IssueLinks == nil || len(issue.Fields.IssueLinks) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("Issue %s has no linked issues.", input.IssueKey)), nil
+		return formatResult(input.OutputFormat, output, fmt.Sprintf("Issue %s has no linked issues.", input.IssueKey))
 	}
 
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Related issues for %s:\n\n", input.IssueKey))
 
 	for _, link := range issue.Fields.IssueLinks {
+		if linkOutput, ok := newIssueLinkOutput(link); ok {
+			output.Links = append(output.Links, linkOutput)
+		}
+
 		// Determine the relationship type and related issue
 		var relatedIssue string
 		var relationshipType string
@@ -100,12 +125,19 @@ func jiraRelationshipHandler(ctx context.Context, request mcp.CallToolRequest, i
 		sb.WriteString("\n")
 	}
 
-	return mcp.NewToolResultText(sb.String()), nil
+	return formatResult(input.OutputFormat, output, sb.String())
 } 
 
 
 func jiraLinkHandler(ctx context.Context, request mcp.CallToolRequest, input LinkIssuesInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
+
+	// A link shows up on both issues
+	for _, issueKey := range []string{input.InwardIssue, input.OutwardIssue} {
+		if err := checkIssuePolicy(ctx, client, actionLink, issueKey); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create the link payload
 	payload := &models.LinkPayloadSchemeV3{
@@ -131,7 +163,8 @@ func jiraLinkHandler(ctx context.Context, request mcp.CallToolRequest, input Lin
 	}
 
 	// Create the link
-	response, err := client.Issue.Link.Create(ctx, payload)
+	response, err := createIssueLink(ctx, client, payload)
+	auditRequest(ctx, response, input.InwardIssue, input.OutwardIssue)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to link issues: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -139,5 +172,8 @@ func jiraLinkHandler(ctx context.Context, request mcp.CallToolRequest, input Lin
 		return nil, fmt.Errorf("failed to link issues: %v", err)
 	}
 
-	return mcp.NewToolResultText(fmt.Sprintf("Successfully linked issues %s and %s with link type \"%s\"", input.InwardIssue, input.OutwardIssue, input.LinkType)), nil
+	auditCreated(ctx, AuditResource{Type: auditResourceLink, Issue: input.InwardIssue, Target: input.OutwardIssue, LinkType: input.LinkType})
+
+	output := LinkIssuesOutput{InwardIssue: input.InwardIssue, OutwardIssue: input.OutwardIssue, LinkType: input.LinkType}
+	return formatResult(input.OutputFormat, output, fmt.Sprintf("Successfully linked issues %s and %s with link type \"%s\"", input.InwardIssue, input.OutwardIssue, input.LinkType))
 } 
\ No newline at end of file
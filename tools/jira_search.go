@@ -19,13 +19,59 @@ import (
 
 // Input types for typed tools
 type SearchIssueInput struct {
-	JQL    string `json:"jql" validate:"required"`
-	Fields string `json:"fields,omitempty"`
-	Expand string `json:"expand,omitempty"`
+	JQL           string `json:"jql" validate:"required"`
+	Fields        string `json:"fields,omitempty"`
+	Expand        string `json:"expand,omitempty"`
+	MaxResults    int    `json:"max_results,omitempty"`
+	NextPageToken string `json:"next_page_token,omitempty"`
+	FetchAll      bool   `json:"fetch_all,omitempty"`
+	FetchAllLimit int    `json:"fetch_all_limit,omitempty"`
+	CustomFields  bool   `json:"custom_fields,omitempty"`
+	OutputFormatInput
 }
 
-// searchIssuesJQL performs JQL search using the new /rest/api/3/search/jql endpoint
-func searchIssuesJQL(ctx context.Context, client *jira.Client, jql string, fields []string, expand []string, startAt, maxResults int) (*models.IssueSearchScheme, error) {
+// SearchIssueOutput is the result of jira_search_issue
+type SearchIssueOutput struct {
+	Issues        []IssueOutput `json:"issues"`
+	Count         int           `json:"count"`
+	IsLast        bool          `json:"is_last"`
+	NextPageToken string        `json:"next_page_token,omitempty" jsonschema_description:"Pass as next_page_token to fetch the following page"`
+}
+
+const (
+	defaultSearchPageSize = 30
+	maxSearchPageSize     = 100
+	defaultFetchAllLimit  = 500
+	maxFetchAllLimit      = 5000
+)
+
+// defaultSearchFields are the standard fields the issue formatter renders, custom fields
+// are only fetched on request as some sites have hundreds of them
+var defaultSearchFields = []string{
+	"summary", "description", "status", "issuetype", "project", "priority", "resolution", "resolutiondate",
+	"assignee", "reporter", "creator", "created", "updated", "labels", "components", "versions", "fixVersions",
+	"parent", "subtasks", "issuelinks", "duedate", "security", "comment", "attachment", "worklog",
+}
+
+// jqlSearchResult is the response of /rest/api/3/search/jql.
+// The endpoint is token based, so it has no startAt/total like models.IssueSearchScheme.
+// RawIssues keeps each issue's JSON, in the same order as Issues, for the custom fields
+// that models.IssueScheme drops.
+type jqlSearchResult struct {
+	Issues        []*models.IssueScheme `json:"-"`
+	RawIssues     []json.RawMessage     `json:"issues,omitempty"`
+	Names         map[string]string     `json:"names,omitempty"`
+	NextPageToken string                `json:"nextPageToken,omitempty"`
+	IsLast        bool                  `json:"isLast,omitempty"`
+}
+
+// searchIssuesJQL performs JQL search using the new /rest/api/3/search/jql endpoint.
+// Pass the nextPageToken of a previous result to fetch the following page.
+func searchIssuesJQL(ctx context.Context, client *jira.Client, jql string, fields []string, expand []string, nextPageToken string, maxResults int) (*jqlSearchResult, error) {
+	if isServer(ctx) {
+		return searchIssuesServer(ctx, client, jql, fields, expand, nextPageToken, maxResults)
+	}
+
 	// Prepare query parameters
 	params := url.Values{}
 	params.Set("jql", jql)
@@ -38,94 +84,246 @@ func searchIssuesJQL(ctx context.Context, client *jira.Client, jql string, field
 		params.Set("expand", strings.Join(expand, ","))
 	}
 
-	if startAt > 0 {
-		params.Set("startAt", strconv.Itoa(startAt))
+	if nextPageToken != "" {
+		params.Set("nextPageToken", nextPageToken)
 	}
 
 	if maxResults > 0 {
 		params.Set("maxResults", strconv.Itoa(maxResults))
 	}
 
-	// Build the URL
-	endpoint := fmt.Sprintf("%s/rest/api/3/search/jql?%s", client.Site.String(), params.Encode())
-
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	// The request goes through the client so that it shares its transport and authentication
+	req, err := client.NewRequest(ctx, http.MethodGet, "rest/api/3/search/jql?"+params.Encode(), "", nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// Add authentication headers - the client already has basic auth configured
-	if client.Auth != nil && client.Auth.HasBasicAuth() {
-		username, password := client.Auth.GetBasicAuth()
-		req.SetBasicAuth(username, password)
+	var searchResult jqlSearchResult
+	response, err := client.Call(req, &searchResult)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to search issues: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to search issues: %w", err)
 	}
 
-	req.Header.Set("Accept", "application/json")
+	for _, raw := range searchResult.RawIssues {
+		issue := new(models.IssueScheme)
+		if err := json.Unmarshal(raw, issue); err != nil {
+			return nil, fmt.Errorf("failed to decode issue: %w", err)
+		}
+		searchResult.Issues = append(searchResult.Issues, issue)
+	}
+
+	// Older responses omit isLast, so a missing token is the only reliable end marker
+	if searchResult.NextPageToken == "" {
+		searchResult.IsLast = true
+	}
+
+	return &searchResult, nil
+}
+
+// serverSearchResult is the response of /rest/api/2/search, which Server and Data Center offer instead of /search/jql
+type serverSearchResult struct {
+	StartAt   int               `json:"startAt"`
+	Total     int               `json:"total"`
+	RawIssues []json.RawMessage `json:"issues,omitempty"`
+	Names     map[string]string `json:"names,omitempty"`
+}
+
+// searchIssuesServer searches with the offset based /rest/api/2/search. The page token handed to
+// callers is the startAt of the following page, so paging works the same as on Cloud.
+func searchIssuesServer(ctx context.Context, client *jira.Client, jql string, fields []string, expand []string, nextPageToken string, maxResults int) (*jqlSearchResult, error) {
+	startAt := 0
+	if nextPageToken != "" {
+		var err error
+		if startAt, err = strconv.Atoi(nextPageToken); err != nil || startAt < 0 {
+			return nil, fmt.Errorf("invalid next_page_token %q: Jira Server page tokens are the offset of the next page", nextPageToken)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("jql", jql)
+	params.Set("startAt", strconv.Itoa(startAt))
+	if len(fields) > 0 {
+		params.Set("fields", strings.Join(fields, ","))
+	}
+	if len(expand) > 0 {
+		params.Set("expand", strings.Join(expand, ","))
+	}
+	if maxResults > 0 {
+		params.Set("maxResults", strconv.Itoa(maxResults))
+	}
 
-	// Perform the request
-	resp, err := client.HTTP.Do(req)
+	req, err := client.NewRequest(ctx, http.MethodGet, "rest/api/2/search?"+params.Encode(), "", nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, resp.Status)
+	var page serverSearchResult
+	response, err := client.Call(req, &page)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to search issues: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to search issues: %w", err)
 	}
 
-	// Parse the response
-	var searchResult models.IssueSearchScheme
-	if err := json.NewDecoder(resp.Body).Decode(&searchResult); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	searchResult := &jqlSearchResult{RawIssues: page.RawIssues, Names: page.Names}
+	for _, raw := range page.RawIssues {
+		issue, err := issueFromServerJSON(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode issue: %w", err)
+		}
+		searchResult.Issues = append(searchResult.Issues, issue)
 	}
 
-	return &searchResult, nil
+	if next := page.StartAt + len(page.RawIssues); len(page.RawIssues) > 0 && next < page.Total {
+		searchResult.NextPageToken = strconv.Itoa(next)
+	} else {
+		searchResult.IsLast = true
+	}
+
+	return searchResult, nil
+}
+
+// searchAllIssuesJQL walks every page of a JQL search until the last page or until limit issues are collected.
+// The walk starts at nextPageToken, or at the first page when it is empty.
+// The returned result keeps the token of the next unread page when the limit cut the walk short.
+func searchAllIssuesJQL(ctx context.Context, client *jira.Client, jql string, fields []string, expand []string, nextPageToken string, limit int) (*jqlSearchResult, error) {
+	all := &jqlSearchResult{Names: map[string]string{}}
+
+	for {
+		pageSize := maxSearchPageSize
+		if remaining := limit - len(all.Issues); remaining < pageSize {
+			pageSize = remaining
+		}
+
+		page, err := searchIssuesJQL(ctx, client, jql, fields, expand, nextPageToken, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		all.Issues = append(all.Issues, page.Issues...)
+		all.RawIssues = append(all.RawIssues, page.RawIssues...)
+		for id, name := range page.Names {
+			all.Names[id] = name
+		}
+		all.NextPageToken = page.NextPageToken
+		all.IsLast = page.IsLast
+
+		if page.IsLast || len(page.Issues) == 0 || len(all.Issues) >= limit {
+			return all, nil
+		}
+		nextPageToken = page.NextPageToken
+	}
 }
 
 func RegisterJiraSearchTool(s *server.MCPServer) {
 	jiraSearchTool := mcp.NewTool("jira_search_issue",
-		mcp.WithDescription("Search for Jira issues using JQL (Jira Query Language). Returns key details like summary, status, assignee, and priority for matching issues"),
+		readOnlyTool(),
+		mcp.WithDescription("Search for Jira issues using JQL (Jira Query Language). Returns key details like summary, status, assignee, and priority for matching issues. Results are paginated: pass the returned next_page_token to get the next page, or set fetch_all to collect every page"),
 		mcp.WithString("jql", mcp.Required(), mcp.Description("JQL query string (e.g., 'project = SHTP AND status = \"In Progress\"')")),
-		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to retrieve (e.g., 'summary,status,assignee'). If not specified, all fields are returned.")),
+		mcp.WithString("fields", mcp.Description("Comma-separated list of fields to retrieve (e.g., 'summary,status,assignee'). If not specified, the standard issue fields are returned.")),
 		mcp.WithString("expand", mcp.Description("Comma-separated list of fields to expand for additional details (e.g., 'transitions,changelog,subtasks,description').")),
+		mcp.WithNumber("max_results", mcp.Description("Number of issues per page (default: 30, max: 100)")),
+		mcp.WithString("next_page_token", mcp.Description("Token from a previous search result to fetch the next page")),
+		mcp.WithBoolean("fetch_all", mcp.Description("If true, follow next page tokens and return all matching issues up to fetch_all_limit, starting from next_page_token when it is set")),
+		mcp.WithNumber("fetch_all_limit", mcp.Description("Maximum number of issues returned when fetch_all is true (default: 500, max: 5000)")),
+		mcp.WithBoolean("custom_fields", mcp.Description("If true and fields is not specified, also return custom fields (Story Points, Sprint, Team, ...) by display name")),
+		withOutputFormat[SearchIssueOutput](),
 	)
-	s.AddTool(jiraSearchTool, mcp.NewTypedToolHandler(jiraSearchHandler))
+	addTool(s, jiraSearchTool, mcp.NewTypedToolHandler(jiraSearchHandler))
 }
 
 func jiraSearchHandler(ctx context.Context, request mcp.CallToolRequest, input SearchIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
-	// Parse fields parameter
-	var fields []string
+	// Parse fields parameter, the search endpoint only returns issue IDs unless fields are requested
+	fields := defaultSearchFields
+	if input.CustomFields {
+		fields = []string{"*all"}
+	}
 	if input.Fields != "" {
 		fields = strings.Split(strings.ReplaceAll(input.Fields, " ", ""), ",")
 	}
 
-	// Parse expand parameter
+	// Parse expand parameter, names and schema are always needed to label custom fields
 	var expand []string = []string{"transitions", "changelog", "subtasks", "description"}
 	if input.Expand != "" {
 		expand = strings.Split(strings.ReplaceAll(input.Expand, " ", ""), ",")
 	}
-	
-	searchResult, err := searchIssuesJQL(ctx, client, input.JQL, fields, expand, 0, 30)
+	expand = appendExpand(appendExpand(expand, "names"), "schema")
+
+	var searchResult *jqlSearchResult
+	var err error
+	if input.FetchAll {
+		limit := input.FetchAllLimit
+		if limit <= 0 {
+			limit = defaultFetchAllLimit
+		}
+		if limit > maxFetchAllLimit {
+			limit = maxFetchAllLimit
+		}
+		searchResult, err = searchAllIssuesJQL(ctx, client, input.JQL, fields, expand, input.NextPageToken, limit)
+	} else {
+		pageSize := input.MaxResults
+		if pageSize <= 0 {
+			pageSize = defaultSearchPageSize
+		}
+		if pageSize > maxSearchPageSize {
+			pageSize = maxSearchPageSize
+		}
+		searchResult, err = searchIssuesJQL(ctx, client, input.JQL, fields, expand, input.NextPageToken, pageSize)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to search issues: %v", err)
 	}
 
+	output := SearchIssueOutput{
+		Issues: []IssueOutput{},
+		IsLast: searchResult.IsLast,
+	}
+	if !searchResult.IsLast {
+		output.NextPageToken = searchResult.NextPageToken
+	}
+
 	if len(searchResult.Issues) == 0 {
-		return mcp.NewToolResultText("No issues found matching the search criteria."), nil
+		return formatResult(input.OutputFormat, output, "No issues found matching the search criteria.")
 	}
 
+	filter := util.CustomFieldFilterFromEnv()
+
 	var sb strings.Builder	
 	for index, issue := range searchResult.Issues {
+		customFields, _ := util.ExtractIssueCustomFields(searchResult.RawIssues[index], searchResult.Names, filter)
+		output.Issues = append(output.Issues, newIssueOutput(issue, customFields))
+
 		// Use the comprehensive formatter for each issue
-		formattedIssue := util.FormatJiraIssue(issue)
+		formattedIssue := util.FormatJiraIssueWithCustomFields(issue, customFields)
 		sb.WriteString(formattedIssue)
 		if index < len(searchResult.Issues) - 1 {
 			sb.WriteString("\n===\n")
 		}
 	}
 
-	return mcp.NewToolResultText(sb.String()), nil
+	sb.WriteString("\n---\n")
+	sb.WriteString(fmt.Sprintf("Returned Issues: %d\n", len(searchResult.Issues)))
+	sb.WriteString(fmt.Sprintf("Is Last Page: %t\n", searchResult.IsLast))
+	if !searchResult.IsLast && searchResult.NextPageToken != "" {
+		sb.WriteString(fmt.Sprintf("Next Page Token: %s\n", searchResult.NextPageToken))
+	}
+
+	output.Count = len(output.Issues)
+	return formatResult(input.OutputFormat, output, sb.String())
+}
+
+// appendExpand adds an expand option unless it is already requested
+func appendExpand(expand []string, option string) []string {
+	for _, existing := range expand {
+		if existing == option {
+			return expand
+		}
+	}
+	return append(expand, option)
 }
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// searchPages are the pages of a JQL search keyed by the token that requests them, the last one has no next token
+var searchPages = map[string]string{
+	"":       `{"issues": [{"id": "1", "key": "KP-1", "fields": {"summary": "one"}}, {"id": "2", "key": "KP-2", "fields": {"summary": "two"}}], "names": {"summary": "Summary"}, "nextPageToken": "page-2"}`,
+	"page-2": `{"issues": [{"id": "3", "key": "KP-3", "fields": {"summary": "three"}}, {"id": "4", "key": "KP-4", "fields": {"summary": "four"}}], "nextPageToken": "page-3"}`,
+	"page-3": `{"issues": [{"id": "5", "key": "KP-5", "fields": {"summary": "five"}}]}`,
+}
+
+func TestSearchAllIssuesJQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string
+		limit     int
+		wantKeys  []string
+		wantToken string
+		wantPages []string
+	}{
+		{"every page", "", 500, []string{"KP-1", "KP-2", "KP-3", "KP-4", "KP-5"}, "", []string{"", "page-2", "page-3"}},
+		{"from a page token", "page-2", 500, []string{"KP-3", "KP-4", "KP-5"}, "", []string{"page-2", "page-3"}},
+		{"cut short by the limit", "", 2, []string{"KP-1", "KP-2"}, "page-2", []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages []string
+			jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/rest/api/3/search/jql" {
+					http.NotFound(w, r)
+					return
+				}
+				token := r.URL.Query().Get("nextPageToken")
+				pages = append(pages, token)
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(searchPages[token]))
+			}))
+			defer jiraServer.Close()
+
+			ctx := stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+			result, err := searchAllIssuesJQL(ctx, services.JiraClientFor(ctx), "project = KP", []string{"summary"}, nil, tt.token, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var keys []string
+			for _, issue := range result.Issues {
+				keys = append(keys, issue.Key)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) || len(result.RawIssues) != len(keys) {
+				t.Errorf("keys = %v, want %v", keys, tt.wantKeys)
+			}
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("requested pages %q, want %q", pages, tt.wantPages)
+			}
+			if result.NextPageToken != tt.wantToken || result.IsLast != (tt.wantToken == "") {
+				t.Errorf("next page token = %q, last %v, want %q", result.NextPageToken, result.IsLast, tt.wantToken)
+			}
+			if result.Names["summary"] != "Summary" && tt.token == "" {
+				t.Errorf("names = %v", result.Names)
+			}
+		})
+	}
+}
+
+func TestSearchFieldsAndExpand(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      SearchIssueInput
+		wantFields string
+	}{
+		{"standard fields by default", SearchIssueInput{}, strings.Join(defaultSearchFields, ",")},
+		{"custom fields on request", SearchIssueInput{CustomFields: true}, "*all"},
+		{"explicit fields", SearchIssueInput{Fields: "summary, customfield_10016", CustomFields: true}, "summary,customfield_10016"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(searchPages["page-3"]))
+			}))
+			defer jiraServer.Close()
+
+			ctx := stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+			tt.input.JQL = "project = KP"
+			if _, err := jiraSearchHandler(ctx, mcp.CallToolRequest{}, tt.input); err != nil {
+				t.Fatal(err)
+			}
+			if fields := query.Get("fields"); fields != tt.wantFields {
+				t.Errorf("fields = %q, want %q", fields, tt.wantFields)
+			}
+			if expand := query.Get("expand"); !strings.HasSuffix(expand, ",names,schema") {
+				t.Errorf("expand = %q, want names and schema", expand)
+			}
+		})
+	}
+}
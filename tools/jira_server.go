@@ -0,0 +1,363 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Jira Server and Data Center only offer the v2 REST API. It differs from v3 in rich text, which is
+// wiki markup instead of Atlassian Document Format, and in users, which are referenced by username
+// instead of accountId. The helpers below convert explicitly at each endpoint that is affected.
+
+// isServer reports whether the site of the request runs Jira Server or Data Center
+func isServer(ctx context.Context) bool {
+	return services.ServerClientFor(ctx) != nil
+}
+
+// restAPI returns the path of a resource of the REST API version the site offers
+func restAPI(ctx context.Context, resource string) string {
+	if isServer(ctx) {
+		return "rest/api/2/" + resource
+	}
+	return "rest/api/3/" + resource
+}
+
+// userID returns the identifier that references user in requests: the account ID on Cloud,
+// the username on Server and Data Center
+func userID(user *models.UserScheme) string {
+	if user.AccountID != "" {
+		return user.AccountID
+	}
+	return user.Name
+}
+
+// userRef references the user with the given identifier in a field value
+func userRef(ctx context.Context, id string) map[string]interface{} {
+	if isServer(ctx) {
+		return map[string]interface{}{"name": id}
+	}
+	return map[string]interface{}{"accountId": id}
+}
+
+// toServerIssuePayload converts a v3 create or edit payload for the v2 API: the description,
+// environment and added comments become wiki markup, and assignee and reporter are sent by username.
+// The payload is changed in place and returned.
+func toServerIssuePayload(payload map[string]interface{}) map[string]interface{} {
+	if fields, ok := payload["fields"].(map[string]interface{}); ok {
+		for _, name := range []string{"description", "environment"} {
+			if text, ok := toWiki(fields[name]); ok {
+				fields[name] = text
+			}
+		}
+		for _, name := range []string{"assignee", "reporter"} {
+			if user, ok := toServerUser(fields[name]); ok {
+				fields[name] = user
+			}
+		}
+	}
+
+	if update, ok := payload["update"].(map[string]interface{}); ok {
+		operations, _ := update["comment"].([]interface{})
+		for _, operation := range operations {
+			add, _ := operation.(map[string]interface{})["add"].(map[string]interface{})
+			if text, ok := toWiki(add["body"]); ok {
+				add["body"] = text
+			}
+		}
+	}
+	return payload
+}
+
+// toWiki renders a document, given as a node or its JSON form, as wiki markup
+func toWiki(value interface{}) (string, bool) {
+	switch document := value.(type) {
+	case *models.CommentNodeScheme:
+		return util.RenderWiki(document), document != nil
+	case map[string]interface{}:
+		node := new(models.CommentNodeScheme)
+		if err := convertJSON(document, node); err != nil {
+			return "", false
+		}
+		return util.RenderWiki(node), true
+	}
+	return "", false
+}
+
+// toServerUser turns a {"accountId": id} or user reference into {"name": id}
+func toServerUser(value interface{}) (map[string]interface{}, bool) {
+	switch user := value.(type) {
+	case *models.UserScheme:
+		if user == nil {
+			return nil, false
+		}
+		return map[string]interface{}{"name": userID(user)}, true
+	case map[string]interface{}:
+		if id, ok := user["accountId"]; ok {
+			return map[string]interface{}{"name": id}, true
+		}
+	}
+	return nil, false
+}
+
+// toServerFieldValue converts a custom field value built for v3: documents of text areas become
+// wiki markup and users are referenced by username
+func toServerFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *models.CommentNodeScheme:
+		return util.RenderWiki(v)
+	case map[string]interface{}:
+		if user, ok := toServerUser(v); ok {
+			return user
+		}
+	case []interface{}:
+		converted := make([]interface{}, len(v))
+		for i, item := range v {
+			converted[i] = toServerFieldValue(item)
+		}
+		return converted
+	}
+	return value
+}
+
+// convertJSON copies src into dst through their JSON form, for types that share their JSON shape
+func convertJSON(src, dst interface{}) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// getIssue gets an issue from either REST API version. On Server and Data Center the v2 issue is
+// converted to the v3 model, with the description, comments and worklog comments as documents.
+func getIssue(ctx context.Context, client *jira.Client, issueKey string, fields, expand []string) (*models.IssueScheme, *models.ResponseScheme, error) {
+	server := services.ServerClientFor(ctx)
+	if server == nil {
+		return client.Issue.Get(ctx, issueKey, fields, expand)
+	}
+
+	issue, response, err := server.Issue.Get(ctx, issueKey, fields, expand)
+	if err != nil {
+		return nil, response, err
+	}
+	converted, err := issueFromServer(issue)
+	return converted, response, err
+}
+
+// issueFromServerJSON decodes an issue of the v2 API into the v3 model
+func issueFromServerJSON(raw []byte) (*models.IssueScheme, error) {
+	issue := new(models.IssueSchemeV2)
+	if err := json.Unmarshal(raw, issue); err != nil {
+		return nil, err
+	}
+	return issueFromServer(issue)
+}
+
+// issueFromServer converts a v2 issue to the v3 model. Only the rich text fields differ,
+// so everything else is copied as is.
+func issueFromServer(issue *models.IssueSchemeV2) (*models.IssueScheme, error) {
+	fields := issue.Fields
+	plain := *issue
+	if fields != nil {
+		// Copy the fields without their rich text, which does not decode into the v3 model
+		withoutRichText := *fields
+		withoutRichText.Description = ""
+		withoutRichText.Comment = nil
+		withoutRichText.Worklog = nil
+		plain.Fields = &withoutRichText
+	}
+
+	converted := new(models.IssueScheme)
+	if err := convertJSON(&plain, converted); err != nil {
+		return nil, fmt.Errorf("failed to convert issue %s: %w", issue.Key, err)
+	}
+	if fields == nil || converted.Fields == nil {
+		return converted, nil
+	}
+
+	if fields.Description != "" {
+		converted.Fields.Description = util.WikiToADF(fields.Description)
+	}
+	if fields.Comment != nil {
+		converted.Fields.Comment = commentPageFromServer(fields.Comment)
+	}
+	if fields.Worklog != nil {
+		converted.Fields.Worklog = &models.IssueWorklogADFPageScheme{
+			StartAt:    fields.Worklog.StartAt,
+			MaxResults: fields.Worklog.MaxResults,
+			Total:      fields.Worklog.Total,
+		}
+		for _, worklog := range fields.Worklog.Worklogs {
+			converted.Fields.Worklog.Worklogs = append(converted.Fields.Worklog.Worklogs, worklogFromServer(worklog))
+		}
+	}
+	return converted, nil
+}
+
+// commentPageFromServer converts a page of v2 comments to the v3 model
+func commentPageFromServer(page *models.IssueCommentPageSchemeV2) *models.IssueCommentPageScheme {
+	converted := &models.IssueCommentPageScheme{
+		StartAt:    page.StartAt,
+		MaxResults: page.MaxResults,
+		Total:      page.Total,
+	}
+	for _, comment := range page.Comments {
+		converted.Comments = append(converted.Comments, commentFromServer(comment))
+	}
+	return converted
+}
+
+// commentFromServer converts a v2 comment, whose body is wiki markup, to the v3 model
+func commentFromServer(comment *models.IssueCommentSchemeV2) *models.IssueCommentScheme {
+	return &models.IssueCommentScheme{
+		Self:         comment.Self,
+		ID:           comment.ID,
+		Author:       comment.Author,
+		RenderedBody: comment.RenderedBody,
+		Body:         util.WikiToADF(comment.Body),
+		JSDPublic:    comment.JSDPublic,
+		UpdateAuthor: comment.UpdateAuthor,
+		Created:      comment.Created,
+		Updated:      comment.Updated,
+		Visibility:   comment.Visibility,
+	}
+}
+
+// worklogFromServer converts a v2 worklog, whose comment is wiki markup, to the v3 model
+func worklogFromServer(worklog *models.IssueWorklogRichTextScheme) *models.IssueWorklogADFScheme {
+	converted := &models.IssueWorklogADFScheme{
+		Self:             worklog.Self,
+		Author:           worklog.Author,
+		UpdateAuthor:     worklog.UpdateAuthor,
+		Updated:          worklog.Updated,
+		Visibility:       worklog.Visibility,
+		Started:          worklog.Started,
+		TimeSpent:        worklog.TimeSpent,
+		TimeSpentSeconds: worklog.TimeSpentSeconds,
+		ID:               worklog.ID,
+		IssueID:          worklog.IssueID,
+	}
+	if worklog.Comment != "" {
+		converted.Comment = util.WikiToADF(worklog.Comment)
+	}
+	return converted
+}
+
+// deleteIssue deletes an issue through the REST API version the site offers
+func deleteIssue(ctx context.Context, client *jira.Client, issueKey string, deleteSubtasks bool) (*models.ResponseScheme, error) {
+	if server := services.ServerClientFor(ctx); server != nil {
+		return server.Issue.Delete(ctx, issueKey, deleteSubtasks)
+	}
+	return client.Issue.Delete(ctx, issueKey, deleteSubtasks)
+}
+
+// addComment adds a comment through the REST API version the site offers, the body is sent as wiki
+// markup on Server and Data Center
+func addComment(ctx context.Context, client *jira.Client, issueKey string, payload *models.CommentPayloadScheme) (*models.IssueCommentScheme, *models.ResponseScheme, error) {
+	server := services.ServerClientFor(ctx)
+	if server == nil {
+		return client.Issue.Comment.Add(ctx, issueKey, payload, nil)
+	}
+
+	comment, response, err := server.Issue.Comment.Add(ctx, issueKey, &models.CommentPayloadSchemeV2{Visibility: payload.Visibility, Body: util.RenderWiki(payload.Body)}, nil)
+	if err != nil {
+		return nil, response, err
+	}
+	return commentFromServer(comment), response, nil
+}
+
+// getComments lists the comments of an issue in the v3 model
+func getComments(ctx context.Context, client *jira.Client, issueKey string, startAt, maxResults int) (*models.IssueCommentPageScheme, *models.ResponseScheme, error) {
+	server := services.ServerClientFor(ctx)
+	if server == nil {
+		return client.Issue.Comment.Gets(ctx, issueKey, "", nil, startAt, maxResults)
+	}
+
+	page, response, err := server.Issue.Comment.Gets(ctx, issueKey, "", nil, startAt, maxResults)
+	if err != nil {
+		return nil, response, err
+	}
+	return commentPageFromServer(page), response, nil
+}
+
+// deleteComment deletes a comment through the REST API version the site offers
+func deleteComment(ctx context.Context, client *jira.Client, issueKey, commentID string) (*models.ResponseScheme, error) {
+	if server := services.ServerClientFor(ctx); server != nil {
+		return server.Issue.Comment.Delete(ctx, issueKey, commentID)
+	}
+	return client.Issue.Comment.Delete(ctx, issueKey, commentID)
+}
+
+// addWorklog logs work through the REST API version the site offers, the comment is sent as wiki
+// markup on Server and Data Center
+func addWorklog(ctx context.Context, client *jira.Client, issueKey string, payload *models.WorklogADFPayloadScheme, options *models.WorklogOptionsScheme) (*models.IssueWorklogADFScheme, *models.ResponseScheme, error) {
+	server := services.ServerClientFor(ctx)
+	if server == nil {
+		return client.Issue.Worklog.Add(ctx, issueKey, payload, options)
+	}
+
+	serverPayload := &models.WorklogRichTextPayloadScheme{
+		Visibility:       payload.Visibility,
+		Started:          payload.Started,
+		TimeSpent:        payload.TimeSpent,
+		TimeSpentSeconds: payload.TimeSpentSeconds,
+	}
+	if payload.Comment != nil {
+		serverPayload.Comment = &models.CommentPayloadSchemeV2{Body: util.RenderWiki(payload.Comment)}
+	}
+
+	worklog, response, err := server.Issue.Worklog.Add(ctx, issueKey, serverPayload, options)
+	if err != nil {
+		return nil, response, err
+	}
+	return worklogFromServer(worklog), response, nil
+}
+
+// deleteWorklog deletes a worklog through the REST API version the site offers
+func deleteWorklog(ctx context.Context, client *jira.Client, issueKey, worklogID string) (*models.ResponseScheme, error) {
+	if server := services.ServerClientFor(ctx); server != nil {
+		return server.Issue.Worklog.Delete(ctx, issueKey, worklogID, nil)
+	}
+	return client.Issue.Worklog.Delete(ctx, issueKey, worklogID, nil)
+}
+
+// createIssueLink links two issues through the REST API version the site offers, the link comment
+// is sent as wiki markup on Server and Data Center
+func createIssueLink(ctx context.Context, client *jira.Client, payload *models.LinkPayloadSchemeV3) (*models.ResponseScheme, error) {
+	server := services.ServerClientFor(ctx)
+	if server == nil {
+		return client.Issue.Link.Create(ctx, payload)
+	}
+
+	serverPayload := &models.LinkPayloadSchemeV2{
+		InwardIssue:  payload.InwardIssue,
+		OutwardIssue: payload.OutwardIssue,
+		Type:         payload.Type,
+	}
+	if payload.Comment != nil {
+		serverPayload.Comment = &models.CommentPayloadSchemeV2{Visibility: payload.Comment.Visibility, Body: util.RenderWiki(payload.Comment.Body)}
+	}
+	return server.Issue.Link.Create(ctx, serverPayload)
+}
+
+// getIssueLinks lists the links of an issue through the REST API version the site offers
+func getIssueLinks(ctx context.Context, client *jira.Client, issueKey string) (*models.IssueLinkPageScheme, *models.ResponseScheme, error) {
+	if server := services.ServerClientFor(ctx); server != nil {
+		return server.Issue.Link.Gets(ctx, issueKey)
+	}
+	return client.Issue.Link.Gets(ctx, issueKey)
+}
+
+// deleteIssueLink deletes a link through the REST API version the site offers
+func deleteIssueLink(ctx context.Context, client *jira.Client, linkID string) (*models.ResponseScheme, error) {
+	if server := services.ServerClientFor(ctx); server != nil {
+		return server.Issue.Link.Delete(ctx, linkID)
+	}
+	return client.Issue.Link.Delete(ctx, linkID)
+}
@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Responses recorded from the v2 REST API of Jira Data Center
+const (
+	serverSearchPage       = `{"expand":"names,schema","startAt":0,"maxResults":1,"total":2,"issues":[{"expand":"operations,editmeta,changelog,transitions,renderedFields","id":"10001","self":"https://jira.example.com/rest/api/2/issue/10001","key":"PROJ-1","fields":{"summary":"Login fails","description":"h2. Steps\r\n\r\n* Open the *login* page","status":{"self":"https://jira.example.com/rest/api/2/status/1","name":"Open","id":"1"},"assignee":{"self":"https://jira.example.com/rest/api/2/user?username=alice","name":"alice","key":"JIRAUSER10100","emailAddress":"alice@example.com","displayName":"Alice","active":true,"timeZone":"Europe/Berlin"},"comment":{"comments":[{"self":"https://jira.example.com/rest/api/2/issue/10001/comment/10200","id":"10200","author":{"name":"bob","key":"JIRAUSER10101","displayName":"Bob","active":true},"body":"Seen on _staging_","created":"2024-05-02T10:00:00.000+0200","updated":"2024-05-02T10:00:00.000+0200"}],"maxResults":1,"total":1,"startAt":0}}}],"names":{"summary":"Summary","description":"Description","status":"Status","assignee":"Assignee","comment":"Comment"}}`
+	serverSearchLastPage   = `{"expand":"names,schema","startAt":1,"maxResults":1,"total":2,"issues":[{"id":"10002","self":"https://jira.example.com/rest/api/2/issue/10002","key":"PROJ-2","fields":{"summary":"Logout fails","status":{"name":"Open","id":"1"}}}],"names":{"summary":"Summary"}}`
+	serverCreatedIssue     = `{"id":"10003","key":"PROJ-3","self":"https://jira.example.com/rest/api/2/issue/10003"}`
+	serverAssignableUsers  = `[{"self":"https://jira.example.com/rest/api/2/user?username=alice","key":"JIRAUSER10100","name":"alice","emailAddress":"alice@example.com","displayName":"Alice","active":true,"timeZone":"Europe/Berlin","locale":"en_US"}]`
+	serverIssueForStatus   = `{"expand":"renderedFields,names,schema","id":"10001","self":"https://jira.example.com/rest/api/2/issue/10001","key":"PROJ-1","fields":{"summary":"Login fails","issuetype":{"id":"10004","name":"Bug","subtask":false},"project":{"id":"10000","key":"PROJ","name":"Project"},"status":{"id":"1","name":"Open"}}}`
+	serverIssueTransitions = `{"expand":"transitions","transitions":[{"id":"21","name":"Resolve","to":{"self":"https://jira.example.com/rest/api/2/status/5","id":"5","name":"Resolved"}},{"id":"31","name":"Close","to":{"self":"https://jira.example.com/rest/api/2/status/6","id":"6","name":"Done"}}]}`
+)
+
+// serverRequest is a request the stub Jira Data Center received
+type serverRequest struct {
+	method string
+	path   string
+	query  url.Values
+	body   map[string]interface{}
+}
+
+// serverContext returns a context whose clients talk to a stub Jira Data Center that answers
+// "METHOD /path" with the given responses and 404 otherwise
+func serverContext(t *testing.T, responses map[string]string) (context.Context, *[]serverRequest) {
+	t.Helper()
+
+	var requests []serverRequest
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := serverRequest{method: r.Method, path: r.URL.Path, query: r.URL.Query()}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			if err := json.Unmarshal(data, &request.body); err != nil {
+				t.Errorf("%s %s: invalid body %s", r.Method, r.URL.Path, data)
+			}
+		}
+		requests = append(requests, request)
+
+		response, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if response == "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(jiraServer.Close)
+
+	return stubJiraContext(t, jiraServer.URL, services.DeploymentServer), &requests
+}
+
+// stubJiraContext returns a context whose clients act on the Jira at site
+func stubJiraContext(t *testing.T, site, deployment string) context.Context {
+	t.Helper()
+	clients, err := services.NewClients(&services.Credentials{
+		Site:       site,
+		Auth:       services.BearerAuth{Token: "pat"},
+		Deployment: deployment,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return services.ContextWithClients(context.Background(), clients)
+}
+
+// findServerRequest returns the first request with the given method and path
+func findServerRequest(t *testing.T, requests []serverRequest, method, path string) serverRequest {
+	t.Helper()
+	for _, request := range requests {
+		if request.method == method && request.path == path {
+			return request
+		}
+	}
+	t.Fatalf("no %s %s request, got %+v", method, path, requests)
+	return serverRequest{}
+}
+
+func TestServerSearch(t *testing.T) {
+	ctx, requests := serverContext(t, map[string]string{"GET /rest/api/2/search": serverSearchPage})
+	client := services.JiraClientFor(ctx)
+
+	page, err := searchIssuesJQL(ctx, client, "project = PROJ", []string{"*all"}, []string{"names"}, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := findServerRequest(t, *requests, http.MethodGet, "/rest/api/2/search").query
+	if query.Get("jql") != "project = PROJ" || query.Get("startAt") != "0" || query.Get("maxResults") != "1" {
+		t.Errorf("query = %v", query)
+	}
+
+	if len(page.Issues) != 1 || len(page.RawIssues) != 1 || page.IsLast || page.NextPageToken != "1" {
+		t.Fatalf("page = %d issues, last %v, token %q", len(page.Issues), page.IsLast, page.NextPageToken)
+	}
+	fields := page.Issues[0].Fields
+	if description := util.RenderADF(fields.Description); !strings.Contains(description, "Steps") || !strings.Contains(description, "login") {
+		t.Errorf("description = %q", description)
+	}
+	if fields.Assignee == nil || fields.Assignee.Name != "alice" || fields.Assignee.DisplayName != "Alice" {
+		t.Errorf("assignee = %+v", fields.Assignee)
+	}
+	if fields.Comment == nil || len(fields.Comment.Comments) != 1 || !strings.Contains(util.RenderADF(fields.Comment.Comments[0].Body), "staging") {
+		t.Errorf("comments = %+v", fields.Comment)
+	}
+
+	// The page token is the offset of the following page
+	ctx, requests = serverContext(t, map[string]string{"GET /rest/api/2/search": serverSearchLastPage})
+	page, err = searchIssuesJQL(ctx, services.JiraClientFor(ctx), "project = PROJ", nil, nil, "1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if startAt := findServerRequest(t, *requests, http.MethodGet, "/rest/api/2/search").query.Get("startAt"); startAt != "1" {
+		t.Errorf("startAt = %s", startAt)
+	}
+	if len(page.Issues) != 1 || page.Issues[0].Key != "PROJ-2" || !page.IsLast || page.NextPageToken != "" {
+		t.Errorf("last page = %+v", page)
+	}
+
+	if _, err := searchIssuesJQL(ctx, services.JiraClientFor(ctx), "project = PROJ", nil, nil, "eyJ0b2tlbiI6MX0", 1); err == nil {
+		t.Error("expected an error for a Cloud page token")
+	}
+}
+
+func TestServerCreateIssueWithDescription(t *testing.T) {
+	ctx, requests := serverContext(t, map[string]string{"POST /rest/api/2/issue": serverCreatedIssue})
+
+	input := CreateIssueInput{
+		ProjectKey:  "PROJ",
+		Summary:     "Login fails",
+		Description: "## Steps\n\n- Open the **login** page",
+		IssueType:   "Bug",
+	}
+	input.Assignee = "alice"
+	result, err := jiraCreateIssueHandler(ctx, mcp.CallToolRequest{}, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(toolResultText(result), "PROJ-3") {
+		t.Errorf("result = %s", toolResultText(result))
+	}
+
+	fields, _ := findServerRequest(t, *requests, http.MethodPost, "/rest/api/2/issue").body["fields"].(map[string]interface{})
+	description, ok := fields["description"].(string)
+	if !ok || !strings.Contains(description, "h2. Steps") || !strings.Contains(description, "*login*") {
+		t.Errorf("description = %#v, want wiki markup", fields["description"])
+	}
+	if assignee, _ := fields["assignee"].(map[string]interface{}); len(assignee) != 1 || assignee["name"] != "alice" {
+		t.Errorf("assignee = %#v", fields["assignee"])
+	}
+}
+
+func TestServerAssignIssue(t *testing.T) {
+	ctx, requests := serverContext(t, map[string]string{
+		"GET /rest/api/2/user/assignable/search": serverAssignableUsers,
+		"PUT /rest/api/2/issue/PROJ-1/assignee":  "",
+	})
+
+	if _, err := jiraAssignIssueHandler(ctx, mcp.CallToolRequest{}, AssignIssueInput{IssueKey: "PROJ-1", Assignee: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	query := findServerRequest(t, *requests, http.MethodGet, "/rest/api/2/user/assignable/search").query
+	if query.Get("username") != "alice" || query.Get("issueKey") != "PROJ-1" || query.Has("query") {
+		t.Errorf("assignable search query = %v", query)
+	}
+	body := findServerRequest(t, *requests, http.MethodPut, "/rest/api/2/issue/PROJ-1/assignee").body
+	if len(body) != 1 || body["name"] != "alice" {
+		t.Errorf("assign body = %v", body)
+	}
+}
+
+func TestServerTransitionIssue(t *testing.T) {
+	ctx, requests := serverContext(t, map[string]string{
+		"GET /rest/api/2/issue/PROJ-1":              serverIssueForStatus,
+		"GET /rest/api/2/issue/PROJ-1/transitions":  serverIssueTransitions,
+		"POST /rest/api/2/issue/PROJ-1/transitions": "",
+	})
+
+	input := TransitionIssueInput{IssueKey: "PROJ-1", TargetStatus: "Done", Comment: "Fixed in **1.2**", Resolution: "Fixed"}
+	if _, err := jiraTransitionIssueHandler(ctx, mcp.CallToolRequest{}, input); err != nil {
+		t.Fatal(err)
+	}
+
+	body := findServerRequest(t, *requests, http.MethodPost, "/rest/api/2/issue/PROJ-1/transitions").body
+	if transition, _ := body["transition"].(map[string]interface{}); transition["id"] != "31" {
+		t.Errorf("transition = %v", body["transition"])
+	}
+	if fields, _ := body["fields"].(map[string]interface{}); fields["resolution"] == nil {
+		t.Errorf("fields = %v", body["fields"])
+	}
+	update, _ := body["update"].(map[string]interface{})
+	comments, _ := update["comment"].([]interface{})
+	if len(comments) != 1 {
+		t.Fatalf("update = %v", body["update"])
+	}
+	add, _ := comments[0].(map[string]interface{})["add"].(map[string]interface{})
+	if comment, ok := add["body"].(string); !ok || !strings.Contains(comment, "*1.2*") {
+		t.Errorf("comment body = %#v, want wiki markup", add["body"])
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -16,15 +17,18 @@ import (
 type ListSprintsInput struct {
 	BoardID    string `json:"board_id,omitempty"`
 	ProjectKey string `json:"project_key,omitempty"`
+	OutputFormatInput
 }
 
 type GetSprintInput struct {
 	SprintID string `json:"sprint_id" validate:"required"`
+	OutputFormatInput
 }
 
 type GetActiveSprintInput struct {
 	BoardID    string `json:"board_id,omitempty"`
 	ProjectKey string `json:"project_key,omitempty"`
+	OutputFormatInput
 }
 
 type SearchSprintByNameInput struct {
@@ -32,37 +36,68 @@ type SearchSprintByNameInput struct {
 	BoardID    string `json:"board_id,omitempty"`
 	ProjectKey string `json:"project_key,omitempty"`
 	ExactMatch bool   `json:"exact_match,omitempty"`
+	OutputFormatInput
+}
+
+// SprintOutput is a sprint in the results of the sprint tools
+type SprintOutput struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	State        string `json:"state"`
+	StartDate    string `json:"start_date,omitempty"`
+	EndDate      string `json:"end_date,omitempty"`
+	CompleteDate string `json:"complete_date,omitempty"`
+	BoardID      int    `json:"board_id,omitempty" jsonschema_description:"Board the sprint was found on, or its origin board for jira_get_sprint"`
+	Goal         string `json:"goal,omitempty"`
+}
+
+// ListSprintsOutput is the result of jira_list_sprints and jira_search_sprint_by_name
+type ListSprintsOutput struct {
+	Sprints []SprintOutput `json:"sprints"`
+}
+
+// ActiveSprintOutput is the result of jira_get_active_sprint
+type ActiveSprintOutput struct {
+	Sprint *SprintOutput `json:"sprint,omitempty" jsonschema_description:"Absent when no board has an active sprint"`
 }
 
 func RegisterJiraSprintTool(s *server.MCPServer) {
 	jiraListSprintTool := mcp.NewTool("jira_list_sprints",
+		readOnlyTool(),
 		mcp.WithDescription("List all active and future sprints for a specific Jira board or project. Requires either board_id or project_key."),
 		mcp.WithString("board_id", mcp.Description("Numeric ID of the Jira board (can be found in board URL). Optional if project_key is provided.")),
 		mcp.WithString("project_key", mcp.Description("The project key (e.g., KP, PROJ, DEV). Optional if board_id is provided.")),
+		withOutputFormat[ListSprintsOutput](),
 	)
-	s.AddTool(jiraListSprintTool, mcp.NewTypedToolHandler(jiraListSprintHandler))
+	addTool(s, jiraListSprintTool, mcp.NewTypedToolHandler(jiraListSprintHandler))
 
 	jiraGetSprintTool := mcp.NewTool("jira_get_sprint",
+		readOnlyTool(),
 		mcp.WithDescription("Retrieve detailed information about a specific Jira sprint by its ID"),
 		mcp.WithString("sprint_id", mcp.Required(), mcp.Description("Numeric ID of the sprint to retrieve")),
+		withOutputFormat[SprintOutput](),
 	)
-	s.AddTool(jiraGetSprintTool, mcp.NewTypedToolHandler(jiraGetSprintHandler))
+	addTool(s, jiraGetSprintTool, mcp.NewTypedToolHandler(jiraGetSprintHandler))
 
 	jiraGetActiveSprintTool := mcp.NewTool("jira_get_active_sprint",
+		readOnlyTool(),
 		mcp.WithDescription("Get the currently active sprint for a given board or project. Requires either board_id or project_key."),
 		mcp.WithString("board_id", mcp.Description("Numeric ID of the Jira board. Optional if project_key is provided.")),
 		mcp.WithString("project_key", mcp.Description("The project key (e.g., KP, PROJ, DEV). Optional if board_id is provided.")),
+		withOutputFormat[ActiveSprintOutput](),
 	)
-	s.AddTool(jiraGetActiveSprintTool, mcp.NewTypedToolHandler(jiraGetActiveSprintHandler))
+	addTool(s, jiraGetActiveSprintTool, mcp.NewTypedToolHandler(jiraGetActiveSprintHandler))
 
 	jiraSearchSprintByNameTool := mcp.NewTool("jira_search_sprint_by_name",
+		readOnlyTool(),
 		mcp.WithDescription("Search for sprints by name across boards or projects. Supports both exact and partial name matching."),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Sprint name to search for (case-insensitive)")),
 		mcp.WithString("board_id", mcp.Description("Numeric ID of the Jira board to search in. Optional if project_key is provided.")),
 		mcp.WithString("project_key", mcp.Description("The project key (e.g., KP, PROJ, DEV) to search in. Optional if board_id is provided.")),
 		mcp.WithBoolean("exact_match", mcp.Description("If true, only return sprints with exact name match. Default is false (partial matching).")),
+		withOutputFormat[ListSprintsOutput](),
 	)
-	s.AddTool(jiraSearchSprintByNameTool, mcp.NewTypedToolHandler(searchSprintByNameHandler))
+	addTool(s, jiraSearchSprintByNameTool, mcp.NewTypedToolHandler(searchSprintByNameHandler))
 }
 
 // Helper function to get board IDs either from direct board_id or by finding boards for a project
@@ -80,7 +115,7 @@ func getBoardIDsFromInput(ctx context.Context, boardID, projectKey string) ([]in
 	}
 
 	if projectKey != "" {
-		boards, response, err := services.AgileClient().Board.Gets(ctx, &models.GetBoardsOptions{
+		boards, response, err := services.AgileClientFor(ctx).Board.Gets(ctx, &models.GetBoardsOptions{
 			ProjectKeyOrID: projectKey,
 		}, 0, 50)
 		if err != nil {
@@ -110,7 +145,7 @@ func jiraGetSprintHandler(ctx context.Context, request mcp.CallToolRequest, inpu
 		return nil, fmt.Errorf("invalid sprint_id: %v", err)
 	}
 
-	sprint, response, err := services.AgileClient().Sprint.Get(ctx, sprintID)
+	sprint, response, err := services.AgileClientFor(ctx).Sprint.Get(ctx, sprintID)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to get sprint: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -137,7 +172,7 @@ Goal: %s`,
 		sprint.Goal,
 	)
 
-	return mcp.NewToolResultText(result), nil
+	return formatResult(input.OutputFormat, newSprintOutput(sprint, sprint.OriginBoardID), result)
 }
 
 func jiraListSprintHandler(ctx context.Context, request mcp.CallToolRequest, input ListSprintsInput) (*mcp.CallToolResult, error) {
@@ -147,8 +182,9 @@ func jiraListSprintHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	}
 
 	var allSprints []string
+	output := ListSprintsOutput{Sprints: []SprintOutput{}}
 	for _, boardID := range boardIDs {
-		sprints, response, err := services.AgileClient().Board.Sprints(ctx, boardID, 0, 50, []string{"active", "future"})
+		sprints, response, err := services.AgileClientFor(ctx).Board.Sprints(ctx, boardID, 0, 50, []string{"active", "future"})
 		if err != nil {
 			if response != nil {
 				return nil, fmt.Errorf("failed to get sprints: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -157,17 +193,18 @@ func jiraListSprintHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		}
 
 		for _, sprint := range sprints.Values {
+			output.Sprints = append(output.Sprints, newSprintOutput((*models.SprintScheme)(sprint), boardID))
 			allSprints = append(allSprints, fmt.Sprintf("ID: %d\nName: %s\nState: %s\nStartDate: %s\nEndDate: %s\nBoard ID: %d\n", 
 				sprint.ID, sprint.Name, sprint.State, sprint.StartDate, sprint.EndDate, boardID))
 		}
 	}
 
 	if len(allSprints) == 0 {
-		return mcp.NewToolResultText("No sprints found."), nil
+		return formatResult(input.OutputFormat, output, "No sprints found.")
 	}
 
 	result := strings.Join(allSprints, "\n")
-	return mcp.NewToolResultText(result), nil
+	return formatResult(input.OutputFormat, output, result)
 }
 
 func jiraGetActiveSprintHandler(ctx context.Context, request mcp.CallToolRequest, input GetActiveSprintInput) (*mcp.CallToolResult, error) {
@@ -178,7 +215,7 @@ func jiraGetActiveSprintHandler(ctx context.Context, request mcp.CallToolRequest
 
 	// Loop through boards and return the first active sprint found
 	for _, boardID := range boardIDs {
-		sprints, response, err := services.AgileClient().Board.Sprints(ctx, boardID, 0, 50, []string{"active"})
+		sprints, response, err := services.AgileClientFor(ctx).Board.Sprints(ctx, boardID, 0, 50, []string{"active"})
 		if err != nil {
 			if response != nil {
 				return nil, fmt.Errorf("failed to get active sprint: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -202,11 +239,12 @@ Board ID: %d`,
 				sprint.EndDate,
 				boardID,
 			)
-			return mcp.NewToolResultText(result), nil
+			sprintOutput := newSprintOutput((*models.SprintScheme)(sprint), boardID)
+			return formatResult(input.OutputFormat, ActiveSprintOutput{Sprint: &sprintOutput}, result)
 		}
 	}
 
-	return mcp.NewToolResultText("No active sprint found."), nil
+	return formatResult(input.OutputFormat, ActiveSprintOutput{}, "No active sprint found.")
 }
 
 func searchSprintByNameHandler(ctx context.Context, request mcp.CallToolRequest, input SearchSprintByNameInput) (*mcp.CallToolResult, error) {
@@ -216,11 +254,12 @@ func searchSprintByNameHandler(ctx context.Context, request mcp.CallToolRequest,
 	}
 
 	var matchingSprints []string
+	output := ListSprintsOutput{Sprints: []SprintOutput{}}
 	searchTerm := strings.ToLower(input.Name)
 
 	for _, boardID := range boardIDs {
 		// Get all sprints (active, future, and closed) for comprehensive search
-		sprints, response, err := services.AgileClient().Board.Sprints(ctx, boardID, 0, 100, []string{"active", "future", "closed"})
+		sprints, response, err := services.AgileClientFor(ctx).Board.Sprints(ctx, boardID, 0, 100, []string{"active", "future", "closed"})
 		if err != nil {
 			if response != nil {
 				return nil, fmt.Errorf("failed to get sprints: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -239,6 +278,7 @@ func searchSprintByNameHandler(ctx context.Context, request mcp.CallToolRequest,
 			}
 
 			if isMatch {
+				output.Sprints = append(output.Sprints, newSprintOutput((*models.SprintScheme)(sprint), boardID))
 				matchingSprints = append(matchingSprints, fmt.Sprintf(`ID: %d
 Name: %s
 State: %s
@@ -264,9 +304,30 @@ Goal: %s`,
 		if input.ExactMatch {
 			matchType = "with exact name"
 		}
-		return mcp.NewToolResultText(fmt.Sprintf("No sprints found %s '%s'.", matchType, input.Name)), nil
+		return formatResult(input.OutputFormat, output, fmt.Sprintf("No sprints found %s '%s'.", matchType, input.Name))
 	}
 
 	result := strings.Join(matchingSprints, "\n\n")
-	return mcp.NewToolResultText(result), nil
+	return formatResult(input.OutputFormat, output, result)
+}
+
+// newSprintOutput converts a sprint, board sprints convert with (*models.SprintScheme)(sprint)
+func newSprintOutput(sprint *models.SprintScheme, boardID int) SprintOutput {
+	return SprintOutput{
+		ID:           sprint.ID,
+		Name:         sprint.Name,
+		State:        sprint.State,
+		StartDate:    formatSprintDate(sprint.StartDate),
+		EndDate:      formatSprintDate(sprint.EndDate),
+		CompleteDate: formatSprintDate(sprint.CompleteDate),
+		BoardID:      boardID,
+		Goal:         sprint.Goal,
+	}
+}
+
+func formatSprintDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(time.RFC3339)
 }
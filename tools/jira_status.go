@@ -13,18 +13,38 @@ import (
 // Input types for typed tools
 type ListStatusesInput struct {
 	ProjectKey string `json:"project_key" validate:"required"`
+	OutputFormatInput
+}
+
+// StatusOutput is a workflow status
+type StatusOutput struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// IssueTypeStatusesOutput lists the statuses available to one issue type
+type IssueTypeStatusesOutput struct {
+	IssueType string         `json:"issue_type"`
+	Statuses  []StatusOutput `json:"statuses"`
+}
+
+// ListStatusesOutput is the result of jira_list_statuses
+type ListStatusesOutput struct {
+	IssueTypes []IssueTypeStatusesOutput `json:"issue_types"`
 }
 
 func RegisterJiraStatusTool(s *server.MCPServer) {
 	jiraStatusListTool := mcp.NewTool("jira_list_statuses",
+		readOnlyTool(),
 		mcp.WithDescription("Retrieve all available issue status IDs and their names for a specific Jira project"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier (e.g., KP, PROJ)")),
+		withOutputFormat[ListStatusesOutput](),
 	)
-	s.AddTool(jiraStatusListTool, mcp.NewTypedToolHandler(jiraGetStatusesHandler))
+	addTool(s, jiraStatusListTool, mcp.NewTypedToolHandler(jiraGetStatusesHandler))
 }
 
 func jiraGetStatusesHandler(ctx context.Context, request mcp.CallToolRequest, input ListStatusesInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	issueTypes, response, err := client.Project.Statuses(ctx, input.ProjectKey)
 	if err != nil {
@@ -34,18 +54,23 @@ func jiraGetStatusesHandler(ctx context.Context, request mcp.CallToolRequest, in
 		return nil, fmt.Errorf("failed to get statuses: %v", err)
 	}
 
+	output := ListStatusesOutput{IssueTypes: []IssueTypeStatusesOutput{}}
+
 	if len(issueTypes) == 0 {
-		return mcp.NewToolResultText("No issue types found for this project."), nil
+		return formatResult(input.OutputFormat, output, "No issue types found for this project.")
 	}
 
 	var result strings.Builder
 	result.WriteString("Available Statuses:\n")
 	for _, issueType := range issueTypes {
+		typeOutput := IssueTypeStatusesOutput{IssueType: issueType.Name, Statuses: []StatusOutput{}}
 		result.WriteString(fmt.Sprintf("\nIssue Type: %s\n", issueType.Name))
 		for _, status := range issueType.Statuses {
+			typeOutput.Statuses = append(typeOutput.Statuses, StatusOutput{ID: status.ID, Name: status.Name})
 			result.WriteString(fmt.Sprintf("  - %s: %s\n", status.Name, status.ID))
 		}
+		output.IssueTypes = append(output.IssueTypes, typeOutput)
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return formatResult(input.OutputFormat, output, result.String())
 }
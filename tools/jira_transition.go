@@ -3,41 +3,130 @@ package tools
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
 	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
 )
 
 // Input types for typed tools
 type TransitionIssueInput struct {
-	IssueKey     string `json:"issue_key" validate:"required"`
-	TransitionID string `json:"transition_id" validate:"required"`
-	Comment      string `json:"comment,omitempty"`
+	IssueKey          string                 `json:"issue_key" validate:"required"`
+	TransitionID      string                 `json:"transition_id,omitempty"`
+	TargetStatus      string                 `json:"target_status,omitempty"`
+	Comment           string                 `json:"comment,omitempty"`
+	Resolution        string                 `json:"resolution,omitempty"`
+	FixVersions       string                 `json:"fix_versions,omitempty"`
+	AssigneeAccountID string                 `json:"assignee_account_id,omitempty"`
+	Fields            map[string]interface{} `json:"fields,omitempty"`
+	ConfirmationInput
+	OutputFormatInput
+}
+
+// TransitionIssueOutput is the result of jira_transition_issue
+type TransitionIssueOutput struct {
+	IssueKey     string   `json:"issue_key"`
+	TransitionID string   `json:"transition_id,omitempty" jsonschema_description:"Set when the transition was requested by ID"`
+	Path         []string `json:"path,omitempty" jsonschema_description:"Statuses the issue moved through, starting with its original status. Set when the transition was requested by target_status"`
+	Transitioned bool     `json:"transitioned" jsonschema_description:"False when the issue was already in the target status"`
+	CommentAdded bool     `json:"comment_added"`
+	ConfirmationOutput
 }
 
 func RegisterJiraTransitionTool(s *server.MCPServer) {
 	jiraTransitionTool := mcp.NewTool("jira_transition_issue",
-		mcp.WithDescription("Transition an issue through its workflow using a valid transition ID. Get available transitions from jira_get_issue"),
+		destructiveTool(false),
+		mcp.WithDescription("Transition an issue through its workflow, either with a transition ID from jira_get_issue or by naming the target status. When no direct transition leads to the target status, the shortest path through the issue type's workflow is planned first and the issue is moved through its intermediate statuses; reading the workflow needs Jira admin rights. Fields required by the transition screen can be set in the same call"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The issue to transition (e.g., KP-123)")),
-		mcp.WithString("transition_id", mcp.Required(), mcp.Description("Transition ID from available transitions list")),
-		mcp.WithString("comment", mcp.Description("Optional comment to add with transition")),
+		mcp.WithString("transition_id", mcp.Description("Transition ID from available transitions list. Optional if target_status is provided.")),
+		mcp.WithString("target_status", mcp.Description("Name of the status to move the issue to (case-insensitive, e.g., 'In Review'). Optional if transition_id is provided.")),
+		mcp.WithString("comment", mcp.Description("Optional comment to add with transition (supports markdown)")),
+		mcp.WithString("resolution", mcp.Description("Resolution name to set during the transition (e.g., Done, Won't Do)")),
+		mcp.WithString("fix_versions", mcp.Description("Comma-separated fix version names to set during the transition (e.g., 'v1.2.0,v1.2.1')")),
+		mcp.WithString("assignee_account_id", mcp.Description("Account ID of the user to assign during the transition")),
+		mcp.WithObject("fields", mcp.Description("Additional raw Jira fields to set during the transition, keyed by field ID (e.g., {\"customfield_10010\": \"value\"})")),
+		withConfirmation(),
+		withOutputFormat[TransitionIssueOutput](),
 	)
-	s.AddTool(jiraTransitionTool, mcp.NewTypedToolHandler(jiraTransitionIssueHandler))
+	addTool(s, jiraTransitionTool, mcp.NewTypedToolHandler(jiraTransitionIssueHandler))
 }
 
 func jiraTransitionIssueHandler(ctx context.Context, request mcp.CallToolRequest, input TransitionIssueInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
-	var options *models.IssueMoveOptionsV3
-	if input.Comment != "" {
-		options = &models.IssueMoveOptionsV3{
-			Fields: &models.IssueScheme{},
+	if input.TransitionID == "" && input.TargetStatus == "" {
+		return nil, fmt.Errorf("either transition_id or target_status argument is required")
+	}
+
+	if err := checkIssueActions(ctx, client, input.IssueKey, transitionActions(input)...); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	for key, value := range input.Fields {
+		fields[key] = value
+	}
+
+	if input.Resolution != "" {
+		fields["resolution"] = map[string]interface{}{"name": input.Resolution}
+	}
+
+	if input.FixVersions != "" {
+		var versions []map[string]interface{}
+		for _, name := range splitList(input.FixVersions) {
+			versions = append(versions, map[string]interface{}{"name": name})
 		}
+		fields["fixVersions"] = versions
 	}
 
-	response, err := client.Issue.Move(ctx, input.IssueKey, input.TransitionID, options)
+	if input.AssigneeAccountID != "" {
+		fields["assignee"] = userRef(ctx, input.AssigneeAccountID)
+	}
+
+	confirmation, err := requireConfirmation(ctx, request, input.ConfirmationToken)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation != nil {
+		return previewTransition(ctx, client, input, *confirmation)
+	}
+
+	auditFields := []string{"status"}
+	for id := range fields {
+		auditFields = append(auditFields, id)
+	}
+	before := auditSnapshot(ctx, client, input.IssueKey, auditFields)
+
+	if input.TransitionID == "" {
+		path, err := transitionToStatus(ctx, client, input.IssueKey, input.TargetStatus, fields, input.Comment)
+		// Hops made before a failure are audited too, so jira_undo can move the issue back
+		auditChanges(ctx, client, input.IssueKey, before)
+		if err != nil {
+			return nil, err
+		}
+
+		output := TransitionIssueOutput{IssueKey: input.IssueKey, Path: path}
+		if len(path) == 1 {
+			return formatResult(input.OutputFormat, output, fmt.Sprintf("Issue %s is already in status %s", input.IssueKey, path[0]))
+		}
+
+		output.Transitioned = true
+		output.CommentAdded = input.Comment != ""
+
+		result := fmt.Sprintf("Issue transition completed successfully\nPath: %s", strings.Join(path, " -> "))
+		if input.Comment != "" {
+			result += "\nComment added with the transition"
+		}
+		return formatResult(input.OutputFormat, output, result)
+	}
+
+	response, err := transitionIssue(ctx, client, input.IssueKey, input.TransitionID, fields, input.Comment)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("transition failed: %s (endpoint: %s)",
@@ -47,5 +136,401 @@ func jiraTransitionIssueHandler(ctx context.Context, request mcp.CallToolRequest
 		return nil, fmt.Errorf("transition failed: %v", err)
 	}
 
-	return mcp.NewToolResultText("Issue transition completed successfully"), nil
+	auditChanges(ctx, client, input.IssueKey, before)
+
+	output := TransitionIssueOutput{
+		IssueKey:     input.IssueKey,
+		TransitionID: input.TransitionID,
+		Transitioned: true,
+		CommentAdded: input.Comment != "",
+	}
+
+	result := "Issue transition completed successfully"
+	if input.Comment != "" {
+		result += "\nComment added with the transition"
+	}
+	return formatResult(input.OutputFormat, output, result)
+}
+
+// previewTransition describes a transition without running it, for confirmation mode
+func previewTransition(ctx context.Context, client *jira.Client, input TransitionIssueInput, confirmation ConfirmationOutput) (*mcp.CallToolResult, error) {
+	issue, response, err := getIssue(ctx, client, input.IssueKey, []string{"summary", "status"}, []string{"transitions"})
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get issue: %v", err)
+	}
+
+	current := ""
+	if issue.Fields != nil && issue.Fields.Status != nil {
+		current = issue.Fields.Status.Name
+	}
+
+	target := input.TargetStatus
+	if input.TransitionID != "" {
+		target = "transition " + input.TransitionID
+		for _, transition := range issue.Transitions {
+			if transition.ID == input.TransitionID && transition.To != nil {
+				target = fmt.Sprintf("%s (transition %s)", transition.To.Name, transition.ID)
+			}
+		}
+	}
+
+	output := TransitionIssueOutput{IssueKey: input.IssueKey, TransitionID: input.TransitionID, ConfirmationOutput: confirmation}
+
+	text := fmt.Sprintf("Preview: issue %s would move from %s to %s", input.IssueKey, current, target)
+	if input.Comment != "" {
+		text += ", with a comment"
+	}
+	return formatResult(input.OutputFormat, output, text+"\n"+confirmationText(&confirmation))
+}
+
+// transitionIssue posts a transition with optional screen fields and a markdown comment.
+// The request is built by hand because client.Issue.Move drops the transition ID whenever
+// custom fields or operations are merged, and ignores plain fields otherwise.
+func transitionIssue(ctx context.Context, client *jira.Client, issueKey, transitionID string, fields map[string]interface{}, comment string) (*models.ResponseScheme, error) {
+	payload := map[string]interface{}{
+		"transition": map[string]interface{}{"id": transitionID},
+	}
+
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
+
+	if comment != "" {
+		// Server and Data Center take the comment as wiki markup
+		var body interface{} = util.MarkdownToADF(comment)
+		if isServer(ctx) {
+			body = util.RenderWiki(util.MarkdownToADF(comment))
+		}
+		payload["update"] = map[string]interface{}{
+			"comment": []map[string]interface{}{
+				{"add": map[string]interface{}{"body": body}},
+			},
+		}
+	}
+
+	endpoint := restAPI(ctx, fmt.Sprintf("issue/%s/transitions", issueKey))
+	req, err := client.NewRequest(ctx, http.MethodPost, endpoint, "", payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transition request: %w", err)
+	}
+
+	response, err := client.Call(req, nil)
+	auditRequest(ctx, response, issueKey)
+	return response, err
+}
+
+// transitionActions lists the write policy actions a transition performs: besides the transition,
+// setting fields or fix versions edits the issue, setting the assignee assigns it and a comment
+// comments on it
+func transitionActions(input TransitionIssueInput) []string {
+	actions := []string{actionTransition}
+	edits := input.FixVersions != ""
+	assigns := input.AssigneeAccountID != ""
+	for id := range input.Fields {
+		if id == "assignee" {
+			assigns = true
+		} else {
+			edits = true
+		}
+	}
+
+	if edits {
+		actions = append(actions, actionEdit)
+	}
+	if assigns {
+		actions = append(actions, actionAssign)
+	}
+	if input.Comment != "" {
+		actions = append(actions, actionComment)
+	}
+	return actions
+}
+
+// maxTransitionHops bounds how many intermediate statuses transitionToStatus walks through.
+const maxTransitionHops = 6
+
+// transitionToStatus moves an issue to the named status and returns the status path it took,
+// starting with the current status. A direct transition is used when one exists; otherwise the
+// shortest path through the workflow of the issue type is planned first, and the issue only
+// moves once a path to the target exists. Fields and comment are only sent with the final hop.
+// On failure the returned path holds the hops that were already made.
+func transitionToStatus(ctx context.Context, client *jira.Client, issueKey, targetStatus string, fields map[string]interface{}, comment string) ([]string, error) {
+	issue, response, err := getIssue(ctx, client, issueKey, []string{"status", "project", "issuetype"}, nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get issue: %v", err)
+	}
+
+	current, currentID := "", ""
+	if issue.Fields != nil && issue.Fields.Status != nil {
+		current, currentID = issue.Fields.Status.Name, issue.Fields.Status.ID
+	}
+
+	path := []string{current}
+	if strings.EqualFold(current, targetStatus) {
+		return path, nil
+	}
+
+	transitions, err := getIssueTransitions(ctx, client, issueKey)
+	if err != nil {
+		return path, err
+	}
+
+	if direct := findTransitionByStatus(transitions, targetStatus); direct != nil {
+		if response, err := transitionIssue(ctx, client, issueKey, direct.ID, fields, comment); err != nil {
+			return path, transitionPathError(path, response, err)
+		}
+		return append(path, direct.To.Name), nil
+	}
+
+	graph, err := getWorkflowGraph(ctx, client, issue)
+	if err != nil {
+		return path, fmt.Errorf("no transition leads from %q to %q, and the workflow could not be read to find a path through other statuses (%v). Reachable statuses: %s",
+			current, targetStatus, err, strings.Join(reachableStatuses(transitions), ", "))
+	}
+
+	route, err := graph.shortestPath(currentID, targetStatus)
+	if err != nil {
+		return path, err
+	}
+
+	for i, statusID := range route {
+		if i > 0 {
+			if transitions, err = getIssueTransitions(ctx, client, issueKey); err != nil {
+				return path, transitionPathError(path, nil, err)
+			}
+		}
+
+		next := nextTransitionHop(transitions, statusID, graph.statusName(statusID))
+		if next == nil {
+			return path, transitionPathError(path, nil, fmt.Errorf("the workflow leads to %q through %q, but that transition is not available for %s. Reachable statuses: %s",
+				targetStatus, graph.statusName(statusID), issueKey, strings.Join(reachableStatuses(transitions), ", ")))
+		}
+
+		var hopFields map[string]interface{}
+		hopComment := ""
+		if i == len(route)-1 {
+			hopFields, hopComment = fields, comment
+		}
+		if response, err := transitionIssue(ctx, client, issueKey, next.ID, hopFields, hopComment); err != nil {
+			return path, transitionPathError(path, response, err)
+		}
+		path = append(path, next.To.Name)
+	}
+
+	return path, nil
+}
+
+func getIssueTransitions(ctx context.Context, client *jira.Client, issueKey string) ([]*models.IssueTransitionScheme, error) {
+	var transitions *models.IssueTransitionsScheme
+	var response *models.ResponseScheme
+	var err error
+	if server := services.ServerClientFor(ctx); server != nil {
+		transitions, response, err = server.Issue.Transitions(ctx, issueKey)
+	} else {
+		transitions, response, err = client.Issue.Transitions(ctx, issueKey)
+	}
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get transitions: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get transitions: %v", err)
+	}
+	return transitions.Transitions, nil
+}
+
+// workflowGraph is the workflow of an issue type: its statuses and the transitions between them
+type workflowGraph struct {
+	statuses    []*models.WorkflowStatusScheme
+	transitions []*models.WorkflowTransitionScheme
+}
+
+// getWorkflowGraph reads the workflow the project's workflow scheme uses for the issue's type.
+// Reading workflows needs admin rights, and team-managed projects have no workflow scheme.
+// Server and Data Center have no workflow search, so only direct transitions work there.
+func getWorkflowGraph(ctx context.Context, client *jira.Client, issue *models.IssueScheme) (*workflowGraph, error) {
+	if isServer(ctx) {
+		return nil, fmt.Errorf("Jira Server and Data Center do not expose workflow transitions")
+	}
+	if issue.Fields == nil || issue.Fields.Project == nil || issue.Fields.IssueType == nil {
+		return nil, fmt.Errorf("the issue has no project or issue type")
+	}
+
+	var associations struct {
+		Values []struct {
+			WorkflowScheme struct {
+				DefaultWorkflow   string            `json:"defaultWorkflow"`
+				IssueTypeMappings map[string]string `json:"issueTypeMappings"`
+			} `json:"workflowScheme"`
+		} `json:"values"`
+	}
+	endpoint := "rest/api/3/workflowscheme/project?projectId=" + url.QueryEscape(issue.Fields.Project.ID)
+	if err := getJSON(ctx, client, endpoint, &associations); err != nil {
+		return nil, fmt.Errorf("failed to get workflow scheme: %v", err)
+	}
+	if len(associations.Values) == 0 {
+		return nil, fmt.Errorf("project %s has no workflow scheme", issue.Fields.Project.Key)
+	}
+
+	scheme := associations.Values[0].WorkflowScheme
+	name, ok := scheme.IssueTypeMappings[issue.Fields.IssueType.ID]
+	if !ok {
+		name = scheme.DefaultWorkflow
+	}
+
+	params := url.Values{}
+	params.Set("workflowName", name)
+	params.Set("expand", "transitions,statuses")
+	var page models.WorkflowPageScheme
+	if err := getJSON(ctx, client, "rest/api/3/workflow/search?"+params.Encode(), &page); err != nil {
+		return nil, fmt.Errorf("failed to get workflow %q: %v", name, err)
+	}
+	for _, workflow := range page.Values {
+		if workflow.ID != nil && workflow.ID.Name == name {
+			return &workflowGraph{statuses: workflow.Statuses, transitions: workflow.Transitions}, nil
+		}
+	}
+	return nil, fmt.Errorf("workflow %q not found", name)
+}
+
+func getJSON(ctx context.Context, client *jira.Client, endpoint string, out interface{}) error {
+	req, err := client.NewRequest(ctx, http.MethodGet, endpoint, "", nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Call(req, out)
+	if err != nil && response != nil {
+		return fmt.Errorf("%s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+	}
+	return err
+}
+
+func (g *workflowGraph) statusName(id string) string {
+	for _, status := range g.statuses {
+		if status.ID == id {
+			return status.Name
+		}
+	}
+	return id
+}
+
+// successors returns the statuses one transition away from a status, in workflow order.
+// Global transitions lead to their status from every other one.
+func (g *workflowGraph) successors(statusID string) []string {
+	var next []string
+	for _, transition := range g.transitions {
+		if transition.Type == "initial" || transition.To == statusID || containsString(next, transition.To) {
+			continue
+		}
+		if len(transition.From) == 0 || containsString(transition.From, statusID) {
+			next = append(next, transition.To)
+		}
+	}
+	return next
+}
+
+// shortestPath searches the workflow breadth first for the fewest transitions from a status to
+// the named one, and returns the status IDs after the first. Without a path it lists the
+// statuses that can be reached.
+func (g *workflowGraph) shortestPath(fromID, targetStatus string) ([]string, error) {
+	var targets []string
+	for _, status := range g.statuses {
+		if strings.EqualFold(status.Name, targetStatus) {
+			targets = append(targets, status.ID)
+		}
+	}
+	if len(targets) == 0 {
+		var names []string
+		for _, status := range g.statuses {
+			names = append(names, status.Name)
+		}
+		return nil, fmt.Errorf("status %q is not part of the workflow. Workflow statuses: %s", targetStatus, strings.Join(names, ", "))
+	}
+
+	previous := map[string]string{fromID: ""}
+	queue := []string{fromID}
+	var reached []string
+	for len(queue) > 0 {
+		status := queue[0]
+		queue = queue[1:]
+		for _, next := range g.successors(status) {
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = status
+			queue = append(queue, next)
+			reached = append(reached, g.statusName(next))
+
+			if containsString(targets, next) {
+				var route []string
+				for id := next; id != fromID; id = previous[id] {
+					route = append([]string{id}, route...)
+				}
+				if len(route) > maxTransitionHops+1 {
+					return nil, fmt.Errorf("status %q is %d transitions away from %q, more than the %d allowed", targetStatus, len(route), g.statusName(fromID), maxTransitionHops+1)
+				}
+				return route, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("status %q is not reachable from %q. Reachable statuses: %s", targetStatus, g.statusName(fromID), strings.Join(reached, ", "))
+}
+
+// findTransitionByStatus returns the transition leading to the named status, falling back to a
+// transition with that name, compared case-insensitively.
+func findTransitionByStatus(transitions []*models.IssueTransitionScheme, status string) *models.IssueTransitionScheme {
+	for _, transition := range transitions {
+		if transition.To != nil && strings.EqualFold(transition.To.Name, status) {
+			return transition
+		}
+	}
+	for _, transition := range transitions {
+		if transition.To != nil && strings.EqualFold(transition.Name, status) {
+			return transition
+		}
+	}
+	return nil
+}
+
+// nextTransitionHop picks the available transition leading to the next status of a planned path,
+// matched by status ID and then by name.
+func nextTransitionHop(transitions []*models.IssueTransitionScheme, statusID, statusName string) *models.IssueTransitionScheme {
+	for _, transition := range transitions {
+		if transition.To != nil && statusID != "" && transition.To.ID == statusID {
+			return transition
+		}
+	}
+	for _, transition := range transitions {
+		if transition.To != nil && strings.EqualFold(transition.To.Name, statusName) {
+			return transition
+		}
+	}
+	return nil
+}
+
+func reachableStatuses(transitions []*models.IssueTransitionScheme) []string {
+	var names []string
+	for _, transition := range transitions {
+		if transition.To != nil {
+			names = append(names, transition.To.Name)
+		}
+	}
+	return names
+}
+
+func transitionPathError(path []string, response *models.ResponseScheme, err error) error {
+	progress := ""
+	if len(path) > 1 {
+		progress = fmt.Sprintf(" after moving through %s", strings.Join(path, " -> "))
+	}
+	if response != nil {
+		return fmt.Errorf("transition failed%s: %s (endpoint: %s)", progress, response.Bytes.String(), response.Endpoint)
+	}
+	return fmt.Errorf("transition failed%s: %v", progress, err)
 }
@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestWorkflowShortestPath(t *testing.T) {
+	statuses := []*models.WorkflowStatusScheme{
+		{ID: "1", Name: "To Do"},
+		{ID: "2", Name: "In Progress"},
+		{ID: "3", Name: "In Review"},
+		{ID: "4", Name: "Done"},
+		{ID: "5", Name: "Blocked"},
+		{ID: "6", Name: "Archived"},
+	}
+	transition := func(from []string, to string) *models.WorkflowTransitionScheme {
+		return &models.WorkflowTransitionScheme{From: from, To: to, Type: "directed"}
+	}
+
+	tests := []struct {
+		name        string
+		transitions []*models.WorkflowTransitionScheme
+		from        string
+		target      string
+		want        []string
+		wantErr     string
+	}{
+		{
+			name:        "linear",
+			transitions: []*models.WorkflowTransitionScheme{transition([]string{"1"}, "2"), transition([]string{"2"}, "3"), transition([]string{"3"}, "4")},
+			from:        "1",
+			target:      "done",
+			want:        []string{"2", "3", "4"},
+		},
+		{
+			// Blocked sorts next to Done in the status list, but is a dead end
+			name: "branch",
+			transitions: []*models.WorkflowTransitionScheme{
+				transition([]string{"1"}, "5"), transition([]string{"1"}, "2"), transition([]string{"5"}, "1"),
+				transition([]string{"2"}, "3"), transition([]string{"3"}, "4"),
+			},
+			from:   "1",
+			target: "Done",
+			want:   []string{"2", "3", "4"},
+		},
+		{
+			name: "shortest of two branches",
+			transitions: []*models.WorkflowTransitionScheme{
+				transition([]string{"1"}, "2"), transition([]string{"2"}, "3"), transition([]string{"3"}, "4"),
+				transition([]string{"1"}, "5"), transition([]string{"5"}, "4"),
+			},
+			from:   "1",
+			target: "Done",
+			want:   []string{"5", "4"},
+		},
+		{
+			name: "cycle",
+			transitions: []*models.WorkflowTransitionScheme{
+				transition([]string{"1"}, "2"), transition([]string{"2"}, "3"), transition([]string{"3"}, "1"),
+			},
+			from:    "1",
+			target:  "Done",
+			wantErr: `status "Done" is not reachable from "To Do". Reachable statuses: In Progress, In Review`,
+		},
+		{
+			name: "global transition",
+			transitions: []*models.WorkflowTransitionScheme{
+				{To: "1", Type: "initial"}, transition([]string{"1"}, "2"), {To: "6", Type: "global"}, transition([]string{"6"}, "4"),
+			},
+			from:   "2",
+			target: "Done",
+			want:   []string{"6", "4"},
+		},
+		{
+			name:        "initial transitions are not hops",
+			transitions: []*models.WorkflowTransitionScheme{{To: "4", Type: "initial"}},
+			from:        "1",
+			target:      "Done",
+			wantErr:     "Reachable statuses: ",
+		},
+		{
+			name:        "status outside the workflow",
+			transitions: []*models.WorkflowTransitionScheme{transition([]string{"1"}, "2")},
+			from:        "1",
+			target:      "Shipped",
+			wantErr:     `status "Shipped" is not part of the workflow`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := &workflowGraph{statuses: statuses, transitions: tt.transitions}
+			got, err := graph.shortestPath(tt.from, tt.target)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("path = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowShortestPathIsBounded(t *testing.T) {
+	var statuses []*models.WorkflowStatusScheme
+	var transitions []*models.WorkflowTransitionScheme
+	for i := 0; i <= maxTransitionHops+2; i++ {
+		id := string(rune('a' + i))
+		statuses = append(statuses, &models.WorkflowStatusScheme{ID: id, Name: "S" + id})
+		if i > 0 {
+			transitions = append(transitions, &models.WorkflowTransitionScheme{From: []string{string(rune('a' + i - 1))}, To: id})
+		}
+	}
+
+	graph := &workflowGraph{statuses: statuses, transitions: transitions}
+	if _, err := graph.shortestPath("a", statuses[len(statuses)-1].Name); err == nil {
+		t.Error("expected an error for a path longer than the hop limit")
+	}
+}
+
+func TestNextTransitionHop(t *testing.T) {
+	start := &models.IssueTransitionScheme{ID: "11", Name: "Start", To: &models.StatusScheme{ID: "2", Name: "In Progress"}}
+	review := &models.IssueTransitionScheme{ID: "21", Name: "Review", To: &models.StatusScheme{ID: "3", Name: "In Review"}}
+	renamed := &models.IssueTransitionScheme{ID: "31", Name: "Finish", To: &models.StatusScheme{Name: "done"}}
+	transitions := []*models.IssueTransitionScheme{start, {ID: "99", Name: "Broken"}, review, renamed}
+
+	tests := []struct {
+		name     string
+		statusID string
+		status   string
+		want     *models.IssueTransitionScheme
+	}{
+		{"by status ID", "3", "Something else", review},
+		{"by status name", "", "in progress", start},
+		{"by name when IDs differ", "404", "Done", renamed},
+		{"not available", "5", "Blocked", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextTransitionHop(transitions, tt.statusID, tt.status); got != tt.want {
+				t.Errorf("nextTransitionHop() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransitionIssueRequestBody(t *testing.T) {
+	var body map[string]interface{}
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/api/3/issue/KP-1/transitions" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer jiraServer.Close()
+
+	ctx := stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+	input := TransitionIssueInput{
+		IssueKey:     "KP-1",
+		TransitionID: "31",
+		Fields:       map[string]interface{}{"customfield_10016": 5},
+		Resolution:   "Won't Do",
+		FixVersions:  "1.0, 1.1",
+		Comment:      "Closing as **duplicate**",
+	}
+	if _, err := jiraTransitionIssueHandler(ctx, mcp.CallToolRequest{}, input); err != nil {
+		t.Fatal(err)
+	}
+
+	if transition, _ := body["transition"].(map[string]interface{}); transition["id"] != "31" {
+		t.Errorf("transition = %v", body["transition"])
+	}
+	wantFields := map[string]interface{}{
+		"customfield_10016": float64(5),
+		"resolution":        map[string]interface{}{"name": "Won't Do"},
+		"fixVersions":       []interface{}{map[string]interface{}{"name": "1.0"}, map[string]interface{}{"name": "1.1"}},
+	}
+	if !reflect.DeepEqual(body["fields"], wantFields) {
+		t.Errorf("fields = %v, want %v", body["fields"], wantFields)
+	}
+
+	update, _ := body["update"].(map[string]interface{})
+	comments, _ := update["comment"].([]interface{})
+	if len(comments) != 1 {
+		t.Fatalf("update = %v", body["update"])
+	}
+	add, _ := comments[0].(map[string]interface{})["add"].(map[string]interface{})
+	doc, _ := add["body"].(map[string]interface{})
+	if encoded, _ := json.Marshal(doc); doc["type"] != "doc" || !strings.Contains(string(encoded), `"strong"`) || !strings.Contains(string(encoded), "duplicate") {
+		t.Errorf("comment body = %s, want an ADF document", encoded)
+	}
+}
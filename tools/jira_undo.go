@@ -0,0 +1,673 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Input types for typed tools
+type UndoInput struct {
+	Count               int    `json:"count,omitempty"`
+	IssueKey            string `json:"issue_key,omitempty"`
+	DeleteCreatedIssues bool   `json:"delete_created_issues,omitempty"`
+	Apply               bool   `json:"apply,omitempty"`
+	ConfirmationInput
+	OutputFormatInput
+}
+
+// UndoStepOutput is one step reverting part of a recorded change
+type UndoStepOutput struct {
+	RecordID string `json:"record_id"`
+	Tool     string `json:"tool"`
+	Time     string `json:"time"`
+	Issue    string `json:"issue,omitempty"`
+	Action   string `json:"action" jsonschema_description:"What the step does to revert the change"`
+	Result   string `json:"result" jsonschema_description:"planned, reverted, skipped (cannot be reversed or changed since) or failed"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// UndoOutput is the result of jira_undo
+type UndoOutput struct {
+	DryRun   bool             `json:"dry_run" jsonschema_description:"True for a preview, nothing was changed"`
+	Records  []string         `json:"records" jsonschema_description:"IDs of the audit records being reverted, newest first"`
+	Steps    []UndoStepOutput `json:"steps"`
+	Reverted int              `json:"reverted"`
+	Skipped  int              `json:"skipped"`
+	Failed   int              `json:"failed"`
+	ConfirmationOutput
+}
+
+const (
+	defaultUndoCount = 1
+	maxUndoCount     = 20
+)
+
+// Results of an undo step
+const (
+	undoPlanned  = "planned"
+	undoReverted = "reverted"
+	undoSkipped  = "skipped"
+	undoFailed   = "failed"
+)
+
+func RegisterJiraUndoTool(s *server.MCPServer) {
+	jiraUndoTool := mcp.NewTool("jira_undo",
+		destructiveTool(false),
+		mcp.WithDescription("Revert the most recent changes this server made, read from its audit log: restore previous field values (summary, description, ...), remove added labels and other list values, delete comments, worklogs and links it created, reassign and move issues back to their previous status where a transition exists. "+
+			"Without apply, returns the steps it would take; steps that cannot be reversed are reported as skipped. A field that was changed again since is left alone"),
+		mcp.WithNumber("count", mcp.Description("Number of recorded tool calls to revert, newest first (default: 1, max: 20)")),
+		mcp.WithString("issue_key", mcp.Description("Only revert calls that changed this issue (e.g., PROJ-123)")),
+		mcp.WithBoolean("delete_created_issues", mcp.Description("Delete issues the reverted calls created. Defaults to false, which reports them as skipped")),
+		mcp.WithBoolean("apply", mcp.Description("If true, revert the changes. Defaults to false, which only previews the steps")),
+		withConfirmation(),
+		withOutputFormat[UndoOutput](),
+	)
+	addTool(s, jiraUndoTool, mcp.NewTypedToolHandler(jiraUndoHandler))
+}
+
+func jiraUndoHandler(ctx context.Context, request mcp.CallToolRequest, input UndoInput) (*mcp.CallToolResult, error) {
+	l := currentAuditLog.Load()
+	if l == nil {
+		return nil, fmt.Errorf("jira_undo reads the audit log, start the server with --audit-log or JIRA_MCP_AUDIT_LOG")
+	}
+
+	count := input.Count
+	if count <= 0 {
+		count = defaultUndoCount
+	}
+	if count > maxUndoCount {
+		count = maxUndoCount
+	}
+
+	// The log is only read once Jira has confirmed who the caller is
+	actor, err := services.Actor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	records, err := recentAuditRecords(l.config, count, input.IssueKey, actor)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no recorded changes to revert")
+	}
+
+	output := UndoOutput{DryRun: !input.Apply, Records: []string{}, Steps: []UndoStepOutput{}}
+	var steps []undoStep
+	for _, record := range records {
+		output.Records = append(output.Records, record.ID)
+		steps = append(steps, planUndo(record, input.DeleteCreatedIssues)...)
+	}
+	for _, step := range steps {
+		stepOutput := step.output()
+		if stepOutput.Result == undoSkipped {
+			output.Skipped++
+		}
+		output.Steps = append(output.Steps, stepOutput)
+	}
+
+	if !input.Apply {
+		return formatResult(input.OutputFormat, output, formatUndo(output))
+	}
+
+	confirmation, err := requireConfirmation(ctx, request, input.ConfirmationToken)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation != nil {
+		output.DryRun = true
+		output.ConfirmationOutput = *confirmation
+		return formatResult(input.OutputFormat, output, formatUndo(output)+confirmationText(confirmation))
+	}
+
+	client := services.JiraClientFor(ctx)
+	unfinishedRecords := map[string]bool{}
+	output.Skipped = 0
+	for i, step := range steps {
+		if step.reason == "" {
+			result, reason := step.apply(ctx, client)
+			output.Steps[i].Result = result
+			output.Steps[i].Reason = reason
+		}
+
+		switch output.Steps[i].Result {
+		case undoReverted:
+			output.Reverted++
+		case undoSkipped:
+			output.Skipped++
+			if step.optional {
+				unfinishedRecords[step.record.ID] = true
+			}
+		case undoFailed:
+			output.Failed++
+			unfinishedRecords[step.record.ID] = true
+		}
+	}
+
+	// Records with a failed step, or a step left for a later undo with other options, stay in the
+	// log as not undone, so the undo can be retried. Other skipped steps have nothing left to revert.
+	for _, record := range records {
+		if !unfinishedRecords[record.ID] {
+			auditUndone(ctx, record.ID)
+		}
+	}
+
+	return formatResult(input.OutputFormat, output, formatUndo(output))
+}
+
+// recentAuditRecords reads the audit log, newest file first, and returns up to count records
+// of actor that can be reverted, newest first
+func recentAuditRecords(config AuditLogConfig, count int, issueKey, actor string) ([]AuditRecord, error) {
+	var records []AuditRecord
+	for i := 0; i <= config.MaxBackups; i++ {
+		filename := config.Path
+		if i > 0 {
+			filename = fmt.Sprintf("%s.%d", config.Path, i)
+		}
+
+		fileRecords, err := readAuditLogFile(filename)
+		if os.IsNotExist(err) && i > 0 {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(fileRecords, records...)
+		if candidates := undoCandidates(records, count, issueKey, actor); len(candidates) >= count {
+			return candidates, nil
+		}
+	}
+	return undoCandidates(records, count, issueKey, actor), nil
+}
+
+func readAuditLogFile(filename string) ([]AuditRecord, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var record AuditRecord
+		// A line being written or cut by a crash is skipped
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", filename, err)
+	}
+	return records, nil
+}
+
+// undoCandidates walks the records newest first and returns up to count records of actor that
+// changed something and were not reverted yet. Undo records are newer than the records they
+// revert, so they are seen first. Callers never see or revert the changes of other users.
+func undoCandidates(records []AuditRecord, count int, issueKey, actor string) []AuditRecord {
+	undone := map[string]bool{}
+	var candidates []AuditRecord
+
+	for i := len(records) - 1; i >= 0 && len(candidates) < count; i-- {
+		record := records[i]
+		for _, id := range record.Undone {
+			undone[id] = true
+		}
+
+		if record.ID == "" || undone[record.ID] || record.Tool == "jira_undo" || record.Actor != actor {
+			continue
+		}
+		if len(record.Changes) == 0 && len(record.Created) == 0 && (!record.Success || len(record.IssueKeys) == 0) {
+			continue
+		}
+		if issueKey != "" && !containsFold(record.IssueKeys, issueKey) {
+			continue
+		}
+		candidates = append(candidates, record)
+	}
+
+	return candidates
+}
+
+// undoStep reverts one change or created resource of a record
+type undoStep struct {
+	record   AuditRecord
+	issue    string
+	action   string
+	describe string
+	change   *AuditChange
+	resource *AuditResource
+	// reason is set when the step cannot be reversed
+	reason string
+	// optional is set when the step is skipped because of an option of the call, so a later
+	// undo with the option can still run it
+	optional bool
+}
+
+func (s undoStep) output() UndoStepOutput {
+	output := UndoStepOutput{
+		RecordID: s.record.ID,
+		Tool:     s.record.Tool,
+		Time:     s.record.Time,
+		Issue:    s.issue,
+		Action:   s.describe,
+		Result:   undoPlanned,
+		Reason:   s.reason,
+	}
+	if s.reason != "" {
+		output.Result = undoSkipped
+	}
+	return output
+}
+
+// planUndo lists the steps reverting a record: created resources are removed first, then
+// each issue moves back to its status before its fields are restored, as workflows often
+// reset fields such as the resolution on the way back
+func planUndo(record AuditRecord, deleteCreatedIssues bool) []undoStep {
+	var steps []undoStep
+
+	for i := len(record.Created) - 1; i >= 0; i-- {
+		resource := record.Created[i]
+		step := undoStep{record: record, issue: resource.Issue, resource: &record.Created[i]}
+
+		switch resource.Type {
+		case auditResourceComment:
+			step.action = actionComment
+			step.describe = fmt.Sprintf("delete comment %s", resource.ID)
+		case auditResourceWorklog:
+			step.action = actionWorklog
+			step.describe = fmt.Sprintf("delete worklog %s", resource.ID)
+		case auditResourceLink:
+			step.action = actionLink
+			step.describe = fmt.Sprintf("delete %s link %s -> %s", resource.LinkType, resource.Issue, resource.Target)
+		case auditResourceIssue:
+			step.action = actionDelete
+			step.describe = fmt.Sprintf("delete issue %s", resource.Issue)
+			if !deleteCreatedIssues {
+				step.reason = "created issues are only deleted with delete_created_issues"
+				step.optional = true
+			}
+		default:
+			step.describe = fmt.Sprintf("remove %s", resource.Type)
+			step.reason = "unknown resource type"
+		}
+		steps = append(steps, step)
+	}
+
+	statusChanged := map[string]bool{}
+	for i, change := range record.Changes {
+		if change.Field == "status" {
+			statusChanged[change.Issue] = true
+			steps = append(steps, undoStep{
+				record:   record,
+				issue:    change.Issue,
+				action:   actionTransition,
+				describe: fmt.Sprintf("move back from %s to %s", change.After, change.Before),
+				change:   &record.Changes[i],
+			})
+		}
+	}
+
+	for i, change := range record.Changes {
+		step := undoStep{record: record, issue: change.Issue, action: actionEdit, change: &record.Changes[i]}
+
+		switch {
+		case change.Field == "status":
+			continue
+		case change.Field == "resolution" && statusChanged[change.Issue]:
+			step.describe = fmt.Sprintf("restore resolution to %s", describeValue(change.Before))
+			step.reason = "the resolution is set by the workflow when the status is reverted"
+		case change.Field == "assignee":
+			step.action = actionAssign
+			step.describe = fmt.Sprintf("assign back to %s", describeValue(change.Before))
+		case isListChange(change):
+			added, removed := diffListValues(change.BeforeValue, change.AfterValue)
+			step.describe = describeListUndo(change.Field, added, removed)
+		default:
+			step.describe = fmt.Sprintf("restore %s to %s", change.Field, describeValue(change.Before))
+		}
+		steps = append(steps, step)
+	}
+
+	if len(steps) == 0 {
+		step := undoStep{record: record, describe: fmt.Sprintf("revert %s", record.Tool), reason: "no reversible change was recorded for this call"}
+		if len(record.IssueKeys) > 0 {
+			step.issue = record.IssueKeys[0]
+		}
+		if record.Tool == "jira_delete_issue" {
+			step.describe = "restore the deleted issue"
+			step.reason = "deleted issues cannot be restored"
+		}
+		steps = append(steps, step)
+	}
+
+	if record.Tool == "jira_transition_issue" && record.Arguments["comment"] != nil && record.Arguments["comment"] != "" {
+		steps = append(steps, undoStep{
+			record:   record,
+			issue:    firstString(record.IssueKeys),
+			describe: "delete the comment added with the transition",
+			reason:   "Jira does not return the ID of a comment added with a transition, delete it by hand",
+		})
+	}
+
+	return steps
+}
+
+// apply runs the step and returns its result and the reason when it was not reverted
+func (s undoStep) apply(ctx context.Context, client *jira.Client) (string, string) {
+	if err := checkIssuePolicy(ctx, client, s.action, s.issue); err != nil {
+		return undoFailed, err.Error()
+	}
+
+	if s.resource != nil {
+		return s.removeResource(ctx, client)
+	}
+
+	current, err := getIssueFieldValues(ctx, client, s.issue, []string{s.change.Field})
+	if err != nil {
+		return undoFailed, err.Error()
+	}
+
+	change := *s.change
+	var response *models.ResponseScheme
+
+	switch {
+	case change.Field == "status":
+		if !strings.EqualFold(util.RenderFieldValue(current["status"]), change.After) {
+			return undoSkipped, fmt.Sprintf("the status changed since, it is now %s", util.RenderFieldValue(current["status"]))
+		}
+		if _, err := transitionToStatus(ctx, client, s.issue, change.Before, nil, ""); err != nil {
+			return undoFailed, err.Error()
+		}
+
+	case isListChange(change):
+		added, removed := diffListValues(change.BeforeValue, change.AfterValue)
+		var operations []map[string]interface{}
+		for _, value := range added {
+			operations = append(operations, map[string]interface{}{"remove": value})
+		}
+		for _, value := range removed {
+			operations = append(operations, map[string]interface{}{"add": value})
+		}
+		response, err = editIssueWithPayload(ctx, client, s.issue, map[string]interface{}{"update": map[string]interface{}{change.Field: operations}})
+
+	default:
+		if !sameFieldValue(current[change.Field], change.AfterValue) {
+			return undoSkipped, fmt.Sprintf("%s changed since, it is now %s", change.Field, describeValue(util.RenderFieldValue(current[change.Field])))
+		}
+
+		value := fieldUpdateValue(change.BeforeValue)
+		if change.Field == "assignee" {
+			var accountID *string
+			if user, ok := value.(map[string]interface{}); ok {
+				id, _ := user["accountId"].(string)
+				if id == "" {
+					// Server and Data Center reference users by username
+					id, _ = user["name"].(string)
+				}
+				accountID = &id
+			}
+			response, err = assignIssue(ctx, client, s.issue, accountID)
+		} else {
+			response, err = editIssueWithPayload(ctx, client, s.issue, map[string]interface{}{"fields": map[string]interface{}{change.Field: value}})
+		}
+	}
+
+	if err != nil {
+		if response != nil {
+			return undoFailed, response.Bytes.String()
+		}
+		return undoFailed, err.Error()
+	}
+
+	auditChanges(ctx, client, s.issue, current)
+	return undoReverted, ""
+}
+
+func (s undoStep) removeResource(ctx context.Context, client *jira.Client) (string, string) {
+	resource := *s.resource
+	var response *models.ResponseScheme
+	var err error
+
+	switch resource.Type {
+	case auditResourceComment:
+		response, err = deleteComment(ctx, client, resource.Issue, resource.ID)
+	case auditResourceWorklog:
+		response, err = deleteWorklog(ctx, client, resource.Issue, resource.ID)
+	case auditResourceIssue:
+		response, err = deleteIssue(ctx, client, resource.Issue, false)
+	case auditResourceLink:
+		linkID, findErr := findIssueLink(ctx, client, resource)
+		if findErr != nil {
+			return undoFailed, findErr.Error()
+		}
+		if linkID == "" {
+			return undoSkipped, "the link no longer exists"
+		}
+		response, err = deleteIssueLink(ctx, client, linkID)
+	}
+
+	auditRequest(ctx, response, resource.Issue)
+	if err != nil {
+		if response != nil && response.Code == 404 {
+			return undoSkipped, fmt.Sprintf("the %s no longer exists", resource.Type)
+		}
+		if response != nil {
+			return undoFailed, response.Bytes.String()
+		}
+		return undoFailed, err.Error()
+	}
+	return undoReverted, ""
+}
+
+// findIssueLink finds the ID of a recorded link. Seen from its inward issue, a link lists
+// the other issue as its outward issue.
+func findIssueLink(ctx context.Context, client *jira.Client, resource AuditResource) (string, error) {
+	links, response, err := getIssueLinks(ctx, client, resource.Issue)
+	if err != nil {
+		if response != nil {
+			return "", fmt.Errorf("failed to get links of %s: %s (endpoint: %s)", resource.Issue, response.Bytes.String(), response.Endpoint)
+		}
+		return "", fmt.Errorf("failed to get links of %s: %v", resource.Issue, err)
+	}
+	if links.Fields == nil {
+		return "", nil
+	}
+
+	for _, link := range links.Fields.IssueLinks {
+		if link.OutwardIssue == nil || link.OutwardIssue.Key != resource.Target || link.Type == nil {
+			continue
+		}
+		if strings.EqualFold(link.Type.Name, resource.LinkType) || strings.EqualFold(link.Type.Outward, resource.LinkType) {
+			return link.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// isListChange reports whether a change is to a list field such as labels or fix versions,
+// which is reverted by removing and adding values rather than by overwriting the list
+func isListChange(change AuditChange) bool {
+	isList := func(raw json.RawMessage) bool {
+		trimmed := strings.TrimSpace(string(raw))
+		return strings.HasPrefix(trimmed, "[")
+	}
+	isEmpty := func(raw json.RawMessage) bool {
+		trimmed := strings.TrimSpace(string(raw))
+		return trimmed == "" || trimmed == "null"
+	}
+	return (isList(change.BeforeValue) || isEmpty(change.BeforeValue)) &&
+		(isList(change.AfterValue) || isEmpty(change.AfterValue)) &&
+		(isList(change.BeforeValue) || isList(change.AfterValue))
+}
+
+// diffListValues returns the values a change added to and removed from a list field, in
+// the form the update operations expect
+func diffListValues(before, after json.RawMessage) (added, removed []interface{}) {
+	var beforeValues, afterValues []json.RawMessage
+	json.Unmarshal(before, &beforeValues)
+	json.Unmarshal(after, &afterValues)
+
+	identities := func(values []json.RawMessage) map[string]bool {
+		ids := map[string]bool{}
+		for _, value := range values {
+			ids[listValueIdentity(value)] = true
+		}
+		return ids
+	}
+	beforeIDs, afterIDs := identities(beforeValues), identities(afterValues)
+
+	for _, value := range afterValues {
+		if !beforeIDs[listValueIdentity(value)] {
+			added = append(added, fieldUpdateValue(value))
+		}
+	}
+	for _, value := range beforeValues {
+		if !afterIDs[listValueIdentity(value)] {
+			removed = append(removed, fieldUpdateValue(value))
+		}
+	}
+	return added, removed
+}
+
+func listValueIdentity(raw json.RawMessage) string {
+	encoded, _ := json.Marshal(fieldUpdateValue(raw))
+	return string(encoded)
+}
+
+// fieldUpdateValue turns a field value read from Jira into the value an edit accepts:
+// users by account ID, options, versions and other entities by ID, documents unchanged
+func fieldUpdateValue(raw json.RawMessage) interface{} {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil
+	}
+	return reduceFieldValue(value)
+}
+
+func reduceFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		reduced := make([]interface{}, len(v))
+		for i, item := range v {
+			reduced[i] = reduceFieldValue(item)
+		}
+		return reduced
+	case map[string]interface{}:
+		if v["type"] == "doc" {
+			return v
+		}
+		if accountID, ok := v["accountId"]; ok {
+			return map[string]interface{}{"accountId": accountID}
+		}
+		if _, isUser := v["displayName"]; isUser && v["name"] != nil {
+			// A user on Server and Data Center, which have no account IDs
+			return map[string]interface{}{"name": v["name"]}
+		}
+		if id, ok := v["id"]; ok {
+			reduced := map[string]interface{}{"id": id}
+			if child, ok := v["child"]; ok {
+				reduced["child"] = reduceFieldValue(child)
+			}
+			return reduced
+		}
+		for _, key := range []string{"key", "name", "value"} {
+			if name, ok := v[key]; ok {
+				return map[string]interface{}{key: name}
+			}
+		}
+	}
+	return value
+}
+
+// sameFieldValue compares field values the way an edit would set them, ignoring
+// presentation details such as avatar URLs
+func sameFieldValue(a, b json.RawMessage) bool {
+	encodedA, _ := json.Marshal(fieldUpdateValue(a))
+	encodedB, _ := json.Marshal(fieldUpdateValue(b))
+	return string(encodedA) == string(encodedB)
+}
+
+func describeListUndo(field string, added, removed []interface{}) string {
+	var parts []string
+	if len(added) > 0 {
+		parts = append(parts, fmt.Sprintf("remove %s %s", field, describeListValues(added)))
+	}
+	if len(removed) > 0 {
+		parts = append(parts, fmt.Sprintf("add %s %s", field, describeListValues(removed)))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("restore %s", field)
+	}
+	return strings.Join(parts, " and ")
+}
+
+func describeListValues(values []interface{}) string {
+	var names []string
+	for _, value := range values {
+		encoded, _ := json.Marshal(value)
+		names = append(names, util.RenderFieldValue(encoded))
+	}
+	return strings.Join(names, ", ")
+}
+
+// describeValue quotes a rendered value for a step description, shortening long text
+func describeValue(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	if len([]rune(value)) > 60 {
+		value = string([]rune(value)[:57]) + "..."
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+func firstString(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func formatUndo(output UndoOutput) string {
+	var result strings.Builder
+
+	if output.DryRun {
+		result.WriteString(fmt.Sprintf("Preview: reverting %d recorded calls, nothing was changed. Call again with apply=true to run these steps:\n\n", len(output.Records)))
+	} else {
+		result.WriteString(fmt.Sprintf("Reverted %d steps, skipped %d, failed %d\n\n", output.Reverted, output.Skipped, output.Failed))
+	}
+
+	record := ""
+	for _, step := range output.Steps {
+		if step.RecordID != record {
+			record = step.RecordID
+			result.WriteString(fmt.Sprintf("%s at %s (record %s):\n", step.Tool, step.Time, step.RecordID))
+		}
+		line := fmt.Sprintf("- [%s] %s", step.Result, step.Action)
+		if step.Issue != "" {
+			line = fmt.Sprintf("- [%s] %s: %s", step.Result, step.Issue, step.Action)
+		}
+		if step.Reason != "" {
+			line += " (" + step.Reason + ")"
+		}
+		result.WriteString(line + "\n")
+	}
+
+	return result.String()
+}
@@ -0,0 +1,341 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestUndoCandidates(t *testing.T) {
+	records := []AuditRecord{
+		{ID: "1", Tool: "jira_update_issue", Success: true, IssueKeys: []string{"KP-1"}, Changes: []AuditChange{{Issue: "KP-1", Field: "summary"}}},
+		{ID: "2", Tool: "jira_add_comment", Success: true, IssueKeys: []string{"KP-2"}, Created: []AuditResource{{Type: auditResourceComment, Issue: "KP-2", ID: "10"}}},
+		{ID: "3", Tool: "jira_update_issue", Error: "policy denied"},
+		{ID: "4", Tool: "jira_undo", Success: true, Undone: []string{"2"}},
+		{ID: "5", Tool: "jira_assign_issue", Success: true, IssueKeys: []string{"KP-1"}, Changes: []AuditChange{{Issue: "KP-1", Field: "assignee"}}},
+	}
+
+	ids := func(records []AuditRecord) []string {
+		var ids []string
+		for _, record := range records {
+			ids = append(ids, record.ID)
+		}
+		return ids
+	}
+
+	if got := ids(undoCandidates(records, 10, "", "")); !reflect.DeepEqual(got, []string{"5", "1"}) {
+		t.Errorf("candidates = %v, want [5 1]", got)
+	}
+	if got := ids(undoCandidates(records, 1, "", "")); !reflect.DeepEqual(got, []string{"5"}) {
+		t.Errorf("candidates = %v, want [5]", got)
+	}
+	if got := ids(undoCandidates(records, 10, "kp-2", "")); len(got) != 0 {
+		t.Errorf("undone records are candidates again: %v", got)
+	}
+}
+
+func TestRecentAuditRecordsReadsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	write := func(filename string, records ...AuditRecord) {
+		var lines []string
+		for _, record := range records {
+			line, _ := json.Marshal(record)
+			lines = append(lines, string(line))
+		}
+		lines = append(lines, `{"id": "cut`)
+		if err := os.WriteFile(filename, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	comment := func(id, issue string) AuditRecord {
+		return AuditRecord{ID: id, Tool: "jira_add_comment", Success: true, IssueKeys: []string{issue}, Created: []AuditResource{{Type: auditResourceComment, Issue: issue, ID: id}}}
+	}
+	write(path+".1", comment("1", "KP-1"), comment("2", "KP-2"))
+	write(path, AuditRecord{ID: "3", Tool: "jira_undo", Success: true, Undone: []string{"2"}}, comment("4", "KP-4"))
+
+	records, err := recentAuditRecords(AuditLogConfig{Path: path, MaxBackups: 3}, 5, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"4", "1"}) {
+		t.Errorf("records = %v, want [4 1]", ids)
+	}
+}
+
+func TestUndoOnlySeesTheCallersRecords(t *testing.T) {
+	path := openTestAuditLog(t, AuditLogConfig{})
+	mutatingTools.Store("jira_test_comment", true)
+	defer mutatingTools.Delete("jira_test_comment")
+
+	handler := AuditMutations(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		issueKey := request.GetString("issue_key", "")
+		auditRequest(ctx, &models.ResponseScheme{Code: 201}, issueKey)
+		auditCreated(ctx, AuditResource{Type: auditResourceComment, Issue: issueKey, ID: "1"})
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	actorContext := userContexts(t)
+	alice, bob := actorContext("alice@example.com", "alice-token"), actorContext("bob@example.com", "bob-token")
+
+	comment := func(ctx context.Context, issueKey string) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "jira_test_comment"
+		request.Params.Arguments = map[string]interface{}{"issue_key": issueKey}
+		handler(ctx, request)
+	}
+	comment(alice, "KP-1")
+	comment(bob, "KP-2")
+	comment(alice, "KP-3")
+
+	written := readAuditRecords(t, path)
+	if len(written) != 3 || written[0].Actor != "account:alice" || written[1].Actor != "account:bob" {
+		t.Fatalf("records = %+v", written)
+	}
+
+	for ctx, want := range map[context.Context][]string{alice: {"KP-3", "KP-1"}, bob: {"KP-2"}, context.Background(): nil} {
+		actor, err := services.Actor(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records, err := recentAuditRecords(AuditLogConfig{Path: path}, 10, "", actor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var issues []string
+		for _, record := range records {
+			issues = append(issues, record.IssueKeys...)
+		}
+		if !reflect.DeepEqual(issues, want) {
+			t.Errorf("%q can undo %v, want %v", actor, issues, want)
+		}
+	}
+
+	// Claiming to be Alice without her token shows nothing, not even a preview
+	forged := actorContext("alice@example.com", "bob-token")
+	if _, err := jiraUndoHandler(forged, mcp.CallToolRequest{}, UndoInput{}); err == nil || !strings.Contains(err.Error(), "failed to verify your Jira credentials") {
+		t.Errorf("undo with forged credentials: %v, want a verification error", err)
+	}
+}
+
+// userContexts returns a function giving the context of an HTTP request carrying a user's email and
+// API token. The stub Jira behind it knows alice and bob, whose tokens are "alice-token" and "bob-token".
+func userContexts(t *testing.T) func(email, token string) context.Context {
+	t.Helper()
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		email, token, _ := r.BasicAuth()
+		name, _, _ := strings.Cut(email, "@")
+		if r.URL.Path != "/rest/api/3/myself" || token != name+"-token" {
+			http.Error(w, `{"errorMessages": ["Client must be authenticated to access this resource."]}`, http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"accountId": name, "emailAddress": email, "active": true})
+	}))
+	t.Cleanup(jiraServer.Close)
+
+	users := services.NewUserCredentials(services.UserCredentialsConfig{Host: jiraServer.URL, CacheSize: 10})
+	return func(email, token string) context.Context {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		r.Header.Set(services.HeaderAtlassianEmail, email)
+		r.Header.Set(services.HeaderAtlassianToken, token)
+		return users.HTTPContext(context.Background(), r)
+	}
+}
+
+func TestUndoKeepsRecordsWithStepsLeftForAnOption(t *testing.T) {
+	path := openTestAuditLog(t, AuditLogConfig{})
+	for _, name := range []string{"jira_test_create", "jira_undo"} {
+		mutatingTools.Store(name, true)
+		defer mutatingTools.Delete(name)
+	}
+
+	var deleted []string
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.NotFound(w, r)
+			return
+		}
+		deleted = append(deleted, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer jiraServer.Close()
+	ctx := stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+
+	// One call created an issue and commented on another, the next one only commented
+	create := AuditMutations(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		auditRequest(ctx, &models.ResponseScheme{Code: 201}, "KP-9")
+		auditCreated(ctx, AuditResource{Type: auditResourceIssue, Issue: "KP-9"})
+		auditCreated(ctx, AuditResource{Type: auditResourceComment, Issue: "KP-1", ID: "100"})
+		return mcp.NewToolResultText("ok"), nil
+	})
+	comment := AuditMutations(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		auditRequest(ctx, &models.ResponseScheme{Code: 201}, "KP-2")
+		auditCreated(ctx, AuditResource{Type: auditResourceComment, Issue: "KP-2", ID: "200"})
+		return mcp.NewToolResultText("ok"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "jira_test_create"
+	create(ctx, request)
+	comment(ctx, request)
+
+	undoRequest := mcp.CallToolRequest{}
+	undoRequest.Params.Name = "jira_undo"
+	undo := AuditMutations(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return jiraUndoHandler(ctx, request, UndoInput{Count: 2, Apply: true})
+	})
+	if _, err := undo(ctx, undoRequest); err != nil {
+		t.Fatal(err)
+	}
+
+	written := readAuditRecords(t, path)
+	createID, commentID := written[0].ID, written[1].ID
+	if undone := written[2].Undone; !reflect.DeepEqual(undone, []string{commentID}) {
+		t.Errorf("undone = %v, want only the comment call %s", undone, commentID)
+	}
+
+	// The created issue can still be deleted once the caller asks for it
+	actor, _ := services.Actor(ctx)
+	candidates, err := recentAuditRecords(AuditLogConfig{Path: path}, 10, "", actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != createID {
+		t.Fatalf("candidates = %+v, want the create call", candidates)
+	}
+	undo = AuditMutations(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return jiraUndoHandler(ctx, request, UndoInput{Apply: true, DeleteCreatedIssues: true})
+	})
+	if _, err := undo(ctx, undoRequest); err != nil {
+		t.Fatal(err)
+	}
+	if !containsString(deleted, "/rest/api/3/issue/KP-9") {
+		t.Errorf("deleted %v, want KP-9", deleted)
+	}
+	if written := readAuditRecords(t, path); !reflect.DeepEqual(written[len(written)-1].Undone, []string{createID}) {
+		t.Errorf("undone = %v, want the create call", written[len(written)-1].Undone)
+	}
+}
+
+func TestPlanUndo(t *testing.T) {
+	record := AuditRecord{
+		ID:        "1",
+		Tool:      "jira_transition_issue",
+		Success:   true,
+		IssueKeys: []string{"KP-1"},
+		Arguments: map[string]interface{}{"comment": "Done!"},
+		Changes: []AuditChange{
+			{Issue: "KP-1", Field: "resolution", Before: "", After: "Done", BeforeValue: json.RawMessage(`null`), AfterValue: json.RawMessage(`{"id":"1","name":"Done"}`)},
+			{Issue: "KP-1", Field: "status", Before: "In Progress", After: "Done", BeforeValue: json.RawMessage(`{"name":"In Progress"}`), AfterValue: json.RawMessage(`{"name":"Done"}`)},
+		},
+	}
+
+	steps := planUndo(record, false)
+	var described []string
+	for _, step := range steps {
+		output := step.output()
+		described = append(described, output.Result+": "+output.Action)
+	}
+	want := []string{
+		"planned: move back from Done to In Progress",
+		`skipped: restore resolution to (empty)`,
+		"skipped: delete the comment added with the transition",
+	}
+	if !reflect.DeepEqual(described, want) {
+		t.Errorf("steps = %q, want %q", described, want)
+	}
+
+	created := AuditRecord{ID: "2", Tool: "jira_bulk_create_issues", Success: true, Created: []AuditResource{
+		{Type: auditResourceIssue, Issue: "KP-5"},
+		{Type: auditResourceLink, Issue: "KP-5", Target: "KP-1", LinkType: "Blocks"},
+	}}
+	steps = planUndo(created, false)
+	if len(steps) != 2 || steps[0].describe != "delete Blocks link KP-5 -> KP-1" || steps[1].reason == "" {
+		t.Errorf("steps = %+v", steps)
+	}
+	if steps = planUndo(created, true); steps[1].reason != "" || steps[1].action != actionDelete {
+		t.Errorf("created issue with delete_created_issues: %+v", steps[1])
+	}
+
+	deleted := planUndo(AuditRecord{ID: "3", Tool: "jira_delete_issue", Success: true, IssueKeys: []string{"KP-9"}}, false)
+	if len(deleted) != 1 || deleted[0].reason != "deleted issues cannot be restored" {
+		t.Errorf("delete record: %+v", deleted)
+	}
+}
+
+func TestUndoListChanges(t *testing.T) {
+	change := AuditChange{
+		Field:       "labels",
+		BeforeValue: json.RawMessage(`["backend","urgent"]`),
+		AfterValue:  json.RawMessage(`["backend","triaged","frontend"]`),
+	}
+	if !isListChange(change) {
+		t.Fatal("labels change is not a list change")
+	}
+
+	added, removed := diffListValues(change.BeforeValue, change.AfterValue)
+	if !reflect.DeepEqual(added, []interface{}{"triaged", "frontend"}) || !reflect.DeepEqual(removed, []interface{}{"urgent"}) {
+		t.Errorf("added = %v, removed = %v", added, removed)
+	}
+	if got := describeListUndo("labels", added, removed); got != "remove labels triaged, frontend and add labels urgent" {
+		t.Errorf("describeListUndo() = %q", got)
+	}
+
+	versions := AuditChange{BeforeValue: json.RawMessage(`null`), AfterValue: json.RawMessage(`[{"id":"100","name":"1.0","self":"https://x"}]`)}
+	added, removed = diffListValues(versions.BeforeValue, versions.AfterValue)
+	if !isListChange(versions) || !reflect.DeepEqual(added, []interface{}{map[string]interface{}{"id": "100"}}) || removed != nil {
+		t.Errorf("versions: added = %v, removed = %v", added, removed)
+	}
+
+	if isListChange(AuditChange{BeforeValue: json.RawMessage(`"old"`), AfterValue: json.RawMessage(`"new"`)}) {
+		t.Error("a summary change is not a list change")
+	}
+}
+
+func TestFieldUpdateValue(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`null`, `null`},
+		{`"Old summary"`, `"Old summary"`},
+		{`3`, `3`},
+		{`{"accountId":"abc","displayName":"Alice","avatarUrls":{}}`, `{"accountId":"abc"}`},
+		{`{"self":"https://x","id":"3","name":"High"}`, `{"id":"3"}`},
+		{`{"id":"10","value":"A","child":{"id":"11","value":"B"}}`, `{"child":{"id":"11"},"id":"10"}`},
+		{`{"type":"doc","version":1,"content":[]}`, `{"content":[],"type":"doc","version":1}`},
+	}
+	for _, tt := range tests {
+		encoded, _ := json.Marshal(fieldUpdateValue(json.RawMessage(tt.raw)))
+		if string(encoded) != tt.want {
+			t.Errorf("fieldUpdateValue(%s) = %s, want %s", tt.raw, encoded, tt.want)
+		}
+	}
+
+	if !sameFieldValue(json.RawMessage(`{"accountId":"abc","displayName":"Alice"}`), json.RawMessage(`{"accountId":"abc","displayName":"Alice B."}`)) {
+		t.Error("users with the same account ID differ")
+	}
+}
+
+func TestFormatUndo(t *testing.T) {
+	output := UndoOutput{DryRun: true, Records: []string{"1"}, Steps: []UndoStepOutput{
+		{RecordID: "1", Tool: "jira_update_issue", Time: "2026-01-01T00:00:00Z", Issue: "KP-1", Action: `restore summary to "Old"`, Result: undoPlanned},
+	}}
+	text := formatUndo(output)
+	if !strings.Contains(text, "Preview") || !strings.Contains(text, `- [planned] KP-1: restore summary to "Old"`) {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+}
@@ -0,0 +1,458 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// Input types for typed tools
+type AssignIssueInput struct {
+	IssueKey string `json:"issue_key" validate:"required"`
+	Assignee string `json:"assignee" validate:"required"`
+	OutputFormatInput
+}
+
+type GetMyselfInput struct {
+	OutputFormatInput
+}
+
+type SearchUsersInput struct {
+	Query           string `json:"query" validate:"required"`
+	MaxResults      int    `json:"max_results,omitempty"`
+	IncludeInactive bool   `json:"include_inactive,omitempty"`
+	OutputFormatInput
+}
+
+type ListAssignableUsersInput struct {
+	ProjectKey string `json:"project_key,omitempty"`
+	IssueKey   string `json:"issue_key,omitempty"`
+	Query      string `json:"query,omitempty"`
+	OutputFormatInput
+}
+
+// UserDetailsOutput is a user in the results of the user tools
+type UserDetailsOutput struct {
+	AccountID    string `json:"account_id"`
+	Username     string `json:"username,omitempty" jsonschema_description:"Only on Jira Server and Data Center, which reference users by username instead of account ID"`
+	DisplayName  string `json:"display_name"`
+	Email        string `json:"email,omitempty"`
+	EmailVisible bool   `json:"email_visible" jsonschema_description:"False when the user's profile visibility settings hide their email"`
+	TimeZone     string `json:"time_zone,omitempty"`
+	Locale       string `json:"locale,omitempty"`
+	Active       bool   `json:"active"`
+	AccountType  string `json:"account_type,omitempty" jsonschema_description:"atlassian for people, app or customer for other accounts"`
+}
+
+// ListUsersOutput is the result of jira_search_users and jira_list_assignable_users
+type ListUsersOutput struct {
+	Users []UserDetailsOutput `json:"users"`
+}
+
+// AssignIssueOutput is the result of jira_assign_issue
+type AssignIssueOutput struct {
+	IssueKey   string       `json:"issue_key"`
+	Assigned   bool         `json:"assigned" jsonschema_description:"False when the assignee was ambiguous and nothing was changed"`
+	Assignee   *UserOutput  `json:"assignee,omitempty" jsonschema_description:"The new assignee, absent when the issue was unassigned"`
+	Candidates []UserOutput `json:"candidates,omitempty" jsonschema_description:"Assignable users matching an ambiguous assignee, retry with one of their account IDs"`
+}
+
+const (
+	defaultUserSearchResults = 20
+	maxUserSearchResults     = 100
+)
+
+func RegisterJiraUserTool(s *server.MCPServer) {
+	jiraGetMyselfTool := mcp.NewTool("jira_get_myself",
+		readOnlyTool(),
+		mcp.WithDescription("Get the authenticated user (the account of ATLASSIAN_EMAIL). This is the user that 'me' refers to in other tools and currentUser() refers to in JQL"),
+		withOutputFormat[UserDetailsOutput](),
+	)
+	addTool(s, jiraGetMyselfTool, mcp.NewTypedToolHandler(jiraGetMyselfHandler))
+
+	jiraSearchUsersTool := mcp.NewTool("jira_search_users",
+		readOnlyTool(),
+		mcp.WithDescription("Search Jira users by display name or email prefix. Returns account IDs, which other tools accept to name people"),
+		mcp.WithString("query", mcp.Required(), mcp.Description("Text matched against display names and email addresses (e.g., 'jane' or 'jane@example.com')")),
+		mcp.WithNumber("max_results", mcp.Description("Maximum number of users to return (default: 20, max: 100)")),
+		mcp.WithBoolean("include_inactive", mcp.Description("If true, also return deactivated users and app accounts")),
+		withOutputFormat[ListUsersOutput](),
+	)
+	addTool(s, jiraSearchUsersTool, mcp.NewTypedToolHandler(jiraSearchUsersHandler))
+
+	jiraListAssignableUsersTool := mcp.NewTool("jira_list_assignable_users",
+		readOnlyTool(),
+		mcp.WithDescription("List users who can be assigned issues in a project, or a specific issue. Requires either project_key or issue_key."),
+		mcp.WithString("project_key", mcp.Description("Project to list assignable users for (e.g., KP). Optional if issue_key is provided.")),
+		mcp.WithString("issue_key", mcp.Description("Issue to list assignable users for (e.g., KP-123). Optional if project_key is provided.")),
+		mcp.WithString("query", mcp.Description("Only return users whose display name or email starts with this text")),
+		withOutputFormat[ListUsersOutput](),
+	)
+	addTool(s, jiraListAssignableUsersTool, mcp.NewTypedToolHandler(jiraListAssignableUsersHandler))
+
+	jiraAssignIssueTool := mcp.NewTool("jira_assign_issue",
+		destructiveTool(true),
+		mcp.WithDescription("Assign or unassign a Jira issue. The assignee can be an email, a display name, an account ID, 'me' or 'unassigned'. Only users assignable to the issue are considered; when several users match, they are returned as candidates and the issue is left unchanged"),
+		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The issue to assign (e.g., KP-123)")),
+		mcp.WithString("assignee", mcp.Required(), mcp.Description("Email, display name, account ID, 'me' for the authenticated user, or 'unassigned' to clear the assignee")),
+		withOutputFormat[AssignIssueOutput](),
+	)
+	addTool(s, jiraAssignIssueTool, mcp.NewTypedToolHandler(jiraAssignIssueHandler))
+}
+
+func jiraAssignIssueHandler(ctx context.Context, request mcp.CallToolRequest, input AssignIssueInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	if err := checkIssuePolicy(ctx, client, actionAssign, input.IssueKey); err != nil {
+		return nil, err
+	}
+
+	output := AssignIssueOutput{IssueKey: input.IssueKey}
+
+	if isUnassignedValue(input.Assignee) {
+		before := auditSnapshot(ctx, client, input.IssueKey, []string{"assignee"})
+		response, err := assignIssue(ctx, client, input.IssueKey, nil)
+		if err != nil {
+			if response != nil {
+				return nil, fmt.Errorf("failed to unassign issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return nil, fmt.Errorf("failed to unassign issue: %v", err)
+		}
+		auditChanges(ctx, client, input.IssueKey, before)
+
+		output.Assigned = true
+		return formatResult(input.OutputFormat, output, fmt.Sprintf("Issue %s is now unassigned", input.IssueKey))
+	}
+
+	user, candidates, err := resolveAssignableUser(ctx, client, input.IssueKey, input.Assignee)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		var result strings.Builder
+		result.WriteString(fmt.Sprintf("%q matches %d assignable users, the issue was not changed. Retry with one of these account IDs or emails:\n", input.Assignee, len(candidates)))
+		for _, candidate := range candidates {
+			output.Candidates = append(output.Candidates, *newUserOutput(candidate))
+			result.WriteString(fmt.Sprintf("- %s\n", formatUser(candidate)))
+		}
+		return formatResult(input.OutputFormat, output, result.String())
+	}
+
+	before := auditSnapshot(ctx, client, input.IssueKey, []string{"assignee"})
+	id := userID(user)
+	response, err := assignIssue(ctx, client, input.IssueKey, &id)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to assign issue: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to assign issue: %v", err)
+	}
+	auditChanges(ctx, client, input.IssueKey, before)
+
+	output.Assigned = true
+	output.Assignee = newUserOutput(user)
+	return formatResult(input.OutputFormat, output, fmt.Sprintf("Issue %s assigned to %s", input.IssueKey, formatUser(user)))
+}
+
+func jiraGetMyselfHandler(ctx context.Context, request mcp.CallToolRequest, input GetMyselfInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	myself, response, err := client.MySelf.Details(ctx, nil)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to get current user: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to get current user: %v", err)
+	}
+
+	result := "Authenticated User:\n" + formatUserDetails(myself) +
+		"\nUse 'me' in tools that take a person, or currentUser() in JQL (e.g., assignee = currentUser()), to refer to this user."
+
+	return formatResult(input.OutputFormat, newUserDetailsOutput(myself), result)
+}
+
+func jiraSearchUsersHandler(ctx context.Context, request mcp.CallToolRequest, input SearchUsersInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	maxResults := input.MaxResults
+	if maxResults <= 0 {
+		maxResults = defaultUserSearchResults
+	}
+	if maxResults > maxUserSearchResults {
+		maxResults = maxUserSearchResults
+	}
+
+	users, response, err := searchUsers(ctx, client, input.Query, maxResults)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to search users: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to search users: %v", err)
+	}
+
+	if !input.IncludeInactive {
+		users = activeUsers(users)
+	}
+
+	return formatUserList(input.OutputFormat, users, fmt.Sprintf("No users found matching %q.", input.Query))
+}
+
+func jiraListAssignableUsersHandler(ctx context.Context, request mcp.CallToolRequest, input ListAssignableUsersInput) (*mcp.CallToolResult, error) {
+	client := services.JiraClientFor(ctx)
+
+	params := url.Values{}
+	switch {
+	case input.IssueKey != "":
+		params.Set("issueKey", input.IssueKey)
+	case input.ProjectKey != "":
+		params.Set("project", input.ProjectKey)
+	default:
+		return nil, fmt.Errorf("either project_key or issue_key argument is required")
+	}
+
+	if input.Query != "" {
+		params.Set("query", input.Query)
+	}
+
+	users, err := findAssignableUsers(ctx, client, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatUserList(input.OutputFormat, users, "No assignable users found.")
+}
+
+// formatUserList renders the users of a search, emptyText is used when there are none
+func formatUserList(format string, users []*models.UserScheme, emptyText string) (*mcp.CallToolResult, error) {
+	output := ListUsersOutput{Users: []UserDetailsOutput{}}
+	if len(users) == 0 {
+		return formatResult(format, output, emptyText)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Users (%d):\n", len(users)))
+	for _, user := range users {
+		output.Users = append(output.Users, newUserDetailsOutput(user))
+		result.WriteString("\n" + formatUserDetails(user))
+	}
+
+	return formatResult(format, output, result.String())
+}
+
+func newUserDetailsOutput(user *models.UserScheme) UserDetailsOutput {
+	return UserDetailsOutput{
+		AccountID:    user.AccountID,
+		Username:     user.Name,
+		DisplayName:  user.DisplayName,
+		Email:        user.EmailAddress,
+		EmailVisible: user.EmailAddress != "",
+		TimeZone:     user.TimeZone,
+		Locale:       user.Locale,
+		Active:       user.Active,
+		AccountType:  user.AccountType,
+	}
+}
+
+func formatUserDetails(user *models.UserScheme) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Display Name: %s\n", user.DisplayName))
+	if user.AccountID != "" {
+		sb.WriteString(fmt.Sprintf("Account ID: %s\n", user.AccountID))
+	} else {
+		sb.WriteString(fmt.Sprintf("Username: %s\n", user.Name))
+	}
+	if user.EmailAddress != "" {
+		sb.WriteString(fmt.Sprintf("Email: %s\n", user.EmailAddress))
+	} else {
+		sb.WriteString("Email: hidden by the user's profile visibility settings\n")
+	}
+	if user.TimeZone != "" {
+		sb.WriteString(fmt.Sprintf("Time Zone: %s\n", user.TimeZone))
+	}
+	sb.WriteString(fmt.Sprintf("Active: %t\n", user.Active))
+	if user.AccountType != "" && user.AccountType != "atlassian" {
+		sb.WriteString(fmt.Sprintf("Account Type: %s\n", user.AccountType))
+	}
+	return sb.String()
+}
+
+// assignIssue sets the assignee of an issue, a nil accountID unassigns it. On Server and Data Center
+// accountID is the username. client.Issue.Assign cannot send the null account ID needed to unassign.
+func assignIssue(ctx context.Context, client *jira.Client, issueKey string, accountID *string) (*models.ResponseScheme, error) {
+	body := map[string]interface{}{"accountId": accountID}
+	if isServer(ctx) {
+		body = map[string]interface{}{"name": accountID}
+	}
+
+	endpoint := restAPI(ctx, fmt.Sprintf("issue/%s/assignee", issueKey))
+	req, err := client.NewRequest(ctx, http.MethodPut, endpoint, "", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	response, err := client.Call(req, nil)
+	auditRequest(ctx, response, issueKey)
+	return response, err
+}
+
+// accountIDPattern matches Atlassian account IDs, either 24 hex characters or the
+// "<number>:<uuid>" form, so they are never sent to the user search as a name.
+var accountIDPattern = regexp.MustCompile(`^([0-9a-f]{24}|\d+:[0-9a-f-]{36})$`)
+
+// resolveAssignableUser resolves "me", an account ID, an email or a display name to a user
+// that can be assigned to the issue. When several users match equally well, the user is nil
+// and the candidates are returned instead.
+func resolveAssignableUser(ctx context.Context, client *jira.Client, issueKey, value string) (*models.UserScheme, []*models.UserScheme, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.EqualFold(value, "me") {
+		myself, response, err := client.MySelf.Details(ctx, nil)
+		if err != nil {
+			if response != nil {
+				return nil, nil, fmt.Errorf("failed to get current user: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+			}
+			return nil, nil, fmt.Errorf("failed to get current user: %v", err)
+		}
+		value = userID(myself)
+	}
+
+	if accountIDPattern.MatchString(value) && !isServer(ctx) {
+		users, err := findAssignableUsers(ctx, client, url.Values{"issueKey": {issueKey}, "accountId": {value}})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(users) == 0 {
+			return nil, nil, fmt.Errorf("user %s cannot be assigned to %s: they are unknown or lack the Assignable User permission in the project", value, issueKey)
+		}
+		return users[0], nil, nil
+	}
+
+	users, err := findAssignableUsers(ctx, client, url.Values{"issueKey": {issueKey}, "query": {value}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(users) == 0 {
+		// Tell apart unknown people from people who exist but cannot be assigned
+		others, _, searchErr := searchUsers(ctx, client, value, 10)
+		if searchErr == nil && len(activeUsers(others)) > 0 {
+			var names []string
+			for _, user := range activeUsers(others) {
+				names = append(names, formatUser(user))
+			}
+			return nil, nil, fmt.Errorf("no user matching %q can be assigned to %s, these users match but lack the Assignable User permission in the project: %s", value, issueKey, strings.Join(names, "; "))
+		}
+		return nil, nil, fmt.Errorf("no user found matching %q", value)
+	}
+
+	user, candidates := pickUser(users, value)
+	return user, candidates, nil
+}
+
+// pickUser chooses the user matching value by email or username, then by display name, case-insensitively.
+// A single search result is accepted as is; anything else is ambiguous and returned as candidates.
+func pickUser(users []*models.UserScheme, value string) (*models.UserScheme, []*models.UserScheme) {
+	for _, user := range users {
+		if user.EmailAddress != "" && strings.EqualFold(user.EmailAddress, value) {
+			return user, nil
+		}
+		if user.Name != "" && strings.EqualFold(user.Name, value) {
+			return user, nil
+		}
+	}
+
+	var exact []*models.UserScheme
+	for _, user := range users {
+		if strings.EqualFold(user.DisplayName, value) {
+			exact = append(exact, user)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0], nil
+	}
+	if len(exact) > 1 {
+		return nil, exact
+	}
+
+	if len(users) == 1 {
+		return users[0], nil
+	}
+	return nil, users
+}
+
+// findAssignableUsers returns the active users assignable to the issueKey or project in params,
+// optionally filtered by query or accountId. The library has no binding for /user/assignable/search.
+func findAssignableUsers(ctx context.Context, client *jira.Client, params url.Values) ([]*models.UserScheme, error) {
+	params.Set("maxResults", "50")
+	if isServer(ctx) && params.Has("query") {
+		// Server and Data Center match the username, name and email given as username
+		params.Set("username", params.Get("query"))
+		params.Del("query")
+	}
+
+	endpoint := restAPI(ctx, fmt.Sprintf("user/assignable/search?%s", params.Encode()))
+	req, err := client.NewRequest(ctx, http.MethodGet, endpoint, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var users []*models.UserScheme
+	response, err := client.Call(req, &users)
+	if err != nil {
+		if response != nil {
+			return nil, fmt.Errorf("failed to search assignable users: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
+		}
+		return nil, fmt.Errorf("failed to search assignable users: %v", err)
+	}
+
+	return activeUsers(users), nil
+}
+
+// searchUsers finds users by name or email. client.User.Search sends the query parameter
+// of Cloud, Server and Data Center take it as username.
+func searchUsers(ctx context.Context, client *jira.Client, query string, maxResults int) ([]*models.UserScheme, *models.ResponseScheme, error) {
+	if !isServer(ctx) {
+		return client.User.Search.Do(ctx, "", query, 0, maxResults)
+	}
+
+	params := url.Values{"username": {query}, "maxResults": {strconv.Itoa(maxResults)}}
+	req, err := client.NewRequest(ctx, http.MethodGet, "rest/api/2/user/search?"+params.Encode(), "", nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var users []*models.UserScheme
+	response, err := client.Call(req, &users)
+	return users, response, err
+}
+
+// activeUsers drops deactivated accounts and app users, which cannot own work
+func activeUsers(users []*models.UserScheme) []*models.UserScheme {
+	var active []*models.UserScheme
+	for _, user := range users {
+		if user.Active && (user.AccountType == "" || user.AccountType == "atlassian") {
+			active = append(active, user)
+		}
+	}
+	return active
+}
+
+// formatUser renders a user as "Name <email> (accountId)", the email only when visible.
+// Server and Data Center users show their username instead of an account ID.
+func formatUser(user *models.UserScheme) string {
+	if user.EmailAddress != "" {
+		return fmt.Sprintf("%s <%s> (%s)", user.DisplayName, user.EmailAddress, userID(user))
+	}
+	return fmt.Sprintf("%s (%s)", user.DisplayName, userID(user))
+}
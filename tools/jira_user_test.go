@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+func TestPickUser(t *testing.T) {
+	jane := &models.UserScheme{AccountID: "1", DisplayName: "Jane Doe", EmailAddress: "jane@example.com"}
+	janet := &models.UserScheme{AccountID: "2", DisplayName: "Janet Roe"}
+	otherJane := &models.UserScheme{AccountID: "3", DisplayName: "Jane Doe"}
+	serverJane := &models.UserScheme{Name: "jdoe", DisplayName: "Jane Doe"}
+
+	tests := []struct {
+		name           string
+		users          []*models.UserScheme
+		value          string
+		want           *models.UserScheme
+		wantCandidates int
+	}{
+		{"exact email", []*models.UserScheme{janet, jane}, "JANE@example.com", jane, 0},
+		{"email before display name", []*models.UserScheme{otherJane, jane}, "jane@example.com", jane, 0},
+		{"server username", []*models.UserScheme{otherJane, serverJane}, "JDoe", serverJane, 0},
+		{"exact display name", []*models.UserScheme{jane, janet}, "janet roe", janet, 0},
+		{"single fuzzy match", []*models.UserScheme{janet}, "jan", janet, 0},
+		{"duplicate display names", []*models.UserScheme{jane, otherJane, janet}, "Jane Doe", nil, 2},
+		{"several fuzzy matches", []*models.UserScheme{jane, janet}, "jan", nil, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, candidates := pickUser(tt.users, tt.value)
+			if user != tt.want || len(candidates) != tt.wantCandidates {
+				t.Errorf("pickUser(%q) = %v, %d candidates, want %v, %d candidates", tt.value, user, len(candidates), tt.want, tt.wantCandidates)
+			}
+		})
+	}
+}
+
+func TestResolveAssignableUser(t *testing.T) {
+	const (
+		jane      = `{"accountId": "5b10a2844c20165700ede21f", "displayName": "Jane Doe", "emailAddress": "jane@example.com", "active": true, "accountType": "atlassian"}`
+		janet     = `{"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "Janet Roe", "active": true, "accountType": "atlassian"}`
+		inactive  = `{"accountId": "5b10ac8d82e05b22cc7d4ef6", "displayName": "Jan Old", "active": false, "accountType": "atlassian"}`
+		myselfRaw = `{"accountId": "5b10ac8d82e05b22cc7d4ef5", "displayName": "Janet Roe", "active": true}`
+	)
+
+	tests := []struct {
+		name           string
+		value          string
+		assignable     string
+		users          string
+		wantAccountID  string
+		wantCandidates int
+		wantErr        string
+		wantQuery      string
+	}{
+		{name: "exact email", value: "jane@example.com", assignable: "[" + janet + "," + jane + "]", wantAccountID: "5b10a2844c20165700ede21f", wantQuery: "query=jane%40example.com"},
+		{name: "single fuzzy match", value: "jan", assignable: "[" + janet + "," + inactive + "]", wantAccountID: "5b10ac8d82e05b22cc7d4ef5", wantQuery: "query=jan"},
+		{name: "several matches", value: "ja", assignable: "[" + jane + "," + janet + "]", wantCandidates: 2, wantQuery: "query=ja"},
+		{name: "account ID", value: "5b10ac8d82e05b22cc7d4ef5", assignable: "[" + janet + "]", wantAccountID: "5b10ac8d82e05b22cc7d4ef5", wantQuery: "accountId=5b10ac8d82e05b22cc7d4ef5"},
+		{name: "me", value: "me", assignable: "[" + janet + "]", wantAccountID: "5b10ac8d82e05b22cc7d4ef5", wantQuery: "accountId=5b10ac8d82e05b22cc7d4ef5"},
+		{name: "not assignable", value: "jane", assignable: "[]", users: "[" + jane + "]", wantErr: "lack the Assignable User permission in the project: Jane Doe <jane@example.com>"},
+		{name: "unknown", value: "nobody", assignable: "[]", users: "[]", wantErr: `no user found matching "nobody"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var assignableQuery string
+			jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/rest/api/3/user/assignable/search":
+					assignableQuery = r.URL.RawQuery
+					w.Write([]byte(tt.assignable))
+				case "/rest/api/3/user/search":
+					w.Write([]byte(tt.users))
+				case "/rest/api/3/myself":
+					w.Write([]byte(myselfRaw))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer jiraServer.Close()
+
+			ctx := stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+			user, candidates, err := resolveAssignableUser(ctx, services.JiraClientFor(ctx), "KP-1", tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantAccountID != "" && (user == nil || user.AccountID != tt.wantAccountID) {
+				t.Errorf("user = %+v, want %s", user, tt.wantAccountID)
+			}
+			if tt.wantAccountID == "" && user != nil {
+				t.Errorf("user = %+v, want none", user)
+			}
+			if len(candidates) != tt.wantCandidates {
+				t.Errorf("candidates = %d, want %d", len(candidates), tt.wantCandidates)
+			}
+			if !strings.Contains(assignableQuery, tt.wantQuery) || !strings.Contains(assignableQuery, "issueKey=KP-1") {
+				t.Errorf("assignable search query = %s, want %s", assignableQuery, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestAssignIssueUnassign(t *testing.T) {
+	for _, value := range []string{"unassigned", "None", "-1"} {
+		t.Run(value, func(t *testing.T) {
+			var body map[string]interface{}
+			searched := false
+			jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPut && r.URL.Path == "/rest/api/3/issue/KP-1/assignee":
+					json.NewDecoder(r.Body).Decode(&body)
+					w.WriteHeader(http.StatusNoContent)
+				case strings.HasPrefix(r.URL.Path, "/rest/api/3/user"):
+					searched = true
+					http.NotFound(w, r)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer jiraServer.Close()
+
+			ctx := stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+			if _, err := jiraAssignIssueHandler(ctx, mcp.CallToolRequest{}, AssignIssueInput{IssueKey: "KP-1", Assignee: value}); err != nil {
+				t.Fatal(err)
+			}
+			if accountID, ok := body["accountId"]; !ok || accountID != nil || len(body) != 1 {
+				t.Errorf("assign body = %v, want a null accountId", body)
+			}
+			if searched {
+				t.Error("unassigning searched for a user")
+			}
+		})
+	}
+}
+
+func TestAccountIDPattern(t *testing.T) {
+	for _, value := range []string{"5b10a2844c20165700ede21f", "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077"} {
+		if !accountIDPattern.MatchString(value) {
+			t.Errorf("%q should be an account ID", value)
+		}
+	}
+	for _, value := range []string{"jane@example.com", "Jane Doe", "me"} {
+		if accountIDPattern.MatchString(value) {
+			t.Errorf("%q should not be an account ID", value)
+		}
+	}
+}
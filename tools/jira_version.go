@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/nguyenvanduocit/jira-mcp/services"
@@ -13,28 +14,53 @@ import (
 // Input types for version tools
 type GetVersionInput struct {
 	VersionID string `json:"version_id" validate:"required"`
+	OutputFormatInput
 }
 
 type ListProjectVersionsInput struct {
 	ProjectKey string `json:"project_key" validate:"required"`
+	OutputFormatInput
+}
+
+// VersionOutput is a project version
+type VersionOutput struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ProjectID   int    `json:"project_id,omitempty"`
+	Status      string `json:"status" jsonschema:"enum=In Development,enum=Released,enum=Archived"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// ListProjectVersionsOutput is the result of jira_list_project_versions
+type ListProjectVersionsOutput struct {
+	ProjectKey string          `json:"project_key"`
+	Versions   []VersionOutput `json:"versions"`
 }
 
 func RegisterJiraVersionTool(s *server.MCPServer) {
 	jiraGetVersionTool := mcp.NewTool("jira_get_version",
+		readOnlyTool(),
 		mcp.WithDescription("Retrieve detailed information about a specific Jira project version including its name, description, release date, and status"),
 		mcp.WithString("version_id", mcp.Required(), mcp.Description("The unique identifier of the version to retrieve (e.g., 10000)")),
+		withOutputFormat[VersionOutput](),
 	)
-	s.AddTool(jiraGetVersionTool, mcp.NewTypedToolHandler(jiraGetVersionHandler))
+	addTool(s, jiraGetVersionTool, mcp.NewTypedToolHandler(jiraGetVersionHandler))
 
 	jiraListProjectVersionsTool := mcp.NewTool("jira_list_project_versions",
+		readOnlyTool(),
 		mcp.WithDescription("List all versions in a Jira project with their details including names, descriptions, release dates, and statuses"),
 		mcp.WithString("project_key", mcp.Required(), mcp.Description("Project identifier to list versions for (e.g., KP, PROJ)")),
+		withOutputFormat[ListProjectVersionsOutput](),
 	)
-	s.AddTool(jiraListProjectVersionsTool, mcp.NewTypedToolHandler(jiraListProjectVersionsHandler))
+	addTool(s, jiraListProjectVersionsTool, mcp.NewTypedToolHandler(jiraListProjectVersionsHandler))
 }
 
 func jiraGetVersionHandler(ctx context.Context, request mcp.CallToolRequest, input GetVersionInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	version, response, err := client.Project.Version.Get(ctx, input.VersionID, nil)
 	if err != nil {
@@ -68,11 +94,11 @@ func jiraGetVersionHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		result.WriteString(fmt.Sprintf("URL: %s\n", version.Self))
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return formatResult(input.OutputFormat, newVersionOutput(version), result.String())
 }
 
 func jiraListProjectVersionsHandler(ctx context.Context, request mcp.CallToolRequest, input ListProjectVersionsInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
 
 	versions, response, err := client.Project.Version.Gets(ctx, input.ProjectKey)
 	if err != nil {
@@ -82,14 +108,18 @@ func jiraListProjectVersionsHandler(ctx context.Context, request mcp.CallToolReq
 		return nil, fmt.Errorf("failed to list project versions: %v", err)
 	}
 
+	output := ListProjectVersionsOutput{ProjectKey: input.ProjectKey, Versions: []VersionOutput{}}
+
 	if len(versions) == 0 {
-		return mcp.NewToolResultText(fmt.Sprintf("No versions found for project %s.", input.ProjectKey)), nil
+		return formatResult(input.OutputFormat, output, fmt.Sprintf("No versions found for project %s.", input.ProjectKey))
 	}
 
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("Project %s Versions:\n\n", input.ProjectKey))
 
 	for i, version := range versions {
+		output.Versions = append(output.Versions, newVersionOutput(version))
+
 		if i > 0 {
 			result.WriteString("\n")
 		}
@@ -119,5 +149,27 @@ func jiraListProjectVersionsHandler(ctx context.Context, request mcp.CallToolReq
 		}
 	}
 
-	return mcp.NewToolResultText(result.String()), nil
+	return formatResult(input.OutputFormat, output, result.String())
+}
+
+func newVersionOutput(version *models.VersionScheme) VersionOutput {
+	status := "In Development"
+	if version.Released {
+		status = "Released"
+	}
+	if version.Archived {
+		status = "Archived"
+	}
+
+	return VersionOutput{
+		ID:          version.ID,
+		Name:        version.Name,
+		Description: version.Description,
+		ProjectID:   version.ProjectID,
+		Status:      status,
+		Released:    version.Released,
+		Archived:    version.Archived,
+		ReleaseDate: version.ReleaseDate,
+		URL:         version.Self,
+	}
 }
@@ -18,21 +18,38 @@ type AddWorklogInput struct {
 	TimeSpent string `json:"time_spent" validate:"required"`
 	Comment   string `json:"comment,omitempty"`
 	Started   string `json:"started,omitempty"`
+	OutputFormatInput
+}
+
+// WorklogOutput is the result of jira_add_worklog
+type WorklogOutput struct {
+	IssueKey         string `json:"issue_key"`
+	ID               string `json:"id"`
+	TimeSpent        string `json:"time_spent"`
+	TimeSpentSeconds int    `json:"time_spent_seconds"`
+	Started          string `json:"started"`
+	Author           string `json:"author,omitempty"`
 }
 
 func RegisterJiraWorklogTool(s *server.MCPServer) {
 	jiraAddWorklogTool := mcp.NewTool("jira_add_worklog",
+		additiveTool(),
 		mcp.WithDescription("Add a worklog to a Jira issue to track time spent on the issue"),
 		mcp.WithString("issue_key", mcp.Required(), mcp.Description("The unique identifier of the Jira issue (e.g., KP-2, PROJ-123)")),
 		mcp.WithString("time_spent", mcp.Required(), mcp.Description("Time spent working on the issue (e.g., 3h, 30m, 1h 30m)")),
 		mcp.WithString("comment", mcp.Description("Comment describing the work done")),
 		mcp.WithString("started", mcp.Description("When the work began, in ISO 8601 format (e.g., 2023-05-01T10:00:00.000+0000). Defaults to current time.")),
+		withOutputFormat[WorklogOutput](),
 	)
-	s.AddTool(jiraAddWorklogTool, mcp.NewTypedToolHandler(jiraAddWorklogHandler))
+	addTool(s, jiraAddWorklogTool, mcp.NewTypedToolHandler(jiraAddWorklogHandler))
 }
 
 func jiraAddWorklogHandler(ctx context.Context, request mcp.CallToolRequest, input AddWorklogInput) (*mcp.CallToolResult, error) {
-	client := services.JiraClient()
+	client := services.JiraClientFor(ctx)
+
+	if err := checkIssuePolicy(ctx, client, actionWorklog, input.IssueKey); err != nil {
+		return nil, err
+	}
 
 	// Convert timeSpent to seconds (this is a simplification - in a real implementation 
 	// you would need to parse formats like "1h 30m" properly)
@@ -69,7 +86,8 @@ func jiraAddWorklogHandler(ctx context.Context, request mcp.CallToolRequest, inp
 	}
 
 	// Call the Jira API to add the worklog
-	worklog, response, err := client.Issue.Worklog.Add(ctx, input.IssueKey, payload, options)
+	worklog, response, err := addWorklog(ctx, client, input.IssueKey, payload, options)
+	auditRequest(ctx, response, input.IssueKey)
 	if err != nil {
 		if response != nil {
 			return nil, fmt.Errorf("failed to add worklog: %s (endpoint: %s)", response.Bytes.String(), response.Endpoint)
@@ -77,6 +95,8 @@ func jiraAddWorklogHandler(ctx context.Context, request mcp.CallToolRequest, inp
 		return nil, fmt.Errorf("failed to add worklog: %v", err)
 	}
 
+	auditCreated(ctx, AuditResource{Type: auditResourceWorklog, Issue: input.IssueKey, ID: worklog.ID})
+
 	result := fmt.Sprintf(`Worklog added successfully!
 Issue: %s
 Worklog ID: %s
@@ -91,7 +111,15 @@ Author: %s`,
 		worklog.Author.DisplayName,
 	)
 
-	return mcp.NewToolResultText(result), nil
+	output := WorklogOutput{
+		IssueKey:         input.IssueKey,
+		ID:               worklog.ID,
+		TimeSpent:        input.TimeSpent,
+		TimeSpentSeconds: worklog.TimeSpentSeconds,
+		Started:          worklog.Started,
+		Author:           worklog.Author.DisplayName,
+	}
+	return formatResult(input.OutputFormat, output, result)
 }
 
 // parseTimeSpent converts time formats like "3h", "30m", "1h 30m" to seconds
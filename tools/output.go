@@ -0,0 +1,318 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+// Values of the output_format argument accepted by every tool
+const (
+	outputFormatText     = "text"
+	outputFormatJSON     = "json"
+	outputFormatMarkdown = "markdown"
+)
+
+// OutputFormatInput is embedded in every tool input to select how the result is rendered
+type OutputFormatInput struct {
+	OutputFormat string `json:"output_format,omitempty"`
+}
+
+// withOutputFormat adds the output_format argument and publishes T as the tool's output schema
+func withOutputFormat[T any]() mcp.ToolOption {
+	formatOption := mcp.WithString("output_format",
+		mcp.Enum(outputFormatText, outputFormatJSON, outputFormatMarkdown),
+		mcp.Description("Result format: 'text' (default), 'json' (the tool's output schema) or 'markdown'"),
+	)
+	schemaOption := mcp.WithOutputSchema[T]()
+
+	return func(tool *mcp.Tool) {
+		formatOption(tool)
+		schemaOption(tool)
+	}
+}
+
+// formatResult renders a tool result in the requested format.
+// The structured output is attached in every format, clients validate it against the
+// published output schema; only the text content changes with the format.
+func formatResult(format string, output interface{}, text string) (*mcp.CallToolResult, error) {
+	switch format {
+	case "", outputFormatText:
+		return mcp.NewToolResultStructured(output, text), nil
+	case outputFormatJSON:
+		encoded, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode result: %w", err)
+		}
+		return mcp.NewToolResultStructured(output, string(encoded)), nil
+	case outputFormatMarkdown:
+		return mcp.NewToolResultStructured(output, util.RenderMarkdown(output)), nil
+	}
+	return nil, invalidOutputFormatError(format)
+}
+
+// ValidateOutputFormat rejects calls with an unknown output_format before the handler runs,
+// so a mutating tool never applies a change whose result it cannot render
+func ValidateOutputFormat(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		format, ok := request.GetArguments()["output_format"]
+		if !ok || format == nil {
+			return next(ctx, request)
+		}
+
+		switch format {
+		case "", outputFormatText, outputFormatJSON, outputFormatMarkdown:
+			return next(ctx, request)
+		}
+		return nil, invalidOutputFormatError(fmt.Sprint(format))
+	}
+}
+
+func invalidOutputFormatError(format string) error {
+	return fmt.Errorf("invalid output_format %q: must be one of %s, %s, %s", format, outputFormatText, outputFormatJSON, outputFormatMarkdown)
+}
+
+// UserOutput is a Jira user in JSON output
+type UserOutput struct {
+	AccountID   string `json:"account_id,omitempty"`
+	Username    string `json:"username,omitempty" jsonschema_description:"Only on Jira Server and Data Center"`
+	DisplayName string `json:"display_name"`
+	Email       string `json:"email,omitempty" jsonschema_description:"Only present when the user's profile visibility allows it"`
+}
+
+// IssueRefOutput is a short reference to another issue, such as a parent or subtask
+type IssueRefOutput struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// IssueLinkOutput is a link from the issue to another issue
+type IssueLinkOutput struct {
+	Type         string `json:"type" jsonschema_description:"Link type name, e.g. Blocks"`
+	Direction    string `json:"direction" jsonschema:"enum=inward,enum=outward"`
+	Relationship string `json:"relationship" jsonschema_description:"Link description read from this issue, e.g. is blocked by"`
+	IssueRefOutput
+}
+
+// TransitionOutput is a workflow transition available on the issue
+type TransitionOutput struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ToStatus string `json:"to_status,omitempty"`
+}
+
+// AttachmentOutput is a file attached to the issue
+type AttachmentOutput struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mime_type,omitempty"`
+	Size     int    `json:"size,omitempty"`
+}
+
+// IssueOutput is the JSON form of an issue, shared by every tool that returns issues.
+// Fields that were not requested or are empty on the issue are omitted.
+type IssueOutput struct {
+	Key             string             `json:"key"`
+	ID              string             `json:"id"`
+	URL             string             `json:"url,omitempty" jsonschema_description:"Browse URL of the issue"`
+	Summary         string             `json:"summary,omitempty"`
+	Description     string             `json:"description,omitempty" jsonschema_description:"Description rendered as markdown"`
+	IssueType       string             `json:"issue_type,omitempty"`
+	Status          string             `json:"status,omitempty"`
+	StatusCategory  string             `json:"status_category,omitempty"`
+	Priority        string             `json:"priority,omitempty"`
+	Resolution      string             `json:"resolution,omitempty"`
+	Project         string             `json:"project,omitempty" jsonschema_description:"Project key"`
+	Assignee        *UserOutput        `json:"assignee,omitempty" jsonschema_description:"Absent when the issue is unassigned"`
+	Reporter        *UserOutput        `json:"reporter,omitempty"`
+	Parent          *IssueRefOutput    `json:"parent,omitempty"`
+	Sprint          string             `json:"sprint,omitempty" jsonschema_description:"Sprint names from the Sprint custom field, comma-separated"`
+	Labels          []string           `json:"labels,omitempty"`
+	Components      []string           `json:"components,omitempty"`
+	FixVersions     []string           `json:"fix_versions,omitempty"`
+	AffectsVersions []string           `json:"affects_versions,omitempty"`
+	Created         string             `json:"created,omitempty"`
+	Updated         string             `json:"updated,omitempty"`
+	ResolutionDate  string             `json:"resolution_date,omitempty"`
+	Subtasks        []IssueRefOutput   `json:"subtasks,omitempty"`
+	Links           []IssueLinkOutput  `json:"links,omitempty"`
+	Attachments     []AttachmentOutput `json:"attachments,omitempty"`
+	Transitions     []TransitionOutput `json:"transitions,omitempty"`
+	CustomFields    map[string]string  `json:"custom_fields,omitempty" jsonschema_description:"Custom field values rendered as text, keyed by display name"`
+}
+
+// newIssueOutput converts an issue and its extracted custom fields to the JSON form
+func newIssueOutput(issue *models.IssueScheme, customFields []util.CustomField) IssueOutput {
+	output := IssueOutput{
+		Key: issue.Key,
+		ID:  issue.ID,
+		URL: issueBrowseURL(issue.Self, issue.Key),
+	}
+
+	for _, transition := range issue.Transitions {
+		transitionOutput := TransitionOutput{ID: transition.ID, Name: transition.Name}
+		if transition.To != nil {
+			transitionOutput.ToStatus = transition.To.Name
+		}
+		output.Transitions = append(output.Transitions, transitionOutput)
+	}
+
+	for _, field := range customFields {
+		if output.CustomFields == nil {
+			output.CustomFields = map[string]string{}
+		}
+		output.CustomFields[field.Name] = field.Value
+		if field.Name == "Sprint" {
+			output.Sprint = field.Value
+		}
+	}
+
+	fields := issue.Fields
+	if fields == nil {
+		return output
+	}
+
+	output.Summary = fields.Summary
+	output.Labels = fields.Labels
+	output.Created = fields.Created
+	output.Updated = fields.Updated
+	output.ResolutionDate = fields.Resolutiondate
+	output.Assignee = newUserOutput(fields.Assignee)
+	output.Reporter = newUserOutput(fields.Reporter)
+
+	if fields.Description != nil {
+		output.Description = util.RenderADF(fields.Description)
+	}
+	if fields.IssueType != nil {
+		output.IssueType = fields.IssueType.Name
+	}
+	if fields.Status != nil {
+		output.Status = fields.Status.Name
+		if fields.Status.StatusCategory != nil {
+			output.StatusCategory = fields.Status.StatusCategory.Name
+		}
+	}
+	if fields.Priority != nil {
+		output.Priority = fields.Priority.Name
+	}
+	if fields.Resolution != nil {
+		output.Resolution = fields.Resolution.Name
+	}
+	if fields.Project != nil {
+		output.Project = fields.Project.Key
+	}
+
+	if fields.Parent != nil {
+		output.Parent = &IssueRefOutput{Key: fields.Parent.Key}
+		if fields.Parent.Fields != nil {
+			output.Parent.Summary = fields.Parent.Fields.Summary
+			if fields.Parent.Fields.Status != nil {
+				output.Parent.Status = fields.Parent.Fields.Status.Name
+			}
+		}
+	}
+
+	for _, component := range fields.Components {
+		output.Components = append(output.Components, component.Name)
+	}
+	for _, version := range fields.FixVersions {
+		output.FixVersions = append(output.FixVersions, version.Name)
+	}
+	for _, version := range fields.Versions {
+		output.AffectsVersions = append(output.AffectsVersions, version.Name)
+	}
+
+	for _, subtask := range fields.Subtasks {
+		subtaskOutput := IssueRefOutput{Key: subtask.Key}
+		if subtask.Fields != nil {
+			subtaskOutput.Summary = subtask.Fields.Summary
+			if subtask.Fields.Status != nil {
+				subtaskOutput.Status = subtask.Fields.Status.Name
+			}
+		}
+		output.Subtasks = append(output.Subtasks, subtaskOutput)
+	}
+
+	for _, link := range fields.IssueLinks {
+		if linkOutput, ok := newIssueLinkOutput(link); ok {
+			output.Links = append(output.Links, linkOutput)
+		}
+	}
+
+	for _, attachment := range fields.Attachment {
+		output.Attachments = append(output.Attachments, AttachmentOutput{
+			ID:       attachment.ID,
+			Filename: attachment.Title,
+			MimeType: attachment.MediaType,
+			Size:     attachment.FileSize,
+		})
+	}
+
+	return output
+}
+
+// newIssueLinkOutput reads a link from the side of the issue that holds it.
+// It reports false for links that carry neither an inward nor an outward issue.
+func newIssueLinkOutput(link *models.IssueLinkScheme) (IssueLinkOutput, bool) {
+	var linkOutput IssueLinkOutput
+	var linked *models.LinkedIssueScheme
+
+	switch {
+	case link.InwardIssue != nil:
+		linked = link.InwardIssue
+		linkOutput.Direction = "inward"
+		if link.Type != nil {
+			linkOutput.Relationship = link.Type.Inward
+		}
+	case link.OutwardIssue != nil:
+		linked = link.OutwardIssue
+		linkOutput.Direction = "outward"
+		if link.Type != nil {
+			linkOutput.Relationship = link.Type.Outward
+		}
+	default:
+		return linkOutput, false
+	}
+
+	if link.Type != nil {
+		linkOutput.Type = link.Type.Name
+	}
+
+	linkOutput.Key = linked.Key
+	if linked.Fields != nil {
+		linkOutput.Summary = linked.Fields.Summary
+		if linked.Fields.Status != nil {
+			linkOutput.Status = linked.Fields.Status.Name
+		}
+	}
+
+	return linkOutput, true
+}
+
+func newUserOutput(user *models.UserScheme) *UserOutput {
+	if user == nil {
+		return nil
+	}
+	return &UserOutput{
+		AccountID:   user.AccountID,
+		Username:    user.Name,
+		DisplayName: user.DisplayName,
+		Email:       user.EmailAddress,
+	}
+}
+
+// issueBrowseURL derives the browse URL from an issue's or project's REST self link
+func issueBrowseURL(self, key string) string {
+	index := strings.Index(self, "/rest/api/")
+	if index < 0 || key == "" {
+		return ""
+	}
+	return self[:index] + "/browse/" + key
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nguyenvanduocit/jira-mcp/util"
+)
+
+func testIssue() *models.IssueScheme {
+	return &models.IssueScheme{
+		ID:   "10001",
+		Key:  "PROJ-1",
+		Self: "https://example.atlassian.net/rest/api/3/issue/10001",
+		Transitions: []*models.IssueTransitionScheme{
+			{ID: "31", Name: "Done", To: &models.StatusScheme{Name: "Done"}},
+		},
+		Fields: &models.IssueFieldsScheme{
+			Summary:  "Login page",
+			Status:   &models.StatusScheme{Name: "In Progress", StatusCategory: &models.StatusCategoryScheme{Name: "In Progress"}},
+			Assignee: &models.UserScheme{AccountID: "abc", DisplayName: "Jane"},
+			IssueLinks: []*models.IssueLinkScheme{
+				{
+					Type:         &models.LinkTypeScheme{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"},
+					OutwardIssue: &models.LinkedIssueScheme{Key: "PROJ-2", Fields: &models.IssueLinkFieldsScheme{Summary: "Logout page"}},
+				},
+				{Type: &models.LinkTypeScheme{Name: "Relates"}},
+			},
+		},
+	}
+}
+
+func TestNewIssueOutput(t *testing.T) {
+	output := newIssueOutput(testIssue(), []util.CustomField{
+		{ID: "customfield_10020", Name: "Sprint", Value: "Sprint 7"},
+		{ID: "customfield_10016", Name: "Story Points", Value: "5"},
+	})
+
+	if output.URL != "https://example.atlassian.net/browse/PROJ-1" {
+		t.Errorf("URL = %q", output.URL)
+	}
+	if output.Status != "In Progress" || output.StatusCategory != "In Progress" {
+		t.Errorf("Status = %q / %q", output.Status, output.StatusCategory)
+	}
+	if output.Assignee == nil || output.Assignee.AccountID != "abc" {
+		t.Errorf("Assignee = %+v", output.Assignee)
+	}
+	if output.Sprint != "Sprint 7" || output.CustomFields["Story Points"] != "5" {
+		t.Errorf("Sprint = %q, CustomFields = %v", output.Sprint, output.CustomFields)
+	}
+	if len(output.Links) != 1 || output.Links[0].Key != "PROJ-2" || output.Links[0].Relationship != "blocks" || output.Links[0].Direction != "outward" {
+		t.Errorf("Links = %+v", output.Links)
+	}
+	if len(output.Transitions) != 1 || output.Transitions[0].ToStatus != "Done" {
+		t.Errorf("Transitions = %+v", output.Transitions)
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	output := newIssueOutput(testIssue(), nil)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "", want: "plain text"},
+		{format: outputFormatText, want: "plain text"},
+		{format: outputFormatJSON, want: "\"key\": \"PROJ-1\""},
+		{format: outputFormatMarkdown, want: "- **Key**: PROJ-1\n"},
+	}
+
+	for _, tt := range tests {
+		result, err := formatResult(tt.format, output, "plain text")
+		if err != nil {
+			t.Fatalf("formatResult(%q) error = %v", tt.format, err)
+		}
+		text := result.Content[0].(mcp.TextContent).Text
+		if !strings.Contains(text, tt.want) {
+			t.Errorf("formatResult(%q) text = %q, want it to contain %q", tt.format, text, tt.want)
+		}
+		if result.StructuredContent == nil {
+			t.Errorf("formatResult(%q) has no structured content", tt.format)
+		}
+	}
+
+	if _, err := formatResult("yaml", output, "plain text"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestWithOutputFormat(t *testing.T) {
+	tool := mcp.NewTool("test", withOutputFormat[SearchIssueOutput]())
+
+	if tool.OutputSchema.Type != "object" {
+		t.Fatalf("OutputSchema.Type = %q, want object", tool.OutputSchema.Type)
+	}
+	for _, property := range []string{"issues", "count", "is_last", "next_page_token"} {
+		if _, ok := tool.OutputSchema.Properties[property]; !ok {
+			t.Errorf("output schema is missing %q", property)
+		}
+	}
+
+	schema, _ := json.Marshal(tool.OutputSchema)
+	for _, property := range []string{"\"assignee\"", "\"links\"", "\"sprint\""} {
+		if !strings.Contains(string(schema), property) {
+			t.Errorf("issue schema is missing %s", property)
+		}
+	}
+
+	if _, ok := tool.InputSchema.Properties["output_format"]; !ok {
+		t.Error("input schema is missing output_format")
+	}
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	called := false
+	handler := ValidateOutputFormat(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"output_format": "xml"}
+	if _, err := handler(context.Background(), request); err == nil || called {
+		t.Fatal("expected an unknown output_format to be rejected before the handler runs")
+	}
+
+	request.Params.Arguments = map[string]interface{}{"output_format": "json"}
+	if _, err := handler(context.Background(), request); err != nil || !called {
+		t.Fatalf("valid output_format was rejected: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ToolPolicy decides which tools the Register functions add to the server.
+// Patterns are tool names or path.Match globs such as jira_get_* or *sprint*.
+type ToolPolicy struct {
+	// ReadOnly keeps only tools annotated as read-only
+	ReadOnly bool
+	// Enabled, when not empty, keeps only tools matching one of its patterns
+	Enabled []string
+	// Disabled drops tools matching one of its patterns, even when they are enabled
+	Disabled []string
+}
+
+var toolPolicy = struct {
+	sync.RWMutex
+	policy ToolPolicy
+}{}
+
+// SetToolPolicy sets the policy used by the Register functions. Call it before registering tools.
+func SetToolPolicy(policy ToolPolicy) error {
+	for _, pattern := range append(append([]string{}, policy.Enabled...), policy.Disabled...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+
+	toolPolicy.Lock()
+	defer toolPolicy.Unlock()
+	toolPolicy.policy = policy
+	return nil
+}
+
+// ParseToolPatterns splits a comma-separated pattern list such as JIRA_MCP_ENABLED_TOOLS
+func ParseToolPatterns(value string) []string {
+	return splitList(value)
+}
+
+// Allows reports whether a tool is registered under the policy
+func (p ToolPolicy) Allows(tool mcp.Tool) bool {
+	if p.ReadOnly && !isReadOnlyTool(tool) {
+		return false
+	}
+	if len(p.Enabled) > 0 && !matchesToolPattern(p.Enabled, tool.Name) {
+		return false
+	}
+	return !matchesToolPattern(p.Disabled, tool.Name)
+}
+
+func matchesToolPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isReadOnlyTool reads the readOnlyHint annotation, which mcp.NewTool defaults to false
+func isReadOnlyTool(tool mcp.Tool) bool {
+	hint := tool.Annotations.ReadOnlyHint
+	return hint != nil && *hint
+}
+
+// addTool registers a tool unless the tool policy leaves it out
+func addTool(s *server.MCPServer, tool mcp.Tool, handler server.ToolHandlerFunc) {
+	toolPolicy.RLock()
+	policy := toolPolicy.policy
+	toolPolicy.RUnlock()
+
+	if !policy.Allows(tool) {
+		return
+	}
+	if !isReadOnlyTool(tool) {
+		mutatingTools.Store(tool.Name, true)
+	}
+	s.AddTool(tool, handler)
+}
+
+// readOnlyTool annotates a tool that only reads from Jira
+func readOnlyTool() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithReadOnlyHintAnnotation(true)(tool)
+		mcp.WithDestructiveHintAnnotation(false)(tool)
+		mcp.WithIdempotentHintAnnotation(true)(tool)
+	}
+}
+
+// additiveTool annotates a tool that creates issues, comments, worklogs or links
+// without changing existing data
+func additiveTool() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithReadOnlyHintAnnotation(false)(tool)
+		mcp.WithDestructiveHintAnnotation(false)(tool)
+		mcp.WithIdempotentHintAnnotation(false)(tool)
+	}
+}
+
+// destructiveTool annotates a tool that overwrites or deletes existing data.
+// Idempotent tools give the same result when repeated with the same arguments.
+func destructiveTool(idempotent bool) mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithReadOnlyHintAnnotation(false)(tool)
+		mcp.WithDestructiveHintAnnotation(true)(tool)
+		mcp.WithIdempotentHintAnnotation(idempotent)(tool)
+	}
+}
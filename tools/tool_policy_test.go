@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestToolPolicyAllows(t *testing.T) {
+	getIssue := mcp.NewTool("jira_get_issue", readOnlyTool())
+	deleteIssue := mcp.NewTool("jira_delete_issue", destructiveTool(true))
+	unannotated := mcp.NewTool("jira_custom")
+
+	tests := []struct {
+		name   string
+		policy ToolPolicy
+		tool   mcp.Tool
+		want   bool
+	}{
+		{"no policy", ToolPolicy{}, deleteIssue, true},
+		{"read-only keeps readers", ToolPolicy{ReadOnly: true}, getIssue, true},
+		{"read-only drops writers", ToolPolicy{ReadOnly: true}, deleteIssue, false},
+		{"read-only drops unannotated tools", ToolPolicy{ReadOnly: true}, unannotated, false},
+		{"enabled glob", ToolPolicy{Enabled: []string{"jira_get_*"}}, getIssue, true},
+		{"not enabled", ToolPolicy{Enabled: []string{"jira_get_*"}}, deleteIssue, false},
+		{"disabled name", ToolPolicy{Disabled: []string{"jira_delete_issue"}}, deleteIssue, false},
+		{"disabled wins", ToolPolicy{Enabled: []string{"jira_*"}, Disabled: []string{"*_delete_*"}}, deleteIssue, false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Allows(tt.tool); got != tt.want {
+			t.Errorf("%s: Allows(%s) = %v, want %v", tt.name, tt.tool.Name, got, tt.want)
+		}
+	}
+}
+
+func TestSetToolPolicyRejectsBadPattern(t *testing.T) {
+	if err := SetToolPolicy(ToolPolicy{Enabled: []string{"jira_[get"}}); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
+
+func registerAllTools(s *server.MCPServer) {
+	RegisterJiraIssueTool(s)
+	RegisterJiraSearchTool(s)
+	RegisterJiraSprintTool(s)
+	RegisterJiraStatusTool(s)
+	RegisterJiraTransitionTool(s)
+	RegisterJiraWorklogTool(s)
+	RegisterJiraCommentTools(s)
+	RegisterJiraHistoryTool(s)
+	RegisterJiraRelationshipTool(s)
+	RegisterJiraVersionTool(s)
+	RegisterJiraDevelopmentTool(s)
+	RegisterJiraAttachmentTool(s)
+	RegisterJiraFieldTool(s)
+	RegisterJiraUserTool(s)
+	RegisterJiraProjectTool(s)
+	RegisterJiraCreateMetadataTool(s)
+	RegisterJiraBulkCreateTool(s)
+	RegisterJiraBulkUpdateTool(s)
+	RegisterJiraPolicyTool(s)
+	RegisterJiraUndoTool(s)
+}
+
+func TestReadOnlyPolicyRegistersNoWriters(t *testing.T) {
+	defer SetToolPolicy(ToolPolicy{})
+
+	// Every tool declares whether it changes Jira
+	s := server.NewMCPServer("test", "0")
+	registerAllTools(s)
+	for name, tool := range s.ListTools() {
+		if tool.Tool.Annotations.ReadOnlyHint == nil || tool.Tool.Annotations.DestructiveHint == nil {
+			t.Errorf("%s has no readOnly/destructive annotations", name)
+		}
+	}
+
+	if err := SetToolPolicy(ToolPolicy{ReadOnly: true}); err != nil {
+		t.Fatal(err)
+	}
+	s = server.NewMCPServer("test", "0")
+	registerAllTools(s)
+
+	registered := s.ListTools()
+	if len(registered) == 0 {
+		t.Fatal("read-only mode registered no tools")
+	}
+	for _, name := range []string{"jira_create_issue", "jira_update_issue", "jira_delete_issue", "jira_add_worklog", "jira_transition_issue", "jira_bulk_update"} {
+		if _, ok := registered[name]; ok {
+			t.Errorf("read-only mode registered %s", name)
+		}
+	}
+	for _, name := range []string{"jira_get_issue", "jira_search_issue"} {
+		if _, ok := registered[name]; !ok {
+			t.Errorf("read-only mode did not register %s", name)
+		}
+	}
+}
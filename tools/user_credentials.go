@@ -0,0 +1,20 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+// RequireUserCredentials rejects calls from HTTP sessions that did not send their own Jira credentials,
+// so that nothing falls back to an account of the server
+func RequireUserCredentials(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := services.CheckUserCredentials(ctx); err != nil {
+			return nil, err
+		}
+		return next(ctx, request)
+	}
+}
@@ -0,0 +1,251 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	jira "github.com/ctreminiom/go-atlassian/jira/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Actions checked by the write policy
+const (
+	actionCreate     = "create"
+	actionEdit       = "edit"
+	actionAssign     = "assign"
+	actionTransition = "transition"
+	actionComment    = "comment"
+	actionWorklog    = "worklog"
+	actionLink       = "link"
+	actionDelete     = "delete"
+)
+
+var writeActions = []string{actionCreate, actionEdit, actionAssign, actionTransition, actionComment, actionWorklog, actionLink, actionDelete}
+
+// WritePolicy restricts which changes the mutating tools may make, by project and issue type.
+// Without a policy every project may be changed.
+type WritePolicy struct {
+	// Default lists the actions allowed in projects that no rule matches, none when empty
+	Default []string `yaml:"default"`
+	// Projects are checked in order and the first rule matching the project applies
+	Projects []ProjectWriteRule `yaml:"projects"`
+}
+
+// ProjectWriteRule lists the actions allowed in the projects matching Project, a project key
+// or a glob such as PLAT-* or TEAM*
+type ProjectWriteRule struct {
+	Project string   `yaml:"project"`
+	Actions []string `yaml:"actions"`
+	// IssueTypes, when set, limits the actions to issues of these types
+	IssueTypes []string `yaml:"issue_types,omitempty"`
+}
+
+var writePolicy = struct {
+	sync.RWMutex
+	policy *WritePolicy
+	source string
+}{}
+
+// LoadWritePolicy reads the write policy from a YAML or JSON file
+func LoadWritePolicy(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	policy, err := parseWritePolicy(data)
+	if err != nil {
+		return fmt.Errorf("invalid policy file %s: %w", filename, err)
+	}
+
+	setWritePolicy(policy, filename)
+	return nil
+}
+
+// setWritePolicy replaces the write policy, a nil policy allows every change
+func setWritePolicy(policy *WritePolicy, source string) {
+	writePolicy.Lock()
+	defer writePolicy.Unlock()
+	writePolicy.policy = policy
+	writePolicy.source = source
+}
+
+func parseWritePolicy(data []byte) (*WritePolicy, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	policy := &WritePolicy{}
+	if err := decoder.Decode(policy); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if policy.Default, err = normalizeActions(policy.Default); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	for i := range policy.Projects {
+		rule := &policy.Projects[i]
+		rule.Project = strings.ToUpper(strings.TrimSuffix(strings.TrimSpace(rule.Project), "-*"))
+		if rule.Project == "" {
+			return nil, fmt.Errorf("rule %d has no project", i+1)
+		}
+		if _, err := path.Match(rule.Project, ""); err != nil {
+			return nil, fmt.Errorf("invalid project pattern %q: %w", rule.Project, err)
+		}
+		if rule.Actions, err = normalizeActions(rule.Actions); err != nil {
+			return nil, fmt.Errorf("project %s: %w", rule.Project, err)
+		}
+	}
+
+	return policy, nil
+}
+
+// normalizeActions lower-cases actions and expands "*" to every action
+func normalizeActions(actions []string) ([]string, error) {
+	var normalized []string
+	for _, action := range actions {
+		action = strings.ToLower(strings.TrimSpace(action))
+		if action == "*" {
+			return append([]string{}, writeActions...), nil
+		}
+		if !containsString(writeActions, action) {
+			return nil, fmt.Errorf("unknown action %q, expected one of %s or *", action, strings.Join(writeActions, ", "))
+		}
+		normalized = append(normalized, action)
+	}
+	return normalized, nil
+}
+
+func currentWritePolicy() (*WritePolicy, string) {
+	writePolicy.RLock()
+	defer writePolicy.RUnlock()
+	return writePolicy.policy, writePolicy.source
+}
+
+// rule returns the first rule matching a project, or nil when the default applies
+func (p *WritePolicy) rule(projectKey string) *ProjectWriteRule {
+	projectKey = strings.ToUpper(projectKey)
+	for i := range p.Projects {
+		if matched, _ := path.Match(p.Projects[i].Project, projectKey); matched {
+			return &p.Projects[i]
+		}
+	}
+	return nil
+}
+
+// allowedActions lists the actions allowed on issues of a type in a project.
+// An empty issue type skips the issue type check, for callers that do not know it yet.
+func (p *WritePolicy) allowedActions(projectKey, issueType string) []string {
+	rule := p.rule(projectKey)
+	if rule == nil {
+		return p.Default
+	}
+	if issueType != "" && len(rule.IssueTypes) > 0 && !containsFold(rule.IssueTypes, issueType) {
+		return nil
+	}
+	return rule.Actions
+}
+
+// policyDeniedError is returned by mutating tools when the write policy forbids the change
+type policyDeniedError struct {
+	Action    string
+	Project   string
+	IssueType string
+	Allowed   []string
+}
+
+func (e *policyDeniedError) Error() string {
+	target := "project " + e.Project
+	if e.IssueType != "" {
+		target = fmt.Sprintf("%s issues in project %s", e.IssueType, e.Project)
+	}
+
+	allowed := "no changes are allowed there"
+	if len(e.Allowed) > 0 {
+		allowed = "allowed there: " + strings.Join(e.Allowed, ", ")
+	}
+
+	return fmt.Sprintf("policy denied: %s is not allowed on %s (%s). Call jira_explain_policy to see what may be changed", e.Action, target, allowed)
+}
+
+// checkWritePolicy checks an action on issues of a type in a project
+func checkWritePolicy(action, projectKey, issueType string) error {
+	policy, _ := currentWritePolicy()
+	if policy == nil {
+		return nil
+	}
+
+	allowed := policy.allowedActions(projectKey, issueType)
+	if containsString(allowed, action) {
+		return nil
+	}
+	return &policyDeniedError{Action: action, Project: strings.ToUpper(projectKey), IssueType: issueType, Allowed: allowed}
+}
+
+// checkIssuePolicy checks an action on an existing issue against the rule of the project the
+// issue is in now. The issue is fetched for it, as a moved issue still answers to its old key.
+func checkIssuePolicy(ctx context.Context, client *jira.Client, action, issueKey string) error {
+	return checkIssueActions(ctx, client, issueKey, action)
+}
+
+// checkIssueActions checks every action one call performs on an existing issue, fetching the
+// issue once
+func checkIssueActions(ctx context.Context, client *jira.Client, issueKey string, actions ...string) error {
+	policy, _ := currentWritePolicy()
+	if policy == nil {
+		return nil
+	}
+
+	issue, response, err := getIssue(ctx, client, issueKey, []string{"project", "issuetype"}, nil)
+	if err != nil {
+		if response != nil {
+			return fmt.Errorf("failed to get issue %s to check the write policy: %s (endpoint: %s)", issueKey, response.Bytes.String(), response.Endpoint)
+		}
+		return fmt.Errorf("failed to get issue %s to check the write policy: %v", issueKey, err)
+	}
+
+	var projectKey, issueType string
+	if issue.Fields != nil && issue.Fields.Project != nil {
+		projectKey = issue.Fields.Project.Key
+	}
+	if issue.Fields != nil && issue.Fields.IssueType != nil {
+		issueType = issue.Fields.IssueType.Name
+	}
+	for _, action := range actions {
+		if err := checkWritePolicy(action, projectKey, issueType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueProjectKey returns the project part of an issue key, or "" for an issue ID
+func issueProjectKey(issueKey string) string {
+	if !issueKeyPattern.MatchString(issueKey) {
+		return ""
+	}
+	return issueKey[:strings.LastIndex(issueKey, "-")]
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,283 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+	"github.com/nguyenvanduocit/jira-mcp/services"
+)
+
+const testWritePolicy = `
+default: [comment]
+projects:
+  - project: PLAT-*
+    actions: ["*"]
+  - project: SEC
+    actions: [comment]
+  - project: OPS
+    actions: [Create, edit]
+    issue_types: [Task, Bug]
+  - project: "*"
+    actions: []
+`
+
+func loadTestWritePolicy(t *testing.T) {
+	t.Helper()
+	policy, err := parseWritePolicy([]byte(testWritePolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setWritePolicy(policy, "policy.yaml")
+	t.Cleanup(func() { setWritePolicy(nil, "") })
+}
+
+func TestParseWritePolicy(t *testing.T) {
+	policy, err := parseWritePolicy([]byte(testWritePolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy.Projects[0].Project != "PLAT" || !reflect.DeepEqual(policy.Projects[0].Actions, writeActions) {
+		t.Errorf("PLAT-* rule = %+v", policy.Projects[0])
+	}
+	if !reflect.DeepEqual(policy.Projects[2].Actions, []string{"create", "edit"}) {
+		t.Errorf("actions are not normalized: %v", policy.Projects[2].Actions)
+	}
+
+	// JSON is valid YAML
+	if _, err := parseWritePolicy([]byte(`{"projects": [{"project": "KP", "actions": ["edit"]}]}`)); err != nil {
+		t.Errorf("JSON policy: %v", err)
+	}
+
+	for _, invalid := range []string{
+		`projects: [{project: KP, actions: [rename]}]`,
+		`projects: [{actions: [edit]}]`,
+		`projects: [{project: KP, action: [edit]}]`,
+		`projects: [{project: "KP[", actions: [edit]}]`,
+	} {
+		if _, err := parseWritePolicy([]byte(invalid)); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestCheckWritePolicy(t *testing.T) {
+	if err := checkWritePolicy(actionDelete, "ANY", ""); err != nil {
+		t.Fatalf("without a policy everything is allowed: %v", err)
+	}
+
+	loadTestWritePolicy(t)
+
+	tests := []struct {
+		action    string
+		project   string
+		issueType string
+		allowed   bool
+	}{
+		{actionDelete, "PLAT", "", true},
+		{actionEdit, "plat", "Story", true},
+		{actionComment, "SEC", "", true},
+		{actionEdit, "SEC", "", false},
+		{actionEdit, "OPS", "bug", true},
+		{actionEdit, "OPS", "Story", false},
+		{actionCreate, "OPS", "", true},
+		{actionComment, "OTHER", "", false},
+	}
+	for _, tt := range tests {
+		err := checkWritePolicy(tt.action, tt.project, tt.issueType)
+		if tt.allowed && err != nil {
+			t.Errorf("%s on %s %s: %v", tt.action, tt.project, tt.issueType, err)
+		}
+		if !tt.allowed {
+			var denied *policyDeniedError
+			if !errors.As(err, &denied) {
+				t.Errorf("%s on %s %s: expected a policy denied error, got %v", tt.action, tt.project, tt.issueType, err)
+			}
+		}
+	}
+
+	err := checkWritePolicy(actionEdit, "SEC", "")
+	if err == nil || !strings.Contains(err.Error(), "policy denied: edit is not allowed on project SEC (allowed there: comment)") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestTransitionPolicy(t *testing.T) {
+	policy, err := parseWritePolicy([]byte(`
+projects:
+  - project: FLOW
+    actions: [transition]
+  - project: OPEN
+    actions: ["*"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setWritePolicy(policy, "policy.yaml")
+	defer setWritePolicy(nil, "")
+
+	tests := []struct {
+		name   string
+		input  TransitionIssueInput
+		denied string
+	}{
+		{"status only", TransitionIssueInput{IssueKey: "FLOW-1", TargetStatus: "Done", Resolution: "Fixed"}, ""},
+		{"comment", TransitionIssueInput{IssueKey: "FLOW-1", TargetStatus: "Done", Comment: "Shipped"}, actionComment},
+		{"fields", TransitionIssueInput{IssueKey: "FLOW-1", TargetStatus: "Done", Fields: map[string]interface{}{"customfield_10010": "x"}}, actionEdit},
+		{"fix versions", TransitionIssueInput{IssueKey: "FLOW-1", TargetStatus: "Done", FixVersions: "1.2"}, actionEdit},
+		{"assignee", TransitionIssueInput{IssueKey: "FLOW-1", TargetStatus: "Done", AssigneeAccountID: "abc"}, actionAssign},
+		{"assignee in fields", TransitionIssueInput{IssueKey: "FLOW-1", TargetStatus: "Done", Fields: map[string]interface{}{"assignee": map[string]interface{}{"accountId": "abc"}}}, actionAssign},
+		{"everything where allowed", TransitionIssueInput{IssueKey: "OPEN-1", TargetStatus: "Done", Comment: "Shipped", FixVersions: "1.2", AssigneeAccountID: "abc"}, ""},
+		{"old key of an issue moved to FLOW", TransitionIssueInput{IssueKey: "OPEN-7", TargetStatus: "Done", Comment: "Shipped"}, actionComment},
+	}
+	ctx := policyIssueContext(t, map[string]string{"OPEN-7": "FLOW"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkIssueActions(ctx, services.JiraClientFor(ctx), tt.input.IssueKey, transitionActions(tt.input)...)
+			if tt.denied == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var denied *policyDeniedError
+			if !errors.As(err, &denied) || denied.Action != tt.denied {
+				t.Errorf("error = %v, want %s denied", err, tt.denied)
+			}
+		})
+	}
+}
+
+func TestUpdatePolicy(t *testing.T) {
+	policy, err := parseWritePolicy([]byte(`
+projects:
+  - project: EDIT
+    actions: [edit]
+  - project: OPEN
+    actions: ["*"]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	setWritePolicy(policy, "policy.yaml")
+	defer setWritePolicy(nil, "")
+
+	tests := []struct {
+		name   string
+		input  UpdateIssueInput
+		denied string
+	}{
+		{"fields", UpdateIssueInput{IssueKey: "EDIT-1", Summary: "New title", IssueFieldsInput: IssueFieldsInput{Priority: "High"}}, ""},
+		{"custom fields", UpdateIssueInput{IssueKey: "EDIT-1", IssueFieldsInput: IssueFieldsInput{CustomFields: map[string]interface{}{"Story Points": 3}}}, ""},
+		{"assignee", UpdateIssueInput{IssueKey: "EDIT-1", IssueFieldsInput: IssueFieldsInput{Assignee: "abc"}}, actionAssign},
+		{"unassign", UpdateIssueInput{IssueKey: "EDIT-1", IssueFieldsInput: IssueFieldsInput{Assignee: "unassigned"}}, actionAssign},
+		{"assignee with fields", UpdateIssueInput{IssueKey: "EDIT-1", Summary: "New title", IssueFieldsInput: IssueFieldsInput{Assignee: "abc"}}, actionAssign},
+		{"assignee where allowed", UpdateIssueInput{IssueKey: "OPEN-1", Summary: "New title", IssueFieldsInput: IssueFieldsInput{Assignee: "abc"}}, ""},
+		{"old key of an issue moved to EDIT", UpdateIssueInput{IssueKey: "OPEN-8", IssueFieldsInput: IssueFieldsInput{Assignee: "abc"}}, actionAssign},
+	}
+	ctx := policyIssueContext(t, map[string]string{"OPEN-8": "EDIT"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := buildIssuePayload(nil, tt.input.IssueFieldsInput)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkIssueActions(ctx, services.JiraClientFor(ctx), tt.input.IssueKey, updateActions(tt.input, payload)...)
+			if tt.denied == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var denied *policyDeniedError
+			if !errors.As(err, &denied) || denied.Action != tt.denied {
+				t.Errorf("error = %v, want %s denied", err, tt.denied)
+			}
+		})
+	}
+}
+
+// policyIssueContext returns a context whose Jira places every issue in the project its key names,
+// except the issues in moved, which answer to their old key from the project they were moved to
+func policyIssueContext(t *testing.T, moved map[string]string) context.Context {
+	t.Helper()
+	jiraServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/3/issue/")
+		project, ok := moved[key]
+		if !ok {
+			project = issueProjectKey(key)
+		}
+		if r.Method != http.MethodGet || project == "" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"key": %q, "fields": {"project": {"key": %q}, "issuetype": {"name": "Task"}}}`, key, project)
+	}))
+	t.Cleanup(jiraServer.Close)
+	return stubJiraContext(t, jiraServer.URL, services.DeploymentCloud)
+}
+
+func TestIssueProjectKey(t *testing.T) {
+	for key, want := range map[string]string{"PLAT-12": "PLAT", "AB_2-7": "AB_2", "10042": ""} {
+		if got := issueProjectKey(key); got != want {
+			t.Errorf("issueProjectKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestCheckBulkPolicy(t *testing.T) {
+	loadTestWritePolicy(t)
+
+	issue := func(key, project, issueType string) *models.IssueScheme {
+		return &models.IssueScheme{Key: key, Fields: &models.IssueFieldsScheme{
+			Project:   &models.ProjectScheme{Key: project},
+			IssueType: &models.IssueTypeScheme{Name: issueType},
+		}}
+	}
+	issues := []*models.IssueScheme{issue("PLAT-1", "PLAT", "Story"), issue("OPS-1", "OPS", "Task"), issue("OPS-2", "OPS", "Story")}
+
+	if err := checkBulkPolicy(issues[:2], true, false, false); err != nil {
+		t.Errorf("edit: %v", err)
+	}
+
+	err := checkBulkPolicy(issues, true, false, false)
+	if err == nil || !strings.Contains(err.Error(), "1 of the 3 selected issues may not be changed (OPS-2)") {
+		t.Errorf("edit with a denied issue type: %v", err)
+	}
+
+	if err := checkBulkPolicy(issues[:2], false, false, true); err == nil {
+		t.Error("expected transitions in OPS to be denied")
+	}
+}
+
+func TestExplainPolicy(t *testing.T) {
+	output := explainPolicy("ops", "Story")
+	if output.Configured || len(output.AllowedActions) != len(writeActions) {
+		t.Errorf("without a policy: %+v", output)
+	}
+
+	loadTestWritePolicy(t)
+
+	output = explainPolicy("ops", "Task")
+	if !output.Configured || output.Source != "policy.yaml" || len(output.Rules) != 4 {
+		t.Errorf("policy not described: %+v", output)
+	}
+	if !reflect.DeepEqual(output.AllowedActions, []string{"create", "edit"}) {
+		t.Errorf("allowed actions = %v", output.AllowedActions)
+	}
+
+	output = explainPolicy("OPS", "Story")
+	if len(output.AllowedActions) != 0 {
+		t.Errorf("allowed actions for a denied issue type = %v", output.AllowedActions)
+	}
+	if text := formatPolicy(output); !strings.Contains(text, "Allowed on Story issues in OPS: nothing") {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+}
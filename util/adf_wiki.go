@@ -0,0 +1,202 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// RenderWiki converts an Atlassian Document Format (ADF) structure to the wiki markup of Jira Server and Data Center
+func RenderWiki(node *models.CommentNodeScheme) string {
+	if node == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	renderWikiNode(node, &sb, "")
+	return strings.TrimSpace(sb.String())
+}
+
+// renderWikiNode recursively renders an ADF node as wiki markup. listPrefix is the bullet of the
+// enclosing lists, e.g. "*#" for a numbered list in a bullet list.
+func renderWikiNode(node *models.CommentNodeScheme, sb *strings.Builder, listPrefix string) {
+	if node == nil {
+		return
+	}
+
+	switch node.Type {
+	case "paragraph":
+		renderWikiChildren(node, sb, listPrefix)
+		if listPrefix == "" {
+			sb.WriteString("\n\n")
+		}
+
+	case "text":
+		sb.WriteString(wikiText(node))
+
+	case "hardBreak":
+		sb.WriteString("\n")
+
+	case "heading":
+		level := 1
+		switch lvl := node.Attrs["level"].(type) {
+		case int:
+			level = lvl
+		case float64:
+			level = int(lvl)
+		}
+		sb.WriteString(fmt.Sprintf("h%d. ", level))
+		renderWikiChildren(node, sb, listPrefix)
+		sb.WriteString("\n\n")
+
+	case "bulletList", "orderedList":
+		bullet := "*"
+		if node.Type == "orderedList" {
+			bullet = "#"
+		}
+		for _, child := range node.Content {
+			renderWikiNode(child, sb, listPrefix+bullet)
+		}
+		if listPrefix == "" {
+			sb.WriteString("\n")
+		}
+
+	case "listItem":
+		sb.WriteString(listPrefix + " ")
+		endsWithList := false
+		for i, child := range node.Content {
+			isList := child.Type == "bulletList" || child.Type == "orderedList"
+			// Nested lists start on their own line, further paragraphs continue the item
+			if isList && !endsWithList {
+				sb.WriteString("\n")
+			} else if !isList && i > 0 {
+				sb.WriteString(" ")
+			}
+			renderWikiNode(child, sb, listPrefix)
+			endsWithList = isList
+		}
+		if !endsWithList {
+			sb.WriteString("\n")
+		}
+
+	case "codeBlock":
+		if language, ok := node.Attrs["language"].(string); ok && language != "" {
+			sb.WriteString("{code:" + language + "}\n")
+		} else {
+			sb.WriteString("{code}\n")
+		}
+		var code strings.Builder
+		for _, child := range node.Content {
+			code.WriteString(child.Text)
+		}
+		sb.WriteString(strings.TrimSuffix(code.String(), "\n"))
+		sb.WriteString("\n{code}\n\n")
+
+	case "blockquote":
+		sb.WriteString("{quote}\n")
+		var inner strings.Builder
+		renderWikiChildren(node, &inner, "")
+		sb.WriteString(strings.TrimSpace(inner.String()))
+		sb.WriteString("\n{quote}\n\n")
+
+	case "rule":
+		sb.WriteString("----\n\n")
+
+	case "table":
+		renderWikiChildren(node, sb, listPrefix)
+		sb.WriteString("\n")
+
+	case "tableRow":
+		separator := "|"
+		if len(node.Content) > 0 && node.Content[0].Type == "tableHeader" {
+			separator = "||"
+		}
+		for _, cell := range node.Content {
+			var inner strings.Builder
+			renderWikiChildren(cell, &inner, "")
+			sb.WriteString(separator + " " + strings.TrimSpace(inner.String()) + " ")
+		}
+		sb.WriteString(separator + "\n")
+
+	case "mention":
+		if id, ok := node.Attrs["id"].(string); ok && id != "" {
+			sb.WriteString("[~" + id + "]")
+		} else if text, ok := node.Attrs["text"].(string); ok {
+			sb.WriteString(text)
+		}
+
+	case "emoji":
+		if shortName, ok := node.Attrs["shortName"].(string); ok {
+			sb.WriteString(shortName)
+		}
+
+	case "inlineCard":
+		if url, ok := node.Attrs["url"].(string); ok {
+			sb.WriteString("[" + url + "]")
+		}
+
+	default:
+		renderWikiChildren(node, sb, listPrefix)
+	}
+}
+
+func renderWikiChildren(node *models.CommentNodeScheme, sb *strings.Builder, listPrefix string) {
+	for _, child := range node.Content {
+		renderWikiNode(child, sb, listPrefix)
+	}
+}
+
+// wikiText applies the marks of a text node; links wrap the formatted text
+func wikiText(node *models.CommentNodeScheme) string {
+	text := node.Text
+	href := ""
+	for _, mark := range node.Marks {
+		switch mark.Type {
+		case "strong":
+			text = "*" + text + "*"
+		case "em":
+			text = "_" + text + "_"
+		case "code":
+			text = "{{" + text + "}}"
+		case "strike":
+			text = "-" + text + "-"
+		case "underline":
+			text = "+" + text + "+"
+		case "link":
+			href, _ = mark.Attrs["href"].(string)
+		}
+	}
+	if href != "" {
+		if text == href {
+			return "[" + href + "]"
+		}
+		return "[" + text + "|" + href + "]"
+	}
+	return text
+}
+
+// WikiToADF wraps wiki markup in an ADF document, one paragraph per block separated by a blank line,
+// so that Server and Data Center text renders like Cloud documents. The markup itself is kept as text.
+func WikiToADF(text string) *models.CommentNodeScheme {
+	doc := &models.CommentNodeScheme{Version: 1, Type: "doc"}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	for _, block := range strings.Split(text, "\n\n") {
+		block = strings.Trim(block, "\n")
+		if block == "" {
+			continue
+		}
+		paragraph := &models.CommentNodeScheme{Type: "paragraph"}
+		for i, line := range strings.Split(block, "\n") {
+			if i > 0 {
+				paragraph.Content = append(paragraph.Content, &models.CommentNodeScheme{Type: "hardBreak"})
+			}
+			if line != "" {
+				paragraph.Content = append(paragraph.Content, &models.CommentNodeScheme{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, paragraph)
+	}
+	return doc
+}
@@ -0,0 +1,38 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestRenderWiki(t *testing.T) {
+	md := "# Title\n\nSome **bold**, *italic* and `code` with a [link](https://example.com).\n\n" +
+		"- one\n- two\n  1. nested\n\n```go\nfmt.Println()\n```\n"
+	want := "h1. Title\n\n" +
+		"Some *bold*, _italic_ and {{code}} with a [link|https://example.com].\n\n" +
+		"* one\n* two\n*# nested\n\n" +
+		"{code:go}\nfmt.Println()\n{code}"
+
+	if got := RenderWiki(MarkdownToADF(md)); got != want {
+		t.Errorf("RenderWiki() =\n%s\nwant\n%s", got, want)
+	}
+	if got := RenderWiki(nil); got != "" {
+		t.Errorf("RenderWiki(nil) = %q", got)
+	}
+}
+
+func TestWikiToADF(t *testing.T) {
+	doc := WikiToADF("h1. Title\r\n\r\nfirst line\nsecond line\n\n\n")
+
+	assertNodeType(t, doc, "doc")
+	assertContentLen(t, doc, 2)
+
+	paragraph := doc.Content[1]
+	assertNodeType(t, paragraph, "paragraph")
+	assertContentLen(t, paragraph, 3)
+	if paragraph.Content[0].Text != "first line" || paragraph.Content[1].Type != "hardBreak" || paragraph.Content[2].Text != "second line" {
+		t.Errorf("unexpected paragraph %+v", paragraph.Content)
+	}
+	if got := RenderWiki(doc); got != "h1. Title\n\nfirst line\nsecond line" {
+		t.Errorf("round trip = %q", got)
+	}
+}
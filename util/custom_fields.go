@@ -0,0 +1,176 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+// CustomField is a custom field value of an issue, resolved to its display name and rendered as text
+type CustomField struct {
+	ID    string
+	Name  string
+	Value string
+}
+
+// CustomFieldFilter limits which custom fields are rendered.
+// Entries match field IDs or display names case-insensitively. When Allow is set, only
+// those fields are rendered; Deny always wins.
+type CustomFieldFilter struct {
+	Allow []string
+	Deny  []string
+}
+
+// CustomFieldFilterFromEnv reads the filter from the comma-separated
+// JIRA_CUSTOM_FIELDS_ALLOW and JIRA_CUSTOM_FIELDS_DENY environment variables
+func CustomFieldFilterFromEnv() CustomFieldFilter {
+	return CustomFieldFilter{
+		Allow: splitEnvList(os.Getenv("JIRA_CUSTOM_FIELDS_ALLOW")),
+		Deny:  splitEnvList(os.Getenv("JIRA_CUSTOM_FIELDS_DENY")),
+	}
+}
+
+// Includes reports whether a field passes the filter
+func (f CustomFieldFilter) Includes(id, name string) bool {
+	if containsFold(f.Deny, id) || containsFold(f.Deny, name) {
+		return false
+	}
+	if len(f.Allow) == 0 {
+		return true
+	}
+	return containsFold(f.Allow, id) || containsFold(f.Allow, name)
+}
+
+// ExtractIssueCustomFields reads the custom fields from the raw JSON of a single issue.
+// The issue must be fetched with the "names" expand unless names is provided, as search
+// results carry the names map at the top level instead of on each issue.
+func ExtractIssueCustomFields(rawIssue []byte, names map[string]string, filter CustomFieldFilter) ([]CustomField, error) {
+	var issue struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+		Names  map[string]string          `json:"names"`
+	}
+	if err := json.Unmarshal(rawIssue, &issue); err != nil {
+		return nil, fmt.Errorf("failed to decode issue: %w", err)
+	}
+
+	if names == nil {
+		names = issue.Names
+	}
+
+	return ExtractCustomFields(issue.Fields, names, filter), nil
+}
+
+// ExtractCustomFields renders every non-empty customfield_* value, sorted by display name
+func ExtractCustomFields(fields map[string]json.RawMessage, names map[string]string, filter CustomFieldFilter) []CustomField {
+	var customFields []CustomField
+	for id, raw := range fields {
+		if !strings.HasPrefix(id, "customfield_") {
+			continue
+		}
+
+		name := names[id]
+		if name == "" {
+			name = id
+		}
+
+		if !filter.Includes(id, name) {
+			continue
+		}
+
+		value := RenderFieldValue(raw)
+		if value == "" {
+			continue
+		}
+
+		customFields = append(customFields, CustomField{ID: id, Name: name, Value: value})
+	}
+
+	sort.Slice(customFields, func(i, j int) bool {
+		if customFields[i].Name == customFields[j].Name {
+			return customFields[i].ID < customFields[j].ID
+		}
+		return customFields[i].Name < customFields[j].Name
+	})
+
+	return customFields
+}
+
+// RenderFieldValue converts a raw Jira field value to readable text.
+// ADF documents are rendered as markdown, options, users and other entities by their
+// display value, and arrays as comma-separated lists.
+func RenderFieldValue(raw json.RawMessage) string {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return fmt.Sprint(v)
+	case bool:
+		return fmt.Sprint(v)
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			itemRaw, _ := json.Marshal(item)
+			if rendered := RenderFieldValue(itemRaw); rendered != "" {
+				items = append(items, rendered)
+			}
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}:
+		if v["type"] == "doc" {
+			var doc models.CommentNodeScheme
+			if err := json.Unmarshal(raw, &doc); err == nil {
+				return RenderADF(&doc)
+			}
+		}
+
+		if optionValue, ok := v["value"].(string); ok {
+			if child, ok := v["child"].(map[string]interface{}); ok {
+				if childValue, ok := child["value"].(string); ok {
+					return optionValue + " > " + childValue
+				}
+			}
+			return optionValue
+		}
+
+		for _, key := range []string{"displayName", "name", "key"} {
+			if text, ok := v[key].(string); ok && text != "" {
+				return text
+			}
+		}
+
+		compact, _ := json.Marshal(v)
+		return string(compact)
+	}
+
+	return ""
+}
+
+func splitEnvList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
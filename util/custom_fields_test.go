@@ -0,0 +1,106 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ctreminiom/go-atlassian/pkg/infra/models"
+)
+
+const issueWithCustomFields = `{
+	"key": "PROJ-1",
+	"names": {
+		"customfield_10016": "Story Points",
+		"customfield_10020": "Sprint",
+		"customfield_10030": "Acceptance Criteria",
+		"customfield_10040": "Team",
+		"customfield_10050": "Region",
+		"customfield_10060": "Unused"
+	},
+	"fields": {
+		"summary": "Login page",
+		"customfield_10016": 5,
+		"customfield_10020": [{"id": 42, "name": "Sprint 7", "state": "active"}],
+		"customfield_10030": {"type": "doc", "version": 1, "content": [
+			{"type": "bulletList", "content": [
+				{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "User can log in"}]}]},
+				{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "User can log out"}]}]}
+			]}
+		]},
+		"customfield_10040": {"value": "Platform"},
+		"customfield_10050": {"value": "EU", "child": {"value": "Germany"}},
+		"customfield_10060": null
+	}
+}`
+
+func TestExtractIssueCustomFields(t *testing.T) {
+	fields, err := ExtractIssueCustomFields([]byte(issueWithCustomFields), nil, CustomFieldFilter{})
+	if err != nil {
+		t.Fatalf("ExtractIssueCustomFields() error = %v", err)
+	}
+
+	got := map[string]string{}
+	for _, field := range fields {
+		got[field.Name] = field.Value
+	}
+
+	want := map[string]string{
+		"Story Points": "5",
+		"Sprint":       "Sprint 7",
+		"Team":         "Platform",
+		"Region":       "EU > Germany",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+
+	if !strings.Contains(got["Acceptance Criteria"], "- User can log in") {
+		t.Errorf("Acceptance Criteria = %q, want rendered ADF list", got["Acceptance Criteria"])
+	}
+
+	if _, ok := got["Unused"]; ok {
+		t.Error("null custom field should be skipped")
+	}
+
+	if len(fields) != 5 {
+		t.Errorf("got %d custom fields, want 5", len(fields))
+	}
+}
+
+func TestExtractIssueCustomFields_Filter(t *testing.T) {
+	fields, err := ExtractIssueCustomFields([]byte(issueWithCustomFields), nil, CustomFieldFilter{
+		Allow: []string{"story points", "customfield_10040", "Sprint"},
+		Deny:  []string{"Sprint"},
+	})
+	if err != nil {
+		t.Fatalf("ExtractIssueCustomFields() error = %v", err)
+	}
+
+	if len(fields) != 2 || fields[0].Name != "Story Points" || fields[1].Name != "Team" {
+		t.Errorf("got %+v, want Story Points and Team", fields)
+	}
+}
+
+func TestFormatJiraIssueWithCustomFields(t *testing.T) {
+	fields, _ := ExtractIssueCustomFields([]byte(issueWithCustomFields), nil, CustomFieldFilter{})
+	output := FormatJiraIssueWithCustomFields(minimalIssue(), fields)
+
+	if !strings.Contains(output, "Custom Fields:\n") {
+		t.Fatalf("output is missing the custom fields section:\n%s", output)
+	}
+	if !strings.Contains(output, "- Story Points: 5\n") {
+		t.Errorf("output is missing Story Points:\n%s", output)
+	}
+	if !strings.Contains(output, "- Acceptance Criteria:\n- User can log in") {
+		t.Errorf("multi-line custom field should start on its own line:\n%s", output)
+	}
+}
+
+func minimalIssue() *models.IssueScheme {
+	return &models.IssueScheme{
+		Key:    "PROJ-1",
+		Fields: &models.IssueFieldsScheme{Summary: "Login page"},
+	}
+}
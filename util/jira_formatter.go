@@ -219,6 +219,12 @@ func renderADFNode(node *models.CommentNodeScheme, sb *strings.Builder, depth in
 // FormatJiraIssue converts a Jira issue struct to a formatted string representation
 // It handles all available fields from IssueFieldsSchemeV2 and related schemas
 func FormatJiraIssue(issue *models.IssueScheme) string {
+	return FormatJiraIssueWithCustomFields(issue, nil)
+}
+
+// FormatJiraIssueWithCustomFields formats an issue like FormatJiraIssue and adds the given
+// custom fields by display name. Use ExtractIssueCustomFields to read them from the raw issue
+func FormatJiraIssueWithCustomFields(issue *models.IssueScheme, customFields []CustomField) string {
 	var sb strings.Builder
 
 	// Basic issue information
@@ -465,6 +471,18 @@ func FormatJiraIssue(issue *models.IssueScheme) string {
 		}
 	}
 
+	// Custom fields, multi-line values (e.g. ADF rich text) get their own block like Description
+	if len(customFields) > 0 {
+		sb.WriteString("Custom Fields:\n")
+		for _, field := range customFields {
+			if strings.Contains(field.Value, "\n") {
+				sb.WriteString(fmt.Sprintf("- %s:\n%s\n", field.Name, field.Value))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s: %s\n", field.Name, field.Value))
+			}
+		}
+	}
+
 	// Available Transitions
 	if len(issue.Transitions) > 0 {
 		sb.WriteString("\nAvailable Transitions:\n")
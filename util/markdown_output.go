@@ -0,0 +1,211 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// RenderMarkdown renders a tool's structured output as a markdown bullet list.
+// Fields are labelled from their json tags and kept in declaration order, nested objects
+// become nested lists and lists of objects become numbered items. Empty values are
+// skipped, as are zero numbers and booleans whose json tag has omitempty, so the
+// markdown shows the same values as the JSON form.
+func RenderMarkdown(output interface{}) string {
+	var sb strings.Builder
+	writeMarkdownFields(&sb, reflect.ValueOf(output), "")
+	return sb.String()
+}
+
+type markdownField struct {
+	label     string
+	value     reflect.Value
+	omitEmpty bool
+}
+
+func writeMarkdownFields(sb *strings.Builder, value reflect.Value, indent string) {
+	for _, field := range markdownFields(value) {
+		writeMarkdownField(sb, field, indent+"- ")
+	}
+}
+
+// writeMarkdownField writes one labelled value, prefix holds the indentation and list marker
+func writeMarkdownField(sb *strings.Builder, field markdownField, prefix string) {
+	value := indirect(field.value)
+	if isEmptyMarkdownValue(value, field.omitEmpty) {
+		return
+	}
+
+	childIndent := strings.Repeat(" ", len(prefix))
+
+	switch value.Kind() {
+	case reflect.Struct, reflect.Map:
+		sb.WriteString(fmt.Sprintf("%s**%s**:\n", prefix, field.label))
+		writeMarkdownFields(sb, value, childIndent)
+	case reflect.Slice, reflect.Array:
+		if !isObjectList(value) {
+			var items []string
+			for i := 0; i < value.Len(); i++ {
+				items = append(items, markdownScalar(indirect(value.Index(i))))
+			}
+			sb.WriteString(fmt.Sprintf("%s**%s**: %s\n", prefix, field.label, strings.Join(items, ", ")))
+			return
+		}
+
+		sb.WriteString(fmt.Sprintf("%s**%s**:\n", prefix, field.label))
+		for i := 0; i < value.Len(); i++ {
+			writeMarkdownItem(sb, indirect(value.Index(i)), fmt.Sprintf("%s%d. ", childIndent, i+1))
+		}
+	default:
+		text := markdownScalar(value)
+		if strings.Contains(text, "\n") {
+			sb.WriteString(fmt.Sprintf("%s**%s**:\n", prefix, field.label))
+			for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+				sb.WriteString(childIndent + "  " + line + "\n")
+			}
+			return
+		}
+		sb.WriteString(fmt.Sprintf("%s**%s**: %s\n", prefix, field.label, text))
+	}
+}
+
+// writeMarkdownItem writes a list item, its first field shares the line with the item number
+func writeMarkdownItem(sb *strings.Builder, item reflect.Value, prefix string) {
+	fields := markdownFields(item)
+	childIndent := strings.Repeat(" ", len(prefix))
+
+	first := true
+	for _, field := range fields {
+		if isEmptyMarkdownValue(indirect(field.value), field.omitEmpty) {
+			continue
+		}
+		if first {
+			writeMarkdownField(sb, field, prefix)
+			first = false
+			continue
+		}
+		writeMarkdownField(sb, field, childIndent+"- ")
+	}
+}
+
+func markdownFields(value reflect.Value) []markdownField {
+	value = indirect(value)
+
+	var fields []markdownField
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			structField := value.Type().Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+
+			name, options, _ := strings.Cut(structField.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if structField.Anonymous && name == "" {
+				fields = append(fields, markdownFields(value.Field(i))...)
+				continue
+			}
+			if name == "" {
+				name = structField.Name
+			}
+
+			fields = append(fields, markdownField{
+				label:     markdownLabel(name),
+				value:     value.Field(i),
+				omitEmpty: strings.Contains(options, "omitempty"),
+			})
+		}
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, key := range keys {
+			// Map keys are data (such as custom field names), so they are kept as is
+			fields = append(fields, markdownField{label: fmt.Sprint(key), value: value.MapIndex(key), omitEmpty: true})
+		}
+	}
+	return fields
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func isEmptyMarkdownValue(value reflect.Value, omitEmpty bool) bool {
+	if !value.IsValid() {
+		return true
+	}
+
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return value.Len() == 0
+	case reflect.Struct:
+		for _, field := range markdownFields(value) {
+			if !isEmptyMarkdownValue(indirect(field.value), field.omitEmpty) {
+				return false
+			}
+		}
+		return true
+	}
+	return omitEmpty && value.IsZero()
+}
+
+func isObjectList(value reflect.Value) bool {
+	for i := 0; i < value.Len(); i++ {
+		switch indirect(value.Index(i)).Kind() {
+		case reflect.Struct, reflect.Map:
+			return true
+		}
+	}
+	return false
+}
+
+func markdownScalar(value reflect.Value) string {
+	if !value.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// markdownLabel turns a json name such as fix_versions or pullRequests into "Fix Versions" or "Pull Requests"
+func markdownLabel(name string) string {
+	var words []string
+	var word []rune
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '-':
+			words, word = appendLabelWord(words, word), nil
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			words, word = appendLabelWord(words, word), nil
+		}
+		word = append(word, r)
+	}
+	words = appendLabelWord(words, word)
+	return strings.Join(words, " ")
+}
+
+func appendLabelWord(words []string, word []rune) []string {
+	if len(word) == 0 {
+		return words
+	}
+
+	switch lower := strings.ToLower(string(word)); lower {
+	case "id", "url", "jql", "adf":
+		return append(words, strings.ToUpper(lower))
+	default:
+		runes := []rune(lower)
+		runes[0] = unicode.ToUpper(runes[0])
+		return append(words, string(runes))
+	}
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+type markdownUser struct {
+	AccountID   string `json:"account_id"`
+	DisplayName string `json:"display_name"`
+}
+
+type markdownLink struct {
+	Key    string `json:"key"`
+	Status string `json:"status,omitempty"`
+}
+
+type markdownIssue struct {
+	Key          string            `json:"key"`
+	Description  string            `json:"description,omitempty"`
+	Assignee     *markdownUser     `json:"assignee,omitempty"`
+	Reporter     *markdownUser     `json:"reporter,omitempty"`
+	FixVersions  []string          `json:"fix_versions,omitempty"`
+	Links        []markdownLink    `json:"links,omitempty"`
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+	Released     bool              `json:"released"`
+	StoryPoints  int               `json:"story_points,omitempty"`
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	output := RenderMarkdown(markdownIssue{
+		Key:         "PROJ-1",
+		Description: "First line\nSecond line",
+		Assignee:    &markdownUser{AccountID: "abc", DisplayName: "Jane"},
+		FixVersions: []string{"1.0", "1.1"},
+		Links: []markdownLink{
+			{Key: "PROJ-2", Status: "Done"},
+			{Key: "PROJ-3"},
+		},
+		CustomFields: map[string]string{"Team": "Platform"},
+	})
+
+	want := "- **Key**: PROJ-1\n" +
+		"- **Description**:\n" +
+		"    First line\n" +
+		"    Second line\n" +
+		"- **Assignee**:\n" +
+		"  - **Account ID**: abc\n" +
+		"  - **Display Name**: Jane\n" +
+		"- **Fix Versions**: 1.0, 1.1\n" +
+		"- **Links**:\n" +
+		"  1. **Key**: PROJ-2\n" +
+		"     - **Status**: Done\n" +
+		"  2. **Key**: PROJ-3\n" +
+		"- **Custom Fields**:\n" +
+		"  - **Team**: Platform\n" +
+		"- **Released**: false\n"
+
+	if output != want {
+		t.Errorf("RenderMarkdown() =\n%s\nwant\n%s", output, want)
+	}
+
+	if strings.Contains(output, "Reporter") || strings.Contains(output, "Story Points") {
+		t.Errorf("empty values should be skipped:\n%s", output)
+	}
+}
+
+func TestMarkdownLabel(t *testing.T) {
+	tests := map[string]string{
+		"fix_versions":    "Fix Versions",
+		"pullRequests":    "Pull Requests",
+		"repositoryId":    "Repository ID",
+		"next_page_token": "Next Page Token",
+		"url":             "URL",
+	}
+	for name, want := range tests {
+		if got := markdownLabel(name); got != want {
+			t.Errorf("markdownLabel(%q) = %q, want %q", name, got, want)
+		}
+	}
+}